@@ -0,0 +1,214 @@
+// Package audit records admin actions and sensitive account changes in the
+// append-only audit_log table. Entries are written in the transaction of the
+// change they describe, and each holds the hash of the previous entry, so
+// altering or deleting entries after the fact is detected by Verify.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/jackc/pgtype"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+)
+
+// Actions recorded. Moderation actions are recorded as ActionModerate, a
+// dot, and the action, ie. "moderate.hide".
+const (
+	ActionModerate    = "moderate"
+	ActionSetEmail    = "account.set_email"
+	ActionVerifyEmail = "account.verify_email"
+	ActionSetDigest   = "account.set_digest"
+	ActionUnsubscribe = "account.unsubscribe"
+
+	ActionBlocklistAdd    = "blocklist.add"
+	ActionBlocklistRemove = "blocklist.remove"
+)
+
+// Types of targets, besides the moderation targets. Accounts are keyed by
+// address, and blocklist entries by id.
+const (
+	TargetAccount   = "account"
+	TargetBlocklist = "blocklist"
+)
+
+// Entry is a change to record. Before and After are snapshots of the target,
+// marshalled to JSON, and are left empty when nil.
+type Entry struct {
+	Action     string
+	TargetType string
+	TargetID   string
+	Before     interface{}
+	After      interface{}
+}
+
+type requestCtxKeyType struct{}
+
+var requestCtxKey = requestCtxKeyType{}
+
+type request struct {
+	id string
+	ip string
+}
+
+// Middleware tags the request context with the request id and client ip
+// recorded with entries. It must run after middleware.RequestID and
+// middleware.RealIP.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := r.RemoteAddr
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+		ctx := context.WithValue(r.Context(), requestCtxKey, request{
+			id: middleware.GetReqID(r.Context()),
+			ip: ip,
+		})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Record appends an entry made by actor to the audit log, with q the queries
+// of the transaction making the change. The transaction holds a lock on the
+// log until it ends, so entries are chained in commit order.
+func Record(ctx context.Context, q *sqlc.Queries, actor string, entry Entry) error {
+	before, err := snapshot(entry.Before)
+	if err != nil {
+		return err
+	}
+	after, err := snapshot(entry.After)
+	if err != nil {
+		return err
+	}
+	req, _ := ctx.Value(requestCtxKey).(request)
+
+	if err := q.LockAuditLog(ctx); err != nil {
+		return err
+	}
+	prev, err := q.GetLastAuditHash(ctx)
+	if err != nil && !errors.Is(err, data.ErrNoRows) {
+		return err
+	}
+
+	params := sqlc.CreateAuditEntryParams{
+		Actor:      actor,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		Before:     before,
+		After:      after,
+		RequestID:  req.id,
+		Ip:         req.ip,
+		// Postgres keeps microseconds, and the hash must match what's read
+		// back.
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+		PrevHash:  prev,
+	}
+	params.Hash, err = hash(&params)
+	if err != nil {
+		return err
+	}
+	_, err = q.CreateAuditEntry(ctx, params)
+	return err
+}
+
+// verifyBatch is the number of entries read at once by Verify.
+const verifyBatch = 500
+
+// Verify walks the audit log from its first entry, and returns the id of the
+// first entry which doesn't match its hash or the hash of the entry before
+// it, or 0 if the chain is intact.
+func Verify(ctx context.Context) (int64, error) {
+	prev := ""
+	var after int64
+	for {
+		rows, err := data.DB.ListAuditChain(ctx, sqlc.ListAuditChainParams{
+			AfterID:    after,
+			MaxEntries: verifyBatch,
+		})
+		if err != nil {
+			return 0, err
+		}
+		for i := range rows {
+			row := &rows[i]
+			h, err := hash(&sqlc.CreateAuditEntryParams{
+				Actor:      row.Actor,
+				Action:     row.Action,
+				TargetType: row.TargetType,
+				TargetID:   row.TargetID,
+				Before:     row.Before,
+				After:      row.After,
+				RequestID:  row.RequestID,
+				Ip:         row.Ip,
+				CreatedAt:  row.CreatedAt,
+				PrevHash:   row.PrevHash,
+			})
+			if err != nil || row.PrevHash != prev || row.Hash != h {
+				return row.ID, nil
+			}
+			prev = row.Hash
+			after = row.ID
+		}
+		if len(rows) < verifyBatch {
+			return 0, nil
+		}
+	}
+}
+
+// hash returns the hash of an entry: the hex sha256 of the hash of the
+// previous entry followed by the JSON of the fields of the entry.
+func hash(entry *sqlc.CreateAuditEntryParams) (string, error) {
+	fields, err := json.Marshal(struct {
+		Actor      string          `json:"actor"`
+		Action     string          `json:"action"`
+		TargetType string          `json:"targetType"`
+		TargetID   string          `json:"targetID"`
+		Before     json.RawMessage `json:"before"`
+		After      json.RawMessage `json:"after"`
+		RequestID  string          `json:"requestID"`
+		IP         string          `json:"ip"`
+		CreatedAt  string          `json:"createdAt"`
+	}{
+		Actor:      entry.Actor,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		Before:     raw(entry.Before),
+		After:      raw(entry.After),
+		RequestID:  entry.RequestID,
+		IP:         entry.Ip,
+		CreatedAt:  entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return "", fmt.Errorf("audit: invalid entry: %w", err)
+	}
+	sum := sha256.Sum256(append([]byte(entry.PrevHash), fields...))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func snapshot(v interface{}) (pgtype.JSON, error) {
+	if v == nil {
+		return pgtype.JSON{Status: pgtype.Null}, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return pgtype.JSON{}, fmt.Errorf("audit: invalid snapshot: %w", err)
+	}
+	return pgtype.JSON{Bytes: b, Status: pgtype.Present}, nil
+}
+
+func raw(j pgtype.JSON) json.RawMessage {
+	if j.Status != pgtype.Present {
+		return nil
+	}
+	return j.Bytes
+}
@@ -0,0 +1,166 @@
+// Package bus fans messages out to the subscribers of a topic across every
+// api-server replica, ie. to push live updates to the clients connected to
+// any of them.
+//
+// Delivery is best effort: messages published while a replica is
+// disconnected from the bus, or which a subscriber is too slow to take, are
+// lost. Durable state must be read back from the database.
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/config"
+	"github.com/rs/zerolog"
+)
+
+var (
+	// ErrSlowConsumer is the error of a subscription closed because its
+	// buffer was full.
+	ErrSlowConsumer = errors.New("bus: subscriber is too slow, messages dropped")
+
+	ErrClosed = errors.New("bus: subscription closed")
+)
+
+// SubscriptionBuffer is the number of messages buffered per subscription.
+var SubscriptionBuffer = 64
+
+type Message struct {
+	Topic string          `json:"t"`
+	Data  json.RawMessage `json:"d"`
+}
+
+type Bus interface {
+	// Publish sends data to the subscribers of topic on every replica.
+	Publish(ctx context.Context, topic string, data json.RawMessage) error
+
+	// Subscribe returns a subscription receiving the messages of topics
+	// published from now on.
+	Subscribe(topics ...string) *Subscription
+
+	// Run connects the bus until ctx is done.
+	Run(ctx context.Context) error
+}
+
+// New returns the bus set by the [bus] config: "postgres", the default, or
+// "memory" which only reaches the subscribers of this process.
+func New(cfg *config.Config, log zerolog.Logger) (Bus, error) {
+	switch cfg.Bus.Driver {
+	case "", "postgres":
+		return NewPostgres(log), nil
+	case "memory":
+		return NewMemory(), nil
+	default:
+		return nil, fmt.Errorf("bus: config invalid bus.driver value %q", cfg.Bus.Driver)
+	}
+}
+
+// Subscription receives the messages of its topics on C until it's closed,
+// either by Close or by the bus when the subscriber falls behind.
+type Subscription struct {
+	hub    *hub
+	topics []string
+	ch     chan *Message
+
+	mu     sync.Mutex
+	closed bool
+	err    error
+}
+
+// C returns the channel of messages, which is closed with the subscription.
+func (s *Subscription) C() <-chan *Message {
+	return s.ch
+}
+
+// Err returns why the subscription was closed, or nil while it's open.
+func (s *Subscription) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close unsubscribes from the bus.
+func (s *Subscription) Close() {
+	if s.close(ErrClosed) {
+		s.hub.remove(s)
+	}
+}
+
+func (s *Subscription) close(err error) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	s.closed = true
+	s.err = err
+	close(s.ch)
+	return true
+}
+
+// send delivers msg without blocking, closing the subscription if its buffer
+// is full.
+func (s *Subscription) send(msg *Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	select {
+	case s.ch <- msg:
+	default:
+		s.closed = true
+		s.err = ErrSlowConsumer
+		close(s.ch)
+		go s.hub.remove(s)
+	}
+}
+
+// hub tracks the subscriptions of this process by topic.
+type hub struct {
+	mu     sync.RWMutex
+	topics map[string]map[*Subscription]struct{}
+}
+
+func newHub() *hub {
+	return &hub{topics: map[string]map[*Subscription]struct{}{}}
+}
+
+func (h *hub) subscribe(topics []string) *Subscription {
+	s := &Subscription{hub: h, topics: topics, ch: make(chan *Message, SubscriptionBuffer)}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, topic := range topics {
+		subs, ok := h.topics[topic]
+		if !ok {
+			subs = map[*Subscription]struct{}{}
+			h.topics[topic] = subs
+		}
+		subs[s] = struct{}{}
+	}
+	return s
+}
+
+func (h *hub) remove(s *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, topic := range s.topics {
+		delete(h.topics[topic], s)
+		if len(h.topics[topic]) == 0 {
+			delete(h.topics, topic)
+		}
+	}
+}
+
+func (h *hub) publish(msg *Message) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	for s := range h.topics[msg.Topic] {
+		s.send(msg)
+	}
+}
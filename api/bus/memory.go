@@ -0,0 +1,29 @@
+package bus
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Memory is a bus local to the process, for tests and single replica setups.
+type Memory struct {
+	hub *hub
+}
+
+func NewMemory() *Memory {
+	return &Memory{hub: newHub()}
+}
+
+func (b *Memory) Publish(ctx context.Context, topic string, body json.RawMessage) error {
+	b.hub.publish(&Message{Topic: topic, Data: body})
+	return nil
+}
+
+func (b *Memory) Subscribe(topics ...string) *Subscription {
+	return b.hub.subscribe(topics)
+}
+
+func (b *Memory) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
@@ -0,0 +1,103 @@
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func receive(t *testing.T, sub *Subscription) *Message {
+	t.Helper()
+	select {
+	case msg, ok := <-sub.C():
+		if !ok {
+			t.Fatalf("subscription closed: %v", sub.Err())
+		}
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("no message received")
+		return nil
+	}
+}
+
+func TestMemoryPublish(t *testing.T) {
+	b := NewMemory()
+	ctx := context.Background()
+
+	posts := b.Subscribe("post:1")
+	defer posts.Close()
+	both := b.Subscribe("post:1", "user:0xabc")
+	defer both.Close()
+
+	if err := b.Publish(ctx, "user:0xabc", json.RawMessage(`{"n":1}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Publish(ctx, "post:1", json.RawMessage(`{"n":2}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Publish(ctx, "post:2", json.RawMessage(`{"n":3}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	if msg := receive(t, posts); msg.Topic != "post:1" || string(msg.Data) != `{"n":2}` {
+		t.Errorf("posts got %s %s, want post:1 {\"n\":2}", msg.Topic, msg.Data)
+	}
+	for _, want := range []string{"user:0xabc", "post:1"} {
+		if msg := receive(t, both); msg.Topic != want {
+			t.Errorf("both got %s, want %s", msg.Topic, want)
+		}
+	}
+	select {
+	case msg := <-posts.C():
+		t.Errorf("posts got unexpected %s", msg.Topic)
+	default:
+	}
+
+	posts.Close()
+	if _, ok := <-posts.C(); ok {
+		t.Error("closed subscription is still receiving")
+	}
+	if !errors.Is(posts.Err(), ErrClosed) {
+		t.Errorf("Err() = %v, want ErrClosed", posts.Err())
+	}
+	if err := b.Publish(ctx, "post:1", json.RawMessage(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	receive(t, both)
+}
+
+func TestMemorySlowConsumer(t *testing.T) {
+	b := NewMemory()
+	ctx := context.Background()
+
+	slow := b.Subscribe("post:1")
+	defer slow.Close()
+
+	for i := 0; i <= SubscriptionBuffer; i++ {
+		if err := b.Publish(ctx, "post:1", json.RawMessage(`{}`)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n := 0
+	for range slow.C() {
+		n++
+	}
+	if n != SubscriptionBuffer {
+		t.Errorf("received %d messages, want the %d buffered", n, SubscriptionBuffer)
+	}
+	if !errors.Is(slow.Err(), ErrSlowConsumer) {
+		t.Errorf("Err() = %v, want ErrSlowConsumer", slow.Err())
+	}
+
+	// The subscription is dropped from the hub, so publishing no longer
+	// reaches it, and new subscriptions aren't affected.
+	fresh := b.Subscribe("post:1")
+	defer fresh.Close()
+	if err := b.Publish(ctx, "post:1", json.RawMessage(`{}`)); err != nil {
+		t.Fatal(err)
+	}
+	receive(t, fresh)
+}
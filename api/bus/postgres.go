@@ -0,0 +1,182 @@
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// channel is the Postgres notification channel of the bus.
+	channel = "bus"
+
+	// maxNotifyPayload is kept under the 8000 bytes NOTIFY limit. Larger
+	// messages are spilled to the bus_spill table.
+	maxNotifyPayload = 7500
+
+	// spillRetention is how long spilled payloads are kept for the
+	// listeners to read them.
+	spillRetention = time.Hour
+)
+
+// Postgres is a bus over Postgres LISTEN/NOTIFY. Every replica listens on a
+// dedicated connection, which is reestablished whenever it's lost.
+type Postgres struct {
+	log zerolog.Logger
+	hub *hub
+}
+
+// envelope is the NOTIFY payload, holding either the message itself or the
+// id of its spilled payload.
+type envelope struct {
+	Topic string          `json:"t"`
+	Data  json.RawMessage `json:"d,omitempty"`
+	Spill int64           `json:"s,omitempty"`
+}
+
+func NewPostgres(log zerolog.Logger) *Postgres {
+	return &Postgres{
+		log: log.With().Str("ps", "bus").Logger(),
+		hub: newHub(),
+	}
+}
+
+func (b *Postgres) Publish(ctx context.Context, topic string, body json.RawMessage) error {
+	if data.DB == nil {
+		return data.ErrNotConnected
+	}
+	// NOTIFY fails on read replicas.
+	ctx = data.WithPrimary(ctx)
+
+	payload, err := json.Marshal(envelope{Topic: topic, Data: body})
+	if err != nil {
+		return err
+	}
+	if len(payload) > maxNotifyPayload {
+		msg, _ := json.Marshal(Message{Topic: topic, Data: body})
+		id, err := data.DB.InsertBusSpill(ctx, pgtype.JSONB{Bytes: msg, Status: pgtype.Present})
+		if err != nil {
+			return fmt.Errorf("bus: failed to spill message: %w", err)
+		}
+		payload, _ = json.Marshal(envelope{Topic: topic, Spill: id})
+	}
+
+	return data.DB.Notify(ctx, sqlc.NotifyParams{Channel: channel, Payload: string(payload)})
+}
+
+func (b *Postgres) Subscribe(topics ...string) *Subscription {
+	return b.hub.subscribe(topics)
+}
+
+// Run listens for messages until ctx is done, reconnecting with backoff.
+func (b *Postgres) Run(ctx context.Context) error {
+	go b.runPruner(ctx)
+
+	failures := 0
+	for {
+		listening, err := b.listen(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if listening {
+			failures = 0
+		}
+
+		delay := retryDelay(failures)
+		failures++
+		if data.IsConnected() {
+			b.log.Warn().Err(err).Int("attempt", failures).Dur("retryIn", delay).Msg("bus listener disconnected, reconnecting..")
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// listen connects and dispatches notifications until the connection fails,
+// and reports whether it got to listen at all.
+func (b *Postgres) listen(ctx context.Context) (bool, error) {
+	conn, err := data.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close(context.Background())
+
+	_, err = conn.Exec(ctx, "LISTEN "+channel)
+	if err != nil {
+		return false, err
+	}
+	b.log.Info().Msg("bus listener connected")
+
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return true, err
+		}
+		msg, err := b.decode(ctx, n.Payload)
+		if err != nil {
+			b.log.Error().Err(err).Msg("invalid bus notification")
+			continue
+		}
+		b.hub.publish(msg)
+	}
+}
+
+func (b *Postgres) decode(ctx context.Context, payload string) (*Message, error) {
+	var env envelope
+	if err := json.Unmarshal([]byte(payload), &env); err != nil {
+		return nil, err
+	}
+	if env.Spill == 0 {
+		return &Message{Topic: env.Topic, Data: env.Data}, nil
+	}
+
+	spilled, err := data.DB.GetBusSpill(data.WithPrimary(ctx), env.Spill)
+	if err != nil {
+		return nil, fmt.Errorf("bus: failed to read spilled message %d: %w", env.Spill, err)
+	}
+	var msg Message
+	if err := json.Unmarshal(spilled.Bytes, &msg); err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// runPruner deletes the spilled payloads every listener has had time to read.
+func (b *Postgres) runPruner(ctx context.Context) {
+	ticker := time.NewTicker(10 * time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if !data.IsConnected() {
+			continue
+		}
+		_, err := data.DB.PruneBusSpill(ctx, time.Now().Add(-spillRetention))
+		if err != nil && ctx.Err() == nil {
+			b.log.Error().Err(err).Msg("failed to prune spilled bus messages")
+		}
+	}
+}
+
+// retryDelay doubles from 500ms up to 30s.
+func retryDelay(attempt int) time.Duration {
+	if attempt >= 6 {
+		return 30 * time.Second
+	}
+	return 500 * time.Millisecond << uint(attempt)
+}
@@ -0,0 +1,207 @@
+// Package chain reads on-chain state from an Ethereum node over JSON-RPC.
+package chain
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/config"
+	"github.com/nfteseum/nfteseum-learning-project/api/tracing"
+)
+
+// ErrNotConfigured is returned by the reader when chain.node_url isn't set.
+var ErrNotConfigured = errors.New("chain: node url is not configured")
+
+// balanceOfSelector is the selector of balanceOf(address), shared by the
+// ERC-20 and ERC-721 interfaces.
+const balanceOfSelector = "70a08231"
+
+// tokenURISelector is the selector of tokenURI(uint256), of the ERC-721
+// metadata extension.
+const tokenURISelector = "c87b56dd"
+
+var addressRe = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// IsAddress reports whether s is a hex encoded address.
+func IsAddress(s string) bool {
+	return addressRe.MatchString(s)
+}
+
+type Reader struct {
+	url    string
+	client *http.Client
+	nextID uint64
+}
+
+func NewReader(cfg *config.Config) *Reader {
+	return &Reader{
+		url: cfg.Chain.NodeURL,
+		client: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: tracing.Transport(nil),
+		},
+	}
+}
+
+// IsConfigured reports whether the reader has a node to read from.
+func (r *Reader) IsConfigured() bool {
+	return r.url != ""
+}
+
+// BalanceOf returns the number of tokens of contract held by owner, read with
+// the balanceOf(address) method of ERC-721 and ERC-20 contracts.
+func (r *Reader) BalanceOf(ctx context.Context, contract, owner string) (*big.Int, error) {
+	if !IsAddress(contract) || !IsAddress(owner) {
+		return nil, fmt.Errorf("chain: invalid address")
+	}
+
+	input := "0x" + balanceOfSelector + strings.Repeat("0", 24) + strings.ToLower(owner[2:])
+	call := map[string]string{"to": strings.ToLower(contract), "data": input}
+
+	var result string
+	if err := r.call(ctx, "eth_call", []interface{}{call, "latest"}, &result); err != nil {
+		return nil, err
+	}
+
+	out, err := hex.DecodeString(strings.TrimPrefix(result, "0x"))
+	if err != nil || len(out) < 32 {
+		return nil, fmt.Errorf("chain: unexpected balanceOf result %q", result)
+	}
+	return new(big.Int).SetBytes(out[:32]), nil
+}
+
+// TokenURI returns the metadata uri of a token, read with the
+// tokenURI(uint256) method of ERC-721 contracts.
+func (r *Reader) TokenURI(ctx context.Context, contract string, tokenID *big.Int) (string, error) {
+	if !IsAddress(contract) {
+		return "", fmt.Errorf("chain: invalid address")
+	}
+	if tokenID.Sign() < 0 || tokenID.BitLen() > 256 {
+		return "", fmt.Errorf("chain: invalid token id")
+	}
+
+	input := "0x" + tokenURISelector + fmt.Sprintf("%064x", tokenID)
+	call := map[string]string{"to": strings.ToLower(contract), "data": input}
+
+	var result string
+	if err := r.call(ctx, "eth_call", []interface{}{call, "latest"}, &result); err != nil {
+		return "", err
+	}
+
+	out, err := hex.DecodeString(strings.TrimPrefix(result, "0x"))
+	if err != nil {
+		return "", fmt.Errorf("chain: unexpected tokenURI result %q", result)
+	}
+	uri, ok := decodeString(out)
+	if !ok {
+		return "", fmt.Errorf("chain: unexpected tokenURI result %q", result)
+	}
+	return uri, nil
+}
+
+// TransactionCount returns the number of transactions sent from account,
+// its nonce.
+func (r *Reader) TransactionCount(ctx context.Context, account string) (uint64, error) {
+	if !IsAddress(account) {
+		return 0, fmt.Errorf("chain: invalid address")
+	}
+
+	var result string
+	if err := r.call(ctx, "eth_getTransactionCount", []interface{}{strings.ToLower(account), "latest"}, &result); err != nil {
+		return 0, err
+	}
+	n, ok := new(big.Int).SetString(strings.TrimPrefix(result, "0x"), 16)
+	if !ok || !n.IsUint64() {
+		return 0, fmt.Errorf("chain: unexpected eth_getTransactionCount result %q", result)
+	}
+	return n.Uint64(), nil
+}
+
+// decodeString decodes an ABI encoded string return value: the offset of
+// the string, then its length and its bytes.
+func decodeString(out []byte) (string, bool) {
+	if len(out) < 32 {
+		return "", false
+	}
+	offset := new(big.Int).SetBytes(out[:32])
+	if !offset.IsInt64() || offset.Int64() > int64(len(out))-32 {
+		return "", false
+	}
+	start := int(offset.Int64())
+	length := new(big.Int).SetBytes(out[start : start+32])
+	if !length.IsInt64() || length.Int64() > int64(len(out)-start-32) {
+		return "", false
+	}
+	return string(out[start+32 : start+32+int(length.Int64())]), true
+}
+
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("chain: node error %d: %s", e.Code, e.Message)
+}
+
+func (r *Reader) call(ctx context.Context, method string, params []interface{}, result interface{}) error {
+	if !r.IsConfigured() {
+		return ErrNotConfigured
+	}
+
+	body, err := json.Marshal(rpcRequest{
+		JSONRPC: "2.0",
+		ID:      atomic.AddUint64(&r.nextID, 1),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("chain: %s failed: %w", method, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("chain: %s failed with status %d", method, resp.StatusCode)
+	}
+
+	var res rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return fmt.Errorf("chain: invalid %s response: %w", method, err)
+	}
+	if res.Error != nil {
+		return res.Error
+	}
+	return json.Unmarshal(res.Result, result)
+}
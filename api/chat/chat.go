@@ -0,0 +1,237 @@
+// Package chat serves a chat room per collection over WebSocket. Messages are
+// persisted and fanned out to the members of the room on every replica
+// through the bus, while presence is tracked in the database so a room lists
+// its members across replicas.
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/nfteseum/nfteseum-learning-project/api/bus"
+	"github.com/nfteseum/nfteseum-learning-project/api/chain"
+	"github.com/nfteseum/nfteseum-learning-project/api/config"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/rs/zerolog"
+)
+
+const (
+	// maxMessageLength caps the content of a message, in characters.
+	maxMessageLength = 1000
+
+	// historyLimit is the number of messages sent on join, and per history
+	// request.
+	historyLimit = 50
+
+	// presenceTTL is how long a connection counts as present without a
+	// heartbeat, so the connections of crashed replicas expire.
+	presenceTTL = 90 * time.Second
+)
+
+type Hub struct {
+	log         zerolog.Logger
+	bus         bus.Bus
+	chain       *chain.Reader
+	holdersOnly bool
+	limiter     *limiter
+	upgrader    websocket.Upgrader
+
+	mu      sync.Mutex
+	clients map[*client]struct{}
+	closing chan struct{}
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+func New(cfg *config.Config, log zerolog.Logger, b bus.Bus, reader *chain.Reader) (*Hub, error) {
+	if cfg.Chat.HoldersOnly && !reader.IsConfigured() {
+		return nil, fmt.Errorf("chat: config chat.holders_only requires chain.node_url")
+	}
+	if cfg.Chat.MessagesPerMinute < 0 {
+		return nil, fmt.Errorf("chat: config invalid chat.messages_per_minute value %d", cfg.Chat.MessagesPerMinute)
+	}
+	perMinute := cfg.Chat.MessagesPerMinute
+	if perMinute == 0 {
+		perMinute = 20
+	}
+
+	h := &Hub{
+		log:         log.With().Str("ps", "chat").Logger(),
+		bus:         b,
+		chain:       reader,
+		holdersOnly: cfg.Chat.HoldersOnly,
+		limiter:     newLimiter(perMinute),
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+		},
+		clients: map[*client]struct{}{},
+		closing: make(chan struct{}),
+	}
+	// For local dev mode, allow all origins
+	if cfg.Mode == config.DevelopmentMode {
+		h.upgrader.CheckOrigin = func(r *http.Request) bool { return true }
+	}
+	return h, nil
+}
+
+// Message is a chat message as sent to the clients.
+type Message struct {
+	ID        int64     `json:"id"`
+	Contract  string    `json:"contract"`
+	Author    string    `json:"author"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Frame is a websocket message, in either direction.
+//
+// Clients send "message" with Content, "typing", and "history" with Before
+// to page back through older messages. The server sends "message",
+// "history" with Messages, "presence" with the Accounts in the room on join,
+// "joined", "left" and "typing" with the Account, and "error".
+type Frame struct {
+	Type     string     `json:"type"`
+	Content  string     `json:"content,omitempty"`
+	Before   int64      `json:"before,omitempty"`
+	Message  *Message   `json:"message,omitempty"`
+	Messages []*Message `json:"messages,omitempty"`
+	Account  string     `json:"account,omitempty"`
+	Accounts []string   `json:"accounts,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// Topic is the bus topic of the room of contract.
+func Topic(contract string) string {
+	return "chat:" + contract
+}
+
+// Serve upgrades the request to a websocket joining account to the room of
+// contract, and serves it until either side closes it.
+func (h *Hub) Serve(w http.ResponseWriter, r *http.Request, account, contract string) {
+	contract = strings.ToLower(contract)
+	if !chain.IsAddress(contract) {
+		http.Error(w, "invalid contract address", http.StatusBadRequest)
+		return
+	}
+	if h.isClosing() {
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+
+	if h.holdersOnly {
+		balance, err := h.chain.BalanceOf(r.Context(), contract, account)
+		if err != nil {
+			h.log.Warn().Err(err).Str("contract", contract).Msg("failed to read token balance")
+			http.Error(w, "failed to verify token ownership", http.StatusBadGateway)
+			return
+		}
+		if balance.Sign() <= 0 {
+			http.Error(w, "the room is reserved to token holders", http.StatusForbidden)
+			return
+		}
+	}
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has replied with the error already.
+		return
+	}
+
+	c := newClient(h, conn, account, contract)
+	if !h.add(c) {
+		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"))
+		conn.Close()
+		return
+	}
+	defer h.remove(c)
+
+	c.serve(r.Context())
+}
+
+func (h *Hub) add(c *client) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return false
+	}
+	h.clients[c] = struct{}{}
+	h.wg.Add(1)
+	return true
+}
+
+func (h *Hub) remove(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.clients, c)
+	h.wg.Done()
+}
+
+func (h *Hub) isClosing() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.closed
+}
+
+// Close closes every socket with a going away status, so clients reconnect to
+// another replica, and waits for them to be done until timeoutCtx is.
+func (h *Hub) Close(timeoutCtx context.Context) {
+	h.mu.Lock()
+	if !h.closed {
+		h.closed = true
+		close(h.closing)
+	}
+	n := len(h.clients)
+	h.mu.Unlock()
+
+	if n > 0 {
+		h.log.Info().Msgf("closing %d chat connections..", n)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-timeoutCtx.Done():
+		h.log.Warn().Msg("timed out closing chat connections")
+	}
+}
+
+// RunPruner deletes the presence of the connections which stopped sending
+// heartbeats, ie. those of crashed replicas, until ctx is done.
+func (h *Hub) RunPruner(ctx context.Context) error {
+	ticker := time.NewTicker(presenceTTL)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if !data.IsConnected() {
+			continue
+		}
+		_, err := data.DB.PruneChatPresence(ctx, time.Now().Add(-presenceTTL))
+		if err != nil && ctx.Err() == nil {
+			h.log.Error().Err(err).Msg("failed to prune chat presence")
+		}
+	}
+}
+
+// publish sends f to the members of the room of contract on every replica.
+func (h *Hub) publish(ctx context.Context, contract string, f *Frame) error {
+	body, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	return h.bus.Publish(ctx, Topic(contract), body)
+}
@@ -0,0 +1,331 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/nfteseum/nfteseum-learning-project/api/bus"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/nfteseum/nfteseum-learning-project/api/moderation"
+)
+
+const (
+	// writeWait is the time allowed to write a frame.
+	writeWait = 10 * time.Second
+
+	// pongWait is the time allowed to read the next pong from the client.
+	pongWait = 60 * time.Second
+
+	// pingPeriod must be shorter than pongWait. Presence is refreshed on
+	// the same period.
+	pingPeriod = 30 * time.Second
+
+	// closeGrace is how long the client has to answer a close frame.
+	closeGrace = time.Second
+
+	// maxFrameSize caps the frames read from the client, in bytes.
+	maxFrameSize = 8192
+
+	// typingInterval throttles the typing indicators of a connection.
+	typingInterval = 3 * time.Second
+)
+
+type client struct {
+	hub      *Hub
+	conn     *websocket.Conn
+	id       string
+	account  string
+	contract string
+
+	send chan *Frame
+
+	// done is closed once the read loop is over, and written once the write
+	// loop is.
+	done    chan struct{}
+	written chan struct{}
+
+	lastTyping time.Time
+}
+
+func newClient(h *Hub, conn *websocket.Conn, account, contract string) *client {
+	return &client{
+		hub:      h,
+		conn:     conn,
+		id:       uuid.NewString(),
+		account:  account,
+		contract: contract,
+		send:     make(chan *Frame, 16),
+		done:     make(chan struct{}),
+		written:  make(chan struct{}),
+	}
+}
+
+// serve joins the room, and reads and writes frames until the connection is
+// closed, then leaves the room.
+func (c *client) serve(ctx context.Context) {
+	log := c.hub.log.With().Str("account", c.account).Str("contract", c.contract).Logger()
+
+	// Subscribe before loading the history, so no message falls in between.
+	sub := c.hub.bus.Subscribe(Topic(c.contract))
+	defer sub.Close()
+
+	go func() {
+		defer close(c.written)
+		c.writeLoop(ctx, sub)
+	}()
+
+	if err := c.join(ctx); err != nil {
+		log.Warn().Err(err).Msg("failed to join chat room")
+		c.sendError("failed to join the room")
+	}
+
+	c.readLoop(ctx)
+	close(c.done)
+	<-c.written
+
+	// The request context may be done already, leave the room regardless.
+	leaveCtx, cancel := context.WithTimeout(context.Background(), writeWait)
+	defer cancel()
+	if err := c.leave(leaveCtx); err != nil {
+		log.Warn().Err(err).Msg("failed to leave chat room")
+	}
+}
+
+func (c *client) join(ctx context.Context) error {
+	err := data.DB.TouchChatPresence(ctx, sqlc.TouchChatPresenceParams{
+		ContractAddr: c.contract,
+		Account:      c.account,
+		ConnID:       c.id,
+	})
+	if err != nil {
+		return err
+	}
+	accounts, err := data.DB.ListChatPresence(ctx, sqlc.ListChatPresenceParams{
+		ContractAddr: c.contract,
+		SeenAfter:    time.Now().Add(-presenceTTL),
+	})
+	if err != nil {
+		return err
+	}
+	c.sendFrame(&Frame{Type: "presence", Accounts: accounts})
+
+	if err := c.sendHistory(ctx, 0); err != nil {
+		return err
+	}
+	return c.hub.publish(ctx, c.contract, &Frame{Type: "joined", Account: c.account})
+}
+
+func (c *client) leave(ctx context.Context) error {
+	err := data.DB.DeleteChatPresence(ctx, sqlc.DeleteChatPresenceParams{
+		ContractAddr: c.contract,
+		Account:      c.account,
+		ConnID:       c.id,
+	})
+	if err != nil {
+		return err
+	}
+	return c.hub.publish(ctx, c.contract, &Frame{Type: "left", Account: c.account})
+}
+
+func (c *client) readLoop(ctx context.Context) {
+	c.conn.SetReadLimit(maxFrameSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, msg, err := c.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var f Frame
+		if err := json.Unmarshal(msg, &f); err != nil {
+			c.sendError("invalid frame")
+			continue
+		}
+		c.handle(ctx, &f)
+	}
+}
+
+func (c *client) handle(ctx context.Context, f *Frame) {
+	switch f.Type {
+	case "message":
+		content := strings.TrimSpace(f.Content)
+		if content == "" || utf8.RuneCountInString(content) > maxMessageLength {
+			c.sendError("messages must be 1 to 1000 characters long")
+			return
+		}
+		if !c.hub.limiter.allow(c.account) {
+			c.sendError("too many messages, slow down")
+			return
+		}
+		suspended, err := c.suspended(ctx)
+		if err != nil {
+			c.hub.log.Error().Err(err).Str("account", c.account).Msg("failed to check suspension")
+			c.sendError("failed to send the message")
+			return
+		}
+		if suspended {
+			c.sendError("your account is suspended")
+			return
+		}
+		row, err := data.DB.CreateChatMessage(ctx, sqlc.CreateChatMessageParams{
+			ContractAddr: c.contract,
+			Author:       c.account,
+			Content:      content,
+		})
+		if err != nil {
+			c.hub.log.Error().Err(err).Str("account", c.account).Msg("failed to save chat message")
+			c.sendError("failed to send the message")
+			return
+		}
+		// The message is saved, members who miss it get it from the history.
+		err = c.hub.publish(ctx, c.contract, &Frame{Type: "message", Message: fromRow(&row)})
+		if err != nil {
+			c.hub.log.Warn().Err(err).Msg("failed to publish chat message")
+		}
+
+	case "typing":
+		if time.Since(c.lastTyping) < typingInterval {
+			return
+		}
+		c.lastTyping = time.Now()
+		c.hub.publish(ctx, c.contract, &Frame{Type: "typing", Account: c.account})
+
+	case "history":
+		if err := c.sendHistory(ctx, f.Before); err != nil {
+			c.hub.log.Error().Err(err).Msg("failed to list chat messages")
+			c.sendError("failed to load the history")
+		}
+
+	default:
+		c.sendError("unknown frame type")
+	}
+}
+
+// suspended reports whether the account of the client is suspended, which
+// makes it read-only. It's checked on every message, as suspensions may
+// start or end while connected.
+func (c *client) suspended(ctx context.Context) (bool, error) {
+	user, err := data.DB.GetUser(ctx, c.account)
+	if errors.Is(err, data.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return moderation.Suspended(&user, time.Now().UTC()), nil
+}
+
+// sendHistory sends the messages before the message id before, or the
+// latest ones if it's zero, newest first.
+func (c *client) sendHistory(ctx context.Context, before int64) error {
+	if before <= 0 {
+		before = math.MaxInt64
+	}
+	rows, err := data.DB.ListChatMessages(ctx, sqlc.ListChatMessagesParams{
+		ContractAddr: c.contract,
+		BeforeID:     before,
+		MaxMessages:  historyLimit,
+	})
+	if err != nil {
+		return err
+	}
+	msgs := make([]*Message, len(rows))
+	for i := range rows {
+		msgs[i] = fromRow(&rows[i])
+	}
+	c.sendFrame(&Frame{Type: "history", Messages: msgs})
+	return nil
+}
+
+func (c *client) sendError(msg string) {
+	c.sendFrame(&Frame{Type: "error", Error: msg})
+}
+
+// sendFrame queues f for the write loop, unless the connection is closing.
+func (c *client) sendFrame(f *Frame) {
+	select {
+	case c.send <- f:
+	case <-c.written:
+	}
+}
+
+// writeLoop is the only writer of the connection. It closes the connection
+// when the client goes away, falls behind, or the hub closes.
+func (c *client) writeLoop(ctx context.Context, sub *bus.Subscription) {
+	defer c.conn.Close()
+
+	ping := time.NewTicker(pingPeriod)
+	defer ping.Stop()
+
+	for {
+		var err error
+		select {
+		case <-c.done:
+			return
+
+		case <-c.hub.closing:
+			c.close(websocket.CloseGoingAway, "server shutting down")
+			return
+
+		case msg, ok := <-sub.C():
+			if !ok {
+				c.close(websocket.CloseTryAgainLater, "connection fell behind")
+				return
+			}
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			err = c.conn.WriteMessage(websocket.TextMessage, msg.Data)
+
+		case f := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			err = c.conn.WriteJSON(f)
+
+		case <-ping.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			err = c.conn.WriteMessage(websocket.PingMessage, nil)
+			if err == nil {
+				err := data.DB.TouchChatPresence(ctx, sqlc.TouchChatPresenceParams{
+					ContractAddr: c.contract,
+					Account:      c.account,
+					ConnID:       c.id,
+				})
+				if err != nil {
+					c.hub.log.Warn().Err(err).Msg("failed to refresh chat presence")
+				}
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// close sends a close frame and gives the client a moment to answer it,
+// which ends the read loop.
+func (c *client) close(code int, text string) {
+	c.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, text), time.Now().Add(writeWait))
+	select {
+	case <-c.done:
+	case <-time.After(closeGrace):
+	}
+}
+
+func fromRow(row *sqlc.ChatMessages) *Message {
+	return &Message{
+		ID:        row.ID,
+		Contract:  row.ContractAddr,
+		Author:    row.Author,
+		Content:   row.Content,
+		CreatedAt: row.CreatedAt,
+	}
+}
@@ -0,0 +1,67 @@
+package chat
+
+import (
+	"sync"
+	"time"
+)
+
+// limiterBurst is the number of messages an account may send at once.
+const limiterBurst = 5
+
+// limiter is a token bucket per account, shared by the connections of an
+// account on this replica.
+type limiter struct {
+	mu      sync.Mutex
+	rate    float64 // tokens per second
+	buckets map[string]*bucket
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(perMinute int) *limiter {
+	return &limiter{
+		rate:    float64(perMinute) / 60,
+		buckets: map[string]*bucket{},
+	}
+}
+
+// allow takes a token from the bucket of account, and reports whether there
+// was one left.
+func (l *limiter) allow(account string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	b, ok := l.buckets[account]
+	if !ok {
+		if len(l.buckets) >= 10000 {
+			l.prune(now)
+		}
+		b = &bucket{tokens: limiterBurst, last: now}
+		l.buckets[account] = b
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * l.rate
+	if b.tokens > limiterBurst {
+		b.tokens = limiterBurst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// prune forgets the buckets which refilled, as they're the same as new ones.
+func (l *limiter) prune(now time.Time) {
+	for account, b := range l.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*l.rate >= limiterBurst {
+			delete(l.buckets, account)
+		}
+	}
+}
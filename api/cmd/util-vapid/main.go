@@ -0,0 +1,19 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/push"
+)
+
+// Prints a new VAPID key pair for the [push] config.
+func main() {
+	public, private, err := push.GenerateVAPIDKeys()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("[push]")
+	fmt.Printf("  vapid_public_key  = %q\n", public)
+	fmt.Printf("  vapid_private_key = %q\n", private)
+}
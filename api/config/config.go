@@ -2,7 +2,9 @@ package config
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/goware/pgkit"
@@ -15,8 +17,22 @@ type Config struct {
 	Service ServiceConfig `toml:"service"`
 	Logging LoggingConfig `toml:"logging"`
 	Auth    Auth          `toml:"auth"`
+	Metrics MetricsConfig `toml:"metrics"`
+	Tracing TracingConfig `toml:"tracing"`
+	Jobs    JobsConfig    `toml:"jobs"`
+	Leader  LeaderConfig  `toml:"leader"`
+	Events  EventsConfig  `toml:"events"`
+	Bus     BusConfig     `toml:"bus"`
+	Chain   ChainConfig   `toml:"chain"`
+	Chat    ChatConfig    `toml:"chat"`
 
-	DB pgkit.Config `toml:"db"`
+	Notifications NotificationsConfig `toml:"notifications"`
+	Mail          MailConfig          `toml:"mail"`
+	Push          PushConfig          `toml:"push"`
+	Webhooks      WebhooksConfig      `toml:"webhooks"`
+	Spam          SpamConfig          `toml:"spam"`
+
+	DB DBConfig `toml:"db"`
 }
 
 type ServiceConfig struct {
@@ -26,9 +42,18 @@ type ServiceConfig struct {
 	// Listen network url for the HTTP/RPC server
 	Listen string `toml:"listen"`
 
+	// URL is the public base url of the server, used in the links sent by
+	// email, ie. "https://api.nfteseum.xyz".
+	URL string `toml:"url"`
+
 	// Mode is the operating mode of the application, one of:
 	// "development", "dev", "production" or "prod"
 	Mode string `toml:"mode"`
+
+	// ShutdownDelay is how long the server keeps serving after readiness
+	// starts failing on shutdown, giving load balancers time to drain it,
+	// ie. "5s".
+	ShutdownDelay string `toml:"shutdown_delay"`
 }
 
 type LoggingConfig struct {
@@ -37,18 +62,231 @@ type LoggingConfig struct {
 	Concise bool   `toml:"concise"`
 }
 
+type MetricsConfig struct {
+	// Enabled exposes Prometheus metrics at /metrics.
+	Enabled bool `toml:"enabled"`
+
+	// Listen is the address of a separate internal listener serving
+	// /metrics, ie. "localhost:9090". When empty, /metrics is served by the
+	// main HTTP server.
+	Listen string `toml:"listen"`
+}
+
+type TracingConfig struct {
+	// Exporter of the OpenTelemetry spans, one of "" (spans are only used
+	// for log correlation), "stdout" or "otlp".
+	Exporter string `toml:"exporter"`
+
+	// Endpoint of the OTLP/HTTP collector, ie. "localhost:4318".
+	Endpoint string `toml:"endpoint"`
+
+	// Insecure sends spans to the collector over plain http.
+	Insecure bool `toml:"insecure"`
+
+	// SampleRatio is the fraction of new traces which are sampled, between
+	// 0 and 1. Defaults to 1.
+	SampleRatio float64 `toml:"sample_ratio"`
+}
+
+type JobsConfig struct {
+	// Workers is the number of background jobs processed concurrently by
+	// this process. Defaults to 4, set to -1 to only enqueue jobs.
+	Workers int `toml:"workers"`
+
+	// PollInterval is how often idle workers check for due jobs, ie. "1s".
+	PollInterval string `toml:"poll_interval"`
+
+	// LockTimeout is how long a job may stay running before it's assumed
+	// its worker died and it's run again, unless out of attempts, ie. "30m".
+	// It must be longer than the timeout of any job handler.
+	LockTimeout string `toml:"lock_timeout"`
+}
+
+type LeaderConfig struct {
+	// Heartbeat is how often the leader checks it still holds leadership,
+	// and how often the other replicas try to take it over, ie. "5s".
+	Heartbeat string `toml:"heartbeat"`
+}
+
+type EventsConfig struct {
+	// PollInterval is how often the outbox is checked for new events when
+	// subscribers are caught up, ie. "500ms".
+	PollInterval string `toml:"poll_interval"`
+
+	// Retention is how long events are kept in the outbox, ie. "168h".
+	Retention string `toml:"retention"`
+}
+
+type BusConfig struct {
+	// Driver of the event bus, "postgres" to fan out across replicas with
+	// LISTEN/NOTIFY, or "memory" for a single replica. Defaults to
+	// "postgres".
+	Driver string `toml:"driver"`
+}
+
+type NotificationsConfig struct {
+	// Retention is how long notifications are kept after their last
+	// activity, ie. "2160h".
+	Retention string `toml:"retention"`
+}
+
+type MailConfig struct {
+	// Driver of the mail sender, "smtp", or "log" which writes the messages
+	// to the log or to Dir instead of sending them. Defaults to "log".
+	Driver string `toml:"driver"`
+
+	// From is the sender address of the mails, ie. "nfteseum
+	// <noreply@nfteseum.xyz>".
+	From string `toml:"from"`
+
+	// Dir is where the "log" driver writes the messages as .eml files.
+	Dir string `toml:"dir"`
+
+	SMTPHost     string `toml:"smtp_host"`
+	SMTPPort     int    `toml:"smtp_port"`
+	SMTPUsername string `toml:"smtp_username"`
+	SMTPPassword string `toml:"smtp_password"`
+
+	// SMTPInsecure allows sending without STARTTLS, ie. to a local relay.
+	SMTPInsecure bool `toml:"smtp_insecure"`
+}
+
+type PushConfig struct {
+	// VAPIDPublicKey and VAPIDPrivateKey identify the server to the push
+	// services, as the base64url encoded uncompressed P-256 public key and
+	// private scalar. Generate them with util-vapid. Web Push is disabled
+	// unless both are set.
+	VAPIDPublicKey  string `toml:"vapid_public_key"`
+	VAPIDPrivateKey string `toml:"vapid_private_key"`
+
+	// Subject is the contact of the server operator given to the push
+	// services, a "mailto:" or "https:" url.
+	Subject string `toml:"subject"`
+
+	// TTL is how long push services keep undelivered messages, ie. "24h".
+	TTL string `toml:"ttl"`
+}
+
+type WebhooksConfig struct {
+	// MaxFailures is the number of consecutive failed delivery attempts
+	// after which a webhook is disabled. Defaults to 20.
+	MaxFailures int `toml:"max_failures"`
+
+	// Timeout bounds a delivery attempt, ie. "10s".
+	Timeout string `toml:"timeout"`
+
+	// Retention is how long the delivery log is kept, ie. "720h".
+	Retention string `toml:"retention"`
+}
+
+type SpamConfig struct {
+	// Disabled turns off the spam filter of comments.
+	Disabled bool `toml:"disabled"`
+
+	// HoldThreshold is the spam score from which comments are held for
+	// review. Defaults to 1.
+	HoldThreshold float64 `toml:"hold_threshold"`
+
+	// NewAccountAge is the age under which accounts count as new, ie.
+	// "72h".
+	NewAccountAge string `toml:"new_account_age"`
+}
+
+type ChainConfig struct {
+	// NodeURL is the JSON-RPC endpoint of the Ethereum node used to read
+	// on-chain state, ie. token balances.
+	NodeURL string `toml:"node_url"`
+
+	// IPFSGateway is the http gateway fetching the "ipfs://" token metadata
+	// uris, ie. "https://ipfs.io".
+	IPFSGateway string `toml:"ipfs_gateway"`
+
+	// MetadataRefresh is how often the metadata of tokens, indexed for
+	// search, is fetched again, ie. "168h".
+	MetadataRefresh string `toml:"metadata_refresh"`
+}
+
+type ChatConfig struct {
+	// HoldersOnly limits the chat room of a collection to the accounts
+	// holding one of its tokens. Requires chain.node_url.
+	HoldersOnly bool `toml:"holders_only"`
+
+	// MessagesPerMinute is the number of chat messages an account may
+	// send per minute. Defaults to 20.
+	MessagesPerMinute int `toml:"messages_per_minute"`
+}
+
 type DBConfig struct {
-	Host     string `toml:"host"`
-	Database string `toml:"database"`
-	Username string `toml:"username"`
-	Password string `toml:"password"`
+	// Connection and pool sizing settings: database, host, username, password,
+	// max_conns, min_conns and conn_max_lifetime.
+	pgkit.Config
+
+	// MaxConnIdleTime is how long an idle connection is kept in the pool,
+	// ie. "30m". Defaults to 30 minutes.
+	MaxConnIdleTime string `toml:"max_conn_idle_time"`
+
+	// HealthCheckPeriod is how often idle connections in the pool are
+	// checked, ie. "1m". Defaults to one minute.
+	HealthCheckPeriod string `toml:"health_check_period"`
+
+	// ProbeInterval is how often the database is pinged to track whether it
+	// is reachable, ie. "5s".
+	ProbeInterval string `toml:"probe_interval"`
+
+	// MaxReconnectBackoff caps the delay between reconnection attempts while
+	// the database is unreachable, ie. "30s".
+	MaxReconnectBackoff string `toml:"max_reconnect_backoff"`
+
+	// SlowQueryThreshold is the duration above which queries are logged,
+	// ie. "200ms". Set to "0s" to disable.
+	SlowQueryThreshold string `toml:"slow_query_threshold"`
+
+	// QueryTimeout bounds queries which aren't made on behalf of a request
+	// with a deadline, ie. "10s".
+	QueryTimeout string `toml:"query_timeout"`
+
+	// Replicas optionally routes read-only queries to read replicas.
+	Replicas DBReplicasConfig `toml:"replicas"`
+}
+
+type DBReplicasConfig struct {
+	// Hosts of the read replicas, as "host" or "host:port". Replicas use the
+	// same database name and credentials as the primary. Leave empty to send
+	// all queries to the primary.
+	Hosts []string `toml:"hosts"`
+
+	// MaxConns is the pool size of each replica. Defaults to db.max_conns.
+	MaxConns int32 `toml:"max_conns"`
+
+	// HealthCheckInterval is how often replicas are probed, ie. "5s".
+	HealthCheckInterval string `toml:"health_check_interval"`
+
+	// MaxLag is the replication lag above which a replica is taken out of
+	// rotation, ie. "10s". Zero disables the lag check.
+	MaxLag string `toml:"max_lag"`
+
+	// ReadYourWritesWindow is how long reads from a caller are pinned to the
+	// primary after that caller wrote to the database, ie. "5s".
+	ReadYourWritesWindow string `toml:"read_your_writes_window"`
 }
 
 func (cfg *Config) DBString() string {
 	if cfg.Mode == DevelopmentMode {
-		return fmt.Sprintf("postgres://%s:%s@%s:5432/%s?sslmode=disable", cfg.DB.Username, cfg.DB.Password, cfg.DB.Host, cfg.DB.Database)
+		return fmt.Sprintf("postgres://%s:%s@%s:5432/%s?sslmode=disable&application_name=%s", cfg.DB.Username, cfg.DB.Password, cfg.DB.Host, cfg.DB.Database, cfg.Service.Name)
+	}
+	return fmt.Sprintf("postgres://%s:%s@%s:5432/%s?application_name=%s", cfg.DB.Username, cfg.DB.Password, cfg.DB.Host, cfg.DB.Database, cfg.Service.Name)
+}
+
+// ReplicaDBString returns the connection string for the replica at host,
+// which may include a port.
+func (cfg *Config) ReplicaDBString(host string) string {
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, "5432")
+	}
+	if cfg.Mode == DevelopmentMode {
+		return fmt.Sprintf("postgres://%s:%s@%s/%s?sslmode=disable&application_name=%s", cfg.DB.Username, cfg.DB.Password, host, cfg.DB.Database, cfg.Service.Name)
 	}
-	return fmt.Sprintf("postgres://%s:%s@%s:5432/%s", cfg.DB.Username, cfg.DB.Password, cfg.DB.Host, cfg.DB.Database)
+	return fmt.Sprintf("postgres://%s:%s@%s/%s?application_name=%s", cfg.DB.Username, cfg.DB.Password, host, cfg.DB.Database, cfg.Service.Name)
 }
 
 type Auth struct {
@@ -83,6 +321,19 @@ func InitConfig(cfg *Config) error {
 	cfg.Mode = mode
 	cfg.Service.Mode = mode.String()
 
+	if cfg.Service.ShutdownDelay != "" {
+		if _, err := time.ParseDuration(cfg.Service.ShutdownDelay); err != nil {
+			return fmt.Errorf("config service.shutdown_delay value is invalid: %w", err)
+		}
+	}
+
+	// Validate tracing
+	switch cfg.Tracing.Exporter {
+	case "", "stdout", "otlp":
+	default:
+		return fmt.Errorf("config tracing.exporter value is invalid, must be one of \"\", \"stdout\" or \"otlp\"")
+	}
+
 	// Validate auth
 	if cfg.Auth.JWTSecret == "" || len(cfg.Auth.JWTSecret) < 10 {
 		return fmt.Errorf("config auth.jwt_secret must be at least 10 characters long")
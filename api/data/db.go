@@ -1,22 +1,28 @@
 package data
 
 import (
-	"database/sql"
+	"context"
 	"errors"
-	"log"
+	"fmt"
 	"sync/atomic"
 	"time"
 
-	_ "github.com/jackc/pgx/v4/stdlib"
-
-	"github.com/jackc/pgx"
+	"github.com/goware/pgkit"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/nfteseum/nfteseum-learning-project/api/config"
 	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/rs/zerolog"
+	"golang.org/x/sync/errgroup"
 )
 
 var (
 	DB        *sqlc.Queries
+	pool      *pgxpool.Pool
+	dbRouter  *router
+	dbSuper   *supervisor
 	connected int32
+	logger    = zerolog.Nop()
 )
 
 func init() {
@@ -24,33 +30,122 @@ func init() {
 	time.Local = time.UTC
 }
 
-func PrepareDB(config *config.Config) (err error) {
-	tries := 5
-	// TODO: Use correct credentials
-	conn, err := sql.Open("pgx", config.DBString())
+// PrepareDB sets up the database pools. Connections are established in the
+// background by Run, which keeps reconnecting whenever the database goes away.
+func PrepareDB(config *config.Config, log zerolog.Logger) (err error) {
+	logger = log.With().Str("ps", "data").Logger()
+
+	poolCfg, err := poolConfig(config, config.DBString())
+	if err != nil {
+		return err
+	}
+
+	db, err := pgkit.ConnectWithPGX(config.Service.Name, poolCfg)
+	if err != nil {
+		return err
+	}
+	pool = db.Conn
+
+	dbRouter, err = newRouter(config, pool)
 	if err != nil {
 		return err
 	}
 
-	for tries > 0 {
-		log.Println("attempting to make a connection to the database...")
-		err = conn.Ping()
+	if config.DB.SlowQueryThreshold != "" {
+		SlowQueryThreshold, err = time.ParseDuration(config.DB.SlowQueryThreshold)
 		if err != nil {
-			tries -= 1
-			log.Println(err, "could not connect. retrying...")
-			time.Sleep(8 * time.Second)
-			continue
+			return fmt.Errorf("data: config invalid db.slow_query_threshold value: %w", err)
+		}
+	}
+	if config.DB.QueryTimeout != "" {
+		QueryTimeout, err = time.ParseDuration(config.DB.QueryTimeout)
+		if err != nil {
+			return fmt.Errorf("data: config invalid db.query_timeout value: %w", err)
 		}
-		DB = sqlc.New(conn)
-		log.Println("connection to the database established.")
-		atomic.StoreInt32(&connected, 1)
-		return nil
 	}
-	return errors.New("could not make a connection to the database.")
+
+	dbSuper = &supervisor{minBackoff: 500 * time.Millisecond}
+	dbSuper.interval, err = parseDuration(config.DB.ProbeInterval, 5*time.Second)
+	if err != nil {
+		return fmt.Errorf("data: config invalid db.probe_interval value: %w", err)
+	}
+	dbSuper.maxBackoff, err = parseDuration(config.DB.MaxReconnectBackoff, 30*time.Second)
+	if err != nil {
+		return fmt.Errorf("data: config invalid db.max_reconnect_backoff value: %w", err)
+	}
+
+	DB = sqlc.New(instrument(dbRouter))
+	return nil
+}
+
+// poolConfig builds the pgxpool settings for connString from the [db] config,
+// applying the same defaults as pgkit.Connect.
+func poolConfig(cfg *config.Config, connString string) (*pgxpool.Config, error) {
+	poolCfg, err := pgxpool.ParseConfig(connString)
+	if err != nil {
+		return nil, fmt.Errorf("data: invalid db config: %w", err)
+	}
+
+	poolCfg.MaxConns = cfg.DB.MaxConns
+	if poolCfg.MaxConns == 0 {
+		poolCfg.MaxConns = 4
+	}
+	poolCfg.MinConns = cfg.DB.MinConns
+
+	poolCfg.MaxConnLifetime, err = parseDuration(cfg.DB.ConnMaxLifetime, time.Hour)
+	if err != nil {
+		return nil, fmt.Errorf("data: config invalid db.conn_max_lifetime value: %w", err)
+	}
+	poolCfg.MaxConnIdleTime, err = parseDuration(cfg.DB.MaxConnIdleTime, 30*time.Minute)
+	if err != nil {
+		return nil, fmt.Errorf("data: config invalid db.max_conn_idle_time value: %w", err)
+	}
+	poolCfg.HealthCheckPeriod, err = parseDuration(cfg.DB.HealthCheckPeriod, time.Minute)
+	if err != nil {
+		return nil, fmt.Errorf("data: config invalid db.health_check_period value: %w", err)
+	}
+
+	// Connections are established by the supervisor, so a database which is
+	// still starting up doesn't fail the pool creation.
+	poolCfg.LazyConnect = true
+
+	if cfg.DB.Override != nil {
+		cfg.DB.Override(poolCfg.ConnConfig)
+	}
+
+	return poolCfg, nil
+}
+
+func parseDuration(s string, defaultValue time.Duration) (time.Duration, error) {
+	if s == "" {
+		return defaultValue, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// Run supervises the database connections until ctx is done: it connects to
+// the primary with exponential backoff, keeps probing it to track IsConnected,
+// and health checks the read replicas.
+func Run(ctx context.Context) error {
+	if dbRouter == nil || dbSuper == nil {
+		return errors.New("data: database not prepared")
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		return dbSuper.run(ctx)
+	})
+	g.Go(func() error {
+		return dbRouter.run(ctx)
+	})
+	return g.Wait()
 }
 
 // something
 func Disconnect() {
+	if pool != nil {
+		pool.Close()
+	}
 	DB = nil
 	atomic.StoreInt32(&connected, 0)
 }
@@ -59,4 +154,32 @@ func IsConnected() bool {
 	return atomic.LoadInt32(&connected) == 1
 }
 
+// CheckConnected is a readiness check which fails while the supervisor can't
+// reach the primary database.
+func CheckConnected(ctx context.Context) error {
+	if !IsConnected() {
+		return ErrNotConnected
+	}
+	return nil
+}
+
+// Conn opens a dedicated connection to the primary database, outside of the
+// pool, for session state like advisory locks. The caller must close it.
+func Conn(ctx context.Context) (*pgx.Conn, error) {
+	if pool == nil {
+		return nil, ErrNotConnected
+	}
+	return pgx.ConnectConfig(ctx, pool.Config().ConnConfig)
+}
+
+// PoolStats returns a snapshot of the connection pool statistics, or nil if
+// the database hasn't been prepared.
+func PoolStats() *pgxpool.Stat {
+	if pool == nil {
+		return nil
+	}
+	return pool.Stat()
+}
+
+// ErrNoRows is returned by sqlc :one queries when no row matches.
 var ErrNoRows = pgx.ErrNoRows
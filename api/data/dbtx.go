@@ -0,0 +1,206 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/nfteseum/nfteseum-learning-project/api/metrics"
+	"github.com/nfteseum/nfteseum-learning-project/api/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	// SlowQueryThreshold is the duration above which a query is logged as
+	// slow. Zero disables slow query logging.
+	SlowQueryThreshold = 200 * time.Millisecond
+
+	// QueryTimeout bounds queries made without a deadline on their context.
+	QueryTimeout = 10 * time.Second
+
+	// QueryDeadlineMargin is kept free before the deadline of the request,
+	// so a query which times out still leaves time to respond with an error.
+	QueryDeadlineMargin = 250 * time.Millisecond
+)
+
+// instrumentedDB wraps a sqlc.DBTX and, for every query, applies a timeout,
+// creates a child span of the request, records metrics and logs the query
+// if it's slow.
+type instrumentedDB struct {
+	db sqlc.DBTX
+}
+
+func instrument(db sqlc.DBTX) sqlc.DBTX {
+	return &instrumentedDB{db: db}
+}
+
+func (d *instrumentedDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	q := startQuery(ctx, sql, args)
+	tag, err := d.db.Exec(q.ctx, sql, args...)
+	q.end(tag.RowsAffected(), err)
+	return tag, err
+}
+
+func (d *instrumentedDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	q := startQuery(ctx, sql, args)
+	rows, err := d.db.Query(q.ctx, sql, args...)
+	if err != nil {
+		q.end(0, err)
+		return rows, err
+	}
+	return &instrumentedRows{Rows: rows, q: q}, nil
+}
+
+func (d *instrumentedDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	q := startQuery(ctx, sql, args)
+	return &instrumentedRow{row: d.db.QueryRow(q.ctx, sql, args...), q: q}
+}
+
+// instrumentedRows counts the rows read and ends the query once closed.
+type instrumentedRows struct {
+	pgx.Rows
+	q    *query
+	n    int64
+	done bool
+}
+
+func (r *instrumentedRows) Next() bool {
+	if r.Rows.Next() {
+		r.n++
+		return true
+	}
+	return false
+}
+
+func (r *instrumentedRows) Close() {
+	r.Rows.Close()
+	if !r.done {
+		r.done = true
+		r.q.end(r.n, r.Rows.Err())
+	}
+}
+
+// instrumentedRow ends the query once the row is scanned.
+type instrumentedRow struct {
+	row pgx.Row
+	q   *query
+}
+
+func (r *instrumentedRow) Scan(dest ...interface{}) error {
+	err := r.row.Scan(dest...)
+	switch err {
+	case nil:
+		r.q.end(1, nil)
+	case pgx.ErrNoRows:
+		r.q.end(0, nil)
+	default:
+		r.q.end(0, err)
+	}
+	return err
+}
+
+// query tracks a single statement from start to completion.
+type query struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	span   trace.Span
+	name   string
+	args   []interface{}
+	start  time.Time
+}
+
+func startQuery(ctx context.Context, sql string, args []interface{}) *query {
+	q := &query{name: QueryName(sql), args: args}
+
+	ctx, q.cancel = queryContext(ctx)
+	q.ctx, q.span = tracing.Tracer().Start(ctx, "db "+q.name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBOperationKey.String(q.name),
+			semconv.DBStatementKey.String(sql),
+		),
+	)
+	q.start = time.Now()
+	return q
+}
+
+func (q *query) end(rows int64, err error) {
+	elapsed := time.Since(q.start)
+	q.cancel()
+
+	q.span.SetAttributes(attribute.Int64("db.rows", rows))
+	tracing.RecordError(q.span, err)
+	q.span.End()
+
+	if err != nil {
+		metrics.DBQueryErrors.WithLabelValues(q.name).Inc()
+	} else {
+		metrics.DBQueryDuration.WithLabelValues(q.name).Observe(elapsed.Seconds())
+		metrics.DBQueryRows.WithLabelValues(q.name).Observe(float64(rows))
+	}
+
+	if SlowQueryThreshold > 0 && elapsed >= SlowQueryThreshold {
+		logger.Warn().
+			Err(err).
+			Str("query", q.name).
+			Dur("duration", elapsed).
+			Int64("rows", rows).
+			Strs("args", redactArgs(q.args)).
+			Str("traceID", q.span.SpanContext().TraceID().String()).
+			Msg("slow query")
+	}
+}
+
+// queryContext derives the context of a single query. Queries inherit the
+// deadline of the request minus a margin to report the failure, and queries
+// without a deadline get QueryTimeout.
+func queryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return context.WithTimeout(ctx, QueryTimeout)
+	}
+	if time.Until(deadline) > 2*QueryDeadlineMargin {
+		return context.WithDeadline(ctx, deadline.Add(-QueryDeadlineMargin))
+	}
+	return context.WithCancel(ctx)
+}
+
+// QueryName returns the name of a sqlc query from its "-- name:" header, or
+// "other" for hand written sql.
+func QueryName(sql string) string {
+	const prefix = "-- name: "
+	if !strings.HasPrefix(sql, prefix) {
+		return "other"
+	}
+	name := sql[len(prefix):]
+	if i := strings.IndexAny(name, " \n"); i != -1 {
+		name = name[:i]
+	}
+	return name
+}
+
+// redactArgs describes query arguments by type and size only, so logs never
+// contain user data.
+func redactArgs(args []interface{}) []string {
+	out := make([]string, len(args))
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case nil:
+			out[i] = fmt.Sprintf("$%d=null", i+1)
+		case string:
+			out[i] = fmt.Sprintf("$%d=string(%d)", i+1, len(v))
+		case []byte:
+			out[i] = fmt.Sprintf("$%d=bytes(%d)", i+1, len(v))
+		default:
+			out[i] = fmt.Sprintf("$%d=%T", i+1, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,77 @@
+package data
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+// Migrations holds the golang-migrate files from data/migrations.
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
+
+// LatestMigration returns the version of the newest migration shipped with
+// this build.
+func LatestMigration() (uint, error) {
+	files, err := fs.Glob(Migrations, "migrations/*.up.sql")
+	if err != nil {
+		return 0, err
+	}
+
+	var latest uint
+	for _, file := range files {
+		name := strings.TrimPrefix(file, "migrations/")
+		i := strings.IndexByte(name, '_')
+		if i == -1 {
+			return 0, fmt.Errorf("data: invalid migration file name %q", name)
+		}
+		v, err := strconv.ParseUint(name[:i], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("data: invalid migration file name %q: %w", name, err)
+		}
+		if uint(v) > latest {
+			latest = uint(v)
+		}
+	}
+	return latest, nil
+}
+
+// MigrationVersion returns the migration version the database is at, as
+// recorded by golang-migrate, and whether the last migration failed halfway.
+func MigrationVersion(ctx context.Context) (version uint, dirty bool, err error) {
+	if pool == nil {
+		return 0, false, ErrNotConnected
+	}
+	var v int64
+	err = pool.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&v, &dirty)
+	if err != nil {
+		return 0, false, err
+	}
+	return uint(v), dirty, nil
+}
+
+// CheckMigrations is a readiness check which fails while the database schema
+// is behind the latest migration shipped with this build. A newer schema is
+// fine: migrations are expand/contract, so the replicas of the previous
+// release keep serving while a rolling deploy migrates the database.
+func CheckMigrations(ctx context.Context) error {
+	latest, err := LatestMigration()
+	if err != nil {
+		return err
+	}
+	version, dirty, err := MigrationVersion(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read migration version: %w", err)
+	}
+	if dirty {
+		return fmt.Errorf("migration %d is dirty", version)
+	}
+	if version < latest {
+		return fmt.Errorf("database is at migration %d, expected %d or later", version, latest)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS jobs (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    kind VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    unique_key TEXT,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'dead')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 10,
+    last_error TEXT,
+    run_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_at TIMESTAMP,
+    locked_by TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Workers only ever scan due pending jobs.
+CREATE INDEX IF NOT EXISTS jobs_dequeue_idx ON jobs (run_at, id) WHERE status = 'pending';
+
+-- A unique key dedupes a job for as long as it is waiting or running.
+CREATE UNIQUE INDEX IF NOT EXISTS jobs_unique_key_idx ON jobs (unique_key) WHERE status IN ('pending', 'running');
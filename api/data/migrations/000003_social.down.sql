@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS follows RESTRICT;
+DROP INDEX IF EXISTS likes_post_id_liked_by_key;
+ALTER TABLE comments DROP COLUMN IF EXISTS parent_id;
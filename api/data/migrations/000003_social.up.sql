@@ -0,0 +1,14 @@
+-- Replies point at the comment they answer.
+ALTER TABLE comments ADD COLUMN parent_id INTEGER REFERENCES comments(id) ON DELETE CASCADE ON UPDATE NO ACTION;
+
+CREATE UNIQUE INDEX IF NOT EXISTS likes_post_id_liked_by_key ON likes (post_id, liked_by);
+
+CREATE TABLE IF NOT EXISTS follows (
+    follower CHAR(42) NOT NULL REFERENCES users(addr) ON DELETE CASCADE ON UPDATE NO ACTION,
+    followee CHAR(42) NOT NULL REFERENCES users(addr) ON DELETE CASCADE ON UPDATE NO ACTION,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (follower, followee),
+    CONSTRAINT follows_self_check CHECK (follower <> followee)
+);
+
+CREATE INDEX IF NOT EXISTS follows_followee_idx ON follows (followee);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS outbox_checkpoints RESTRICT;
+DROP TABLE IF EXISTS outbox RESTRICT;
@@ -0,0 +1,21 @@
+-- Domain events, written in the same transaction as the change they describe
+-- and relayed to subscribers in (txid, id) order.
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    txid BIGINT NOT NULL DEFAULT txid_current(),
+    type VARCHAR(64) NOT NULL,
+    actor CHAR(42) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS outbox_txid_id_idx ON outbox (txid, id);
+CREATE INDEX IF NOT EXISTS outbox_created_at_idx ON outbox (created_at);
+
+-- Position of each subscriber in the outbox.
+CREATE TABLE IF NOT EXISTS outbox_checkpoints (
+    subscriber VARCHAR(64) NOT NULL PRIMARY KEY,
+    last_txid BIGINT NOT NULL DEFAULT 0,
+    last_id BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
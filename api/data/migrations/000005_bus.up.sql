@@ -0,0 +1,8 @@
+-- Payloads of bus messages too large to be sent with NOTIFY.
+CREATE TABLE IF NOT EXISTS bus_spill (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS bus_spill_created_at_idx ON bus_spill (created_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS chat_presence RESTRICT;
+DROP TABLE IF EXISTS chat_messages RESTRICT;
@@ -0,0 +1,22 @@
+-- Chat messages of the collection rooms, one room per contract.
+CREATE TABLE IF NOT EXISTS chat_messages (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    contract_addr CHAR(42) NOT NULL,
+    author CHAR(42) NOT NULL REFERENCES users(addr) ON DELETE CASCADE ON UPDATE NO ACTION,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS chat_messages_contract_addr_id_idx ON chat_messages (contract_addr, id DESC);
+
+-- Open chat connections, refreshed while they're alive so the ones of
+-- crashed replicas expire.
+CREATE TABLE IF NOT EXISTS chat_presence (
+    contract_addr CHAR(42) NOT NULL,
+    account CHAR(42) NOT NULL,
+    conn_id TEXT NOT NULL,
+    last_seen TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (contract_addr, account, conn_id)
+);
+
+CREATE INDEX IF NOT EXISTS chat_presence_last_seen_idx ON chat_presence (last_seen);
@@ -0,0 +1,29 @@
+-- In-app notifications. Repeated events on the same subject are aggregated
+-- into the unread notification of their group, ie. the likes of a post.
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    recipient CHAR(42) NOT NULL REFERENCES users(addr) ON DELETE CASCADE ON UPDATE NO ACTION,
+    kind TEXT NOT NULL,
+    group_key TEXT NOT NULL,
+    post_id INTEGER REFERENCES posts(id) ON DELETE CASCADE ON UPDATE NO ACTION,
+    comment_id INTEGER REFERENCES comments(id) ON DELETE CASCADE ON UPDATE NO ACTION,
+    -- Distinct actors of the group, latest first.
+    actors TEXT[] NOT NULL,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS notifications_recipient_group_key_unread_key ON notifications (recipient, group_key) WHERE read_at IS NULL;
+CREATE INDEX IF NOT EXISTS notifications_recipient_updated_at_idx ON notifications (recipient, updated_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS notifications_updated_at_idx ON notifications (updated_at);
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    account CHAR(42) NOT NULL PRIMARY KEY REFERENCES users(addr) ON DELETE CASCADE ON UPDATE NO ACTION,
+    likes BOOLEAN NOT NULL DEFAULT TRUE,
+    comments BOOLEAN NOT NULL DEFAULT TRUE,
+    replies BOOLEAN NOT NULL DEFAULT TRUE,
+    follows BOOLEAN NOT NULL DEFAULT TRUE,
+    mentions BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
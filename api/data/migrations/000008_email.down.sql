@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS users_digest_idx;
+ALTER TABLE users DROP COLUMN IF EXISTS digest_sent_at;
+ALTER TABLE users DROP COLUMN IF EXISTS digest;
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
+ALTER TABLE users DROP COLUMN IF EXISTS email;
@@ -0,0 +1,8 @@
+-- Optional email address, only mailed once verified, and the frequency of
+-- the activity digest mailed to it.
+ALTER TABLE users ADD COLUMN email TEXT;
+ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN digest TEXT NOT NULL DEFAULT 'off' CONSTRAINT users_digest_check CHECK (digest IN ('off', 'daily', 'weekly'));
+ALTER TABLE users ADD COLUMN digest_sent_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS users_digest_idx ON users (digest, digest_sent_at) WHERE digest <> 'off' AND email_verified_at IS NOT NULL;
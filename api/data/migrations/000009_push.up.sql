@@ -0,0 +1,11 @@
+-- Web Push subscriptions of the browsers of a user.
+CREATE TABLE IF NOT EXISTS push_subscriptions (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    account CHAR(42) NOT NULL REFERENCES users(addr) ON DELETE CASCADE ON UPDATE NO ACTION,
+    endpoint TEXT NOT NULL UNIQUE,
+    p256dh TEXT NOT NULL,
+    auth TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS push_subscriptions_account_idx ON push_subscriptions (account);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS webhook_deliveries RESTRICT;
+DROP TABLE IF EXISTS webhooks RESTRICT;
@@ -0,0 +1,39 @@
+-- Outbound webhooks of partners, ie. the Discord bot of a collection,
+-- receiving the activity on the posts of a contract.
+CREATE TABLE IF NOT EXISTS webhooks (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    owner CHAR(42) NOT NULL REFERENCES users(addr) ON DELETE CASCADE ON UPDATE NO ACTION,
+    contract_addr CHAR(42) NOT NULL,
+    url TEXT NOT NULL,
+    event_types TEXT[] NOT NULL,
+    secret TEXT NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    -- Consecutive failed delivery attempts, the webhook is disabled once
+    -- they reach webhooks.max_failures.
+    failure_count INTEGER NOT NULL DEFAULT 0,
+    disabled_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS webhooks_owner_idx ON webhooks (owner);
+CREATE INDEX IF NOT EXISTS webhooks_contract_addr_idx ON webhooks (contract_addr) WHERE enabled;
+
+-- Delivery attempts of the webhooks, every retry is logged.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    webhook_id BIGINT NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE ON UPDATE NO ACTION,
+    -- Shared by the attempts of the same payload.
+    delivery_id TEXT NOT NULL,
+    event_type TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    status_code INTEGER,
+    error TEXT,
+    response TEXT,
+    duration_ms INTEGER NOT NULL,
+    success BOOLEAN NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_webhook_id_idx ON webhook_deliveries (webhook_id, id DESC);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_created_at_idx ON webhook_deliveries (created_at);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS users_lower_name_idx;
+DROP TABLE IF EXISTS comment_hashtags RESTRICT;
+DROP TABLE IF EXISTS comment_mentions RESTRICT;
@@ -0,0 +1,24 @@
+-- Users mentioned in comments, by the handle they were mentioned with: their
+-- lowercase address or name.
+CREATE TABLE IF NOT EXISTS comment_mentions (
+    comment_id INTEGER NOT NULL REFERENCES comments(id) ON DELETE CASCADE ON UPDATE NO ACTION,
+    handle TEXT NOT NULL,
+    account CHAR(42) NOT NULL REFERENCES users(addr) ON DELETE CASCADE ON UPDATE NO ACTION,
+    PRIMARY KEY (comment_id, handle)
+);
+
+CREATE INDEX IF NOT EXISTS comment_mentions_account_idx ON comment_mentions (account);
+
+-- Lowercase hashtags of comments, with the post of the comment to browse the
+-- posts of a hashtag.
+CREATE TABLE IF NOT EXISTS comment_hashtags (
+    comment_id INTEGER NOT NULL REFERENCES comments(id) ON DELETE CASCADE ON UPDATE NO ACTION,
+    tag TEXT NOT NULL,
+    post_id INTEGER NOT NULL REFERENCES posts(id) ON DELETE CASCADE ON UPDATE NO ACTION,
+    PRIMARY KEY (comment_id, tag)
+);
+
+CREATE INDEX IF NOT EXISTS comment_hashtags_tag_post_id_idx ON comment_hashtags (tag, post_id DESC);
+
+-- Mentions by name.
+CREATE INDEX IF NOT EXISTS users_lower_name_idx ON users (lower(name));
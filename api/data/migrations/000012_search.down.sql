@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS token_metadata_search_index ON token_metadata;
+DROP TRIGGER IF EXISTS comments_search_index ON comments;
+DROP TRIGGER IF EXISTS users_search_index ON users;
+DROP FUNCTION IF EXISTS search_index_token();
+DROP FUNCTION IF EXISTS search_index_comment();
+DROP FUNCTION IF EXISTS search_index_user();
+DROP TABLE IF EXISTS search_documents RESTRICT;
+DROP INDEX IF EXISTS posts_token_key_idx;
+DROP TABLE IF EXISTS token_metadata RESTRICT;
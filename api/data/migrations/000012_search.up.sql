@@ -0,0 +1,90 @@
+-- Metadata of the tokens of the posts, fetched from their tokenURI.
+CREATE TABLE IF NOT EXISTS token_metadata (
+    contract_addr CHAR(42) NOT NULL,
+    token_id INTEGER NOT NULL,
+    name TEXT,
+    description TEXT,
+    image TEXT,
+    -- Why the last fetch failed, the metadata of the previous one is kept.
+    error TEXT,
+    fetched_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (contract_addr, token_id)
+);
+
+CREATE INDEX IF NOT EXISTS token_metadata_fetched_at_idx ON token_metadata (fetched_at);
+
+-- Posts are matched to the metadata of their token, keyed as in
+-- search_documents.
+CREATE INDEX IF NOT EXISTS posts_token_key_idx ON posts ((lower(contract_addr::text) || ':' || token_id::text));
+
+-- Full-text search documents, kept current by the triggers below. Keys are
+-- the address of users, the id of comments, and "<contract>:<token id>" of
+-- tokens.
+CREATE TABLE IF NOT EXISTS search_documents (
+    kind TEXT NOT NULL CHECK (kind IN ('user', 'comment', 'token')),
+    key TEXT NOT NULL,
+    vector tsvector NOT NULL,
+    PRIMARY KEY (kind, key)
+);
+
+CREATE INDEX IF NOT EXISTS search_documents_vector_idx ON search_documents USING GIN (vector);
+
+CREATE OR REPLACE FUNCTION search_index_user() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        DELETE FROM search_documents WHERE kind = 'user' AND key = OLD.addr::text;
+        RETURN OLD;
+    END IF;
+    INSERT INTO search_documents (kind, key, vector)
+    VALUES ('user', NEW.addr::text, to_tsvector('simple', NEW.name))
+    ON CONFLICT (kind, key) DO UPDATE SET vector = EXCLUDED.vector;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER users_search_index AFTER INSERT OR DELETE OR UPDATE OF name ON users
+FOR EACH ROW EXECUTE FUNCTION search_index_user();
+
+CREATE OR REPLACE FUNCTION search_index_comment() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        DELETE FROM search_documents WHERE kind = 'comment' AND key = OLD.id::text;
+        RETURN OLD;
+    END IF;
+    INSERT INTO search_documents (kind, key, vector)
+    VALUES ('comment', NEW.id::text, to_tsvector('simple', NEW.content))
+    ON CONFLICT (kind, key) DO UPDATE SET vector = EXCLUDED.vector;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER comments_search_index AFTER INSERT OR DELETE OR UPDATE OF content ON comments
+FOR EACH ROW EXECUTE FUNCTION search_index_comment();
+
+-- Token names rank above their descriptions.
+CREATE OR REPLACE FUNCTION search_index_token() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        DELETE FROM search_documents WHERE kind = 'token' AND key = OLD.contract_addr::text || ':' || OLD.token_id::text;
+        RETURN OLD;
+    END IF;
+    INSERT INTO search_documents (kind, key, vector)
+    VALUES ('token', NEW.contract_addr::text || ':' || NEW.token_id::text,
+        setweight(to_tsvector('simple', coalesce(NEW.name, '')), 'A') ||
+        setweight(to_tsvector('simple', coalesce(NEW.description, '')), 'B'))
+    ON CONFLICT (kind, key) DO UPDATE SET vector = EXCLUDED.vector;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER token_metadata_search_index AFTER INSERT OR DELETE OR UPDATE OF name, description ON token_metadata
+FOR EACH ROW EXECUTE FUNCTION search_index_token();
+
+-- Index the existing users and comments.
+INSERT INTO search_documents (kind, key, vector)
+SELECT 'user', addr::text, to_tsvector('simple', name) FROM users
+ON CONFLICT DO NOTHING;
+
+INSERT INTO search_documents (kind, key, vector)
+SELECT 'comment', id::text, to_tsvector('simple', content) FROM comments
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS reports RESTRICT;
+ALTER TABLE users DROP COLUMN IF EXISTS suspended_until;
+ALTER TABLE users DROP COLUMN IF EXISTS suspended_at;
+ALTER TABLE comments DROP COLUMN IF EXISTS visibility;
+ALTER TABLE posts DROP COLUMN IF EXISTS visibility;
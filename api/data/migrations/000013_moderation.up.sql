@@ -0,0 +1,34 @@
+-- Moderation state of posts and comments. Hidden content is kept out of
+-- feeds, lists and search while it's reviewed, and removed content was taken
+-- down. Both can be restored.
+ALTER TABLE posts ADD COLUMN visibility TEXT NOT NULL DEFAULT 'visible'
+    CONSTRAINT posts_visibility_check CHECK (visibility IN ('visible', 'hidden', 'removed'));
+ALTER TABLE comments ADD COLUMN visibility TEXT NOT NULL DEFAULT 'visible'
+    CONSTRAINT comments_visibility_check CHECK (visibility IN ('visible', 'hidden', 'removed'));
+
+-- Suspended accounts can't post. Suspensions without an end last until
+-- they're lifted.
+ALTER TABLE users ADD COLUMN suspended_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN suspended_until TIMESTAMP;
+
+-- Reports of posts, comments and profiles, keyed by the id of the post or
+-- comment or the address of the user.
+CREATE TABLE IF NOT EXISTS reports (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    reporter CHAR(42) NOT NULL REFERENCES users(addr) ON DELETE CASCADE ON UPDATE NO ACTION,
+    target_type TEXT NOT NULL CHECK (target_type IN ('post', 'comment', 'user')),
+    target_id TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    details TEXT,
+    status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'actioned', 'dismissed')),
+    -- The moderation action which resolved the report.
+    action TEXT,
+    resolved_by CHAR(42),
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A reporter has at most one open report per target.
+CREATE UNIQUE INDEX IF NOT EXISTS reports_reporter_target_open_key ON reports (reporter, target_type, target_id) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS reports_status_id_idx ON reports (status, id DESC);
+CREATE INDEX IF NOT EXISTS reports_target_idx ON reports (target_type, target_id);
@@ -0,0 +1,33 @@
+-- Append-only log of privileged actions and sensitive account changes. Each
+-- entry holds the hash of the previous one, so altering or deleting entries
+-- breaks the chain.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL NOT NULL PRIMARY KEY,
+    actor CHAR(42) NOT NULL,
+    action TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id TEXT NOT NULL,
+    -- Snapshots of the target, kept as written since they're hashed.
+    before JSON,
+    after JSON,
+    request_id TEXT NOT NULL,
+    ip TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    prev_hash TEXT NOT NULL,
+    hash TEXT NOT NULL UNIQUE
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_actor_id_idx ON audit_log (actor, id DESC);
+CREATE INDEX IF NOT EXISTS audit_log_target_id_idx ON audit_log (target_type, target_id, id DESC);
+
+CREATE OR REPLACE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only BEFORE UPDATE OR DELETE ON audit_log
+FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+
+CREATE TRIGGER audit_log_no_truncate BEFORE TRUNCATE ON audit_log
+FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
@@ -0,0 +1,13 @@
+-- Blocks and mutes between users. Blocked users can't comment on, like or
+-- follow the blocker, and muted users are kept out of the feeds and
+-- notifications of the muter, as are blocked ones.
+CREATE TABLE IF NOT EXISTS user_blocks (
+    blocker CHAR(42) NOT NULL REFERENCES users(addr) ON DELETE CASCADE ON UPDATE NO ACTION,
+    blocked CHAR(42) NOT NULL REFERENCES users(addr) ON DELETE CASCADE ON UPDATE NO ACTION,
+    kind TEXT NOT NULL CHECK (kind IN ('block', 'mute')),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (blocker, blocked, kind),
+    CONSTRAINT user_blocks_self_check CHECK (blocker <> blocked)
+);
+
+CREATE INDEX IF NOT EXISTS user_blocks_blocked_idx ON user_blocks (blocked);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS reputation RESTRICT;
+DROP TABLE IF EXISTS blocklist RESTRICT;
+DROP TABLE IF EXISTS held_comments RESTRICT;
+DROP INDEX IF EXISTS comments_author_created_at_idx;
+UPDATE comments SET visibility = 'hidden' WHERE visibility = 'held';
+ALTER TABLE comments DROP CONSTRAINT comments_visibility_check;
+ALTER TABLE comments ADD CONSTRAINT comments_visibility_check CHECK (visibility IN ('visible', 'hidden', 'removed'));
+ALTER TABLE users DROP COLUMN IF EXISTS created_at;
@@ -0,0 +1,37 @@
+-- When accounts registered, to tell new accounts apart. Accounts registered
+-- before are left without.
+ALTER TABLE users ADD COLUMN created_at TIMESTAMP;
+ALTER TABLE users ALTER COLUMN created_at SET DEFAULT CURRENT_TIMESTAMP;
+
+-- Comments held by the spam filter until a moderator reviews them.
+ALTER TABLE comments DROP CONSTRAINT comments_visibility_check;
+ALTER TABLE comments ADD CONSTRAINT comments_visibility_check CHECK (visibility IN ('visible', 'hidden', 'removed', 'held'));
+
+CREATE INDEX IF NOT EXISTS comments_author_created_at_idx ON comments (author, created_at);
+
+CREATE TABLE IF NOT EXISTS held_comments (
+    comment_id INTEGER NOT NULL PRIMARY KEY REFERENCES comments(id) ON DELETE CASCADE ON UPDATE NO ACTION,
+    score REAL NOT NULL,
+    reasons TEXT[] NOT NULL,
+    -- The payload of the comment.created event, published once approved.
+    event JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Words and domains which get comments held, managed by admins.
+CREATE TABLE IF NOT EXISTS blocklist (
+    id SERIAL NOT NULL PRIMARY KEY,
+    kind TEXT NOT NULL CHECK (kind IN ('word', 'url')),
+    value TEXT NOT NULL,
+    created_by CHAR(42) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT blocklist_kind_value_key UNIQUE (kind, value)
+);
+
+-- Reputation of users, lowered when moderators take action on their content
+-- and raised when it's restored.
+CREATE TABLE IF NOT EXISTS reputation (
+    account CHAR(42) NOT NULL PRIMARY KEY REFERENCES users(addr) ON DELETE CASCADE ON UPDATE NO ACTION,
+    score INTEGER NOT NULL DEFAULT 0 CHECK (score BETWEEN -100 AND 100),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
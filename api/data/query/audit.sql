@@ -0,0 +1,28 @@
+-- name: LockAuditLog :exec
+-- Serializes the writers of the audit log until the end of the transaction,
+-- so entries are chained in order.
+SELECT pg_advisory_xact_lock(hashtext('audit_log'));
+
+-- name: GetLastAuditHash :one
+SELECT hash FROM audit_log ORDER BY id DESC LIMIT 1;
+
+-- name: CreateAuditEntry :one
+INSERT INTO audit_log (actor, action, target_type, target_id, before, after, request_id, ip, created_at, prev_hash, hash)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING *;
+
+-- name: ListAuditLog :many
+-- Returns the entries of the audit log, latest first. The filters are
+-- ignored when empty.
+SELECT * FROM audit_log
+WHERE (sqlc.arg(actor)::text = '' OR actor = sqlc.arg(actor))
+  AND (sqlc.arg(action)::text = '' OR action = sqlc.arg(action))
+  AND (sqlc.arg(target_type)::text = '' OR target_type = sqlc.arg(target_type))
+  AND (sqlc.arg(target_id)::text = '' OR target_id = sqlc.arg(target_id))
+  AND id < sqlc.arg(before_id)
+ORDER BY id DESC
+LIMIT sqlc.arg(max_entries);
+
+-- name: ListAuditChain :many
+-- Returns the entries after after_id in chain order, to verify the chain.
+SELECT * FROM audit_log WHERE id > sqlc.arg(after_id) ORDER BY id LIMIT sqlc.arg(max_entries);
@@ -0,0 +1,24 @@
+-- name: CreateUserBlock :one
+INSERT INTO user_blocks (blocker, blocked, kind) VALUES ($1, $2, $3)
+ON CONFLICT (blocker, blocked, kind) DO NOTHING
+RETURNING *;
+
+-- name: DeleteUserBlock :execrows
+DELETE FROM user_blocks WHERE blocker = $1 AND blocked = $2 AND kind = $3;
+
+-- name: IsBlocked :one
+SELECT EXISTS (SELECT 1 FROM user_blocks WHERE blocker = $1 AND blocked = $2 AND kind = 'block');
+
+-- name: IsMuted :one
+-- Blocked users are muted too.
+SELECT EXISTS (SELECT 1 FROM user_blocks WHERE blocker = $1 AND blocked = $2);
+
+-- name: ListMutedAccounts :many
+-- Returns the users blocked or muted by blocker.
+SELECT DISTINCT blocked FROM user_blocks WHERE blocker = $1;
+
+-- name: ListUserBlocks :many
+SELECT * FROM user_blocks
+WHERE blocker = $1 AND kind = $2 AND blocked > sqlc.arg(after_blocked)::text
+ORDER BY blocked
+LIMIT sqlc.arg(max_blocks);
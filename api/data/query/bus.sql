@@ -0,0 +1,11 @@
+-- name: Notify :exec
+SELECT pg_notify(sqlc.arg(channel)::text, sqlc.arg(payload)::text);
+
+-- name: InsertBusSpill :one
+INSERT INTO bus_spill (payload) VALUES ($1) RETURNING id;
+
+-- name: GetBusSpill :one
+SELECT payload FROM bus_spill WHERE id = $1;
+
+-- name: PruneBusSpill :execrows
+DELETE FROM bus_spill WHERE created_at < $1;
@@ -0,0 +1,25 @@
+-- name: CreateChatMessage :one
+INSERT INTO chat_messages (contract_addr, author, content) VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: ListChatMessages :many
+-- Lists the messages of a room before a message id, newest first.
+SELECT * FROM chat_messages
+WHERE contract_addr = $1 AND id < sqlc.arg(before_id)::bigint
+ORDER BY id DESC
+LIMIT sqlc.arg(max_messages);
+
+-- name: TouchChatPresence :exec
+INSERT INTO chat_presence (contract_addr, account, conn_id) VALUES ($1, $2, $3)
+ON CONFLICT (contract_addr, account, conn_id) DO UPDATE SET last_seen = CURRENT_TIMESTAMP;
+
+-- name: DeleteChatPresence :exec
+DELETE FROM chat_presence WHERE contract_addr = $1 AND account = $2 AND conn_id = $3;
+
+-- name: ListChatPresence :many
+SELECT DISTINCT account FROM chat_presence
+WHERE contract_addr = $1 AND last_seen > sqlc.arg(seen_after)::timestamp
+ORDER BY account;
+
+-- name: PruneChatPresence :execrows
+DELETE FROM chat_presence WHERE last_seen < $1;
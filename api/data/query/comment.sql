@@ -0,0 +1,34 @@
+-- name: GetComment :one
+SELECT * FROM comments WHERE id = $1;
+
+-- name: CreateComment :one
+INSERT INTO comments (post_id, parent_id, author, content) VALUES ($1, $2, $3, $4) RETURNING *;
+
+-- name: ListComments :many
+-- Leaves out the comments of hidden or removed posts, and those of the users
+-- the viewer blocked or muted.
+SELECT comments.* FROM comments
+JOIN posts p ON p.id = comments.post_id AND p.visibility = 'visible'
+WHERE comments.post_id = sqlc.arg(post_id) AND comments.visibility = 'visible' AND comments.id < sqlc.arg(before_id)
+  AND NOT EXISTS (SELECT 1 FROM user_blocks b WHERE b.blocker = sqlc.arg(viewer)::text AND b.blocked = comments.author)
+ORDER BY comments.id DESC
+LIMIT sqlc.arg(max_comments);
+
+-- name: CreateCommentMentions :exec
+INSERT INTO comment_mentions (comment_id, handle, account)
+SELECT sqlc.arg(comment_id), m.handle, m.account
+FROM unnest(sqlc.arg(handles)::text[], sqlc.arg(accounts)::text[]) AS m(handle, account);
+
+-- name: ListCommentMentions :many
+SELECT * FROM comment_mentions WHERE comment_id = ANY(sqlc.arg(comment_ids)::int[]);
+
+-- name: CreateCommentHashtags :exec
+INSERT INTO comment_hashtags (comment_id, tag, post_id)
+SELECT sqlc.arg(comment_id), t.tag, sqlc.arg(post_id)
+FROM unnest(sqlc.arg(tags)::text[]) AS t(tag);
+
+-- name: ListCommentsByID :many
+SELECT * FROM comments WHERE id = ANY(sqlc.arg(ids)::int[]);
+
+-- name: SetCommentVisibility :execrows
+UPDATE comments SET visibility = $2 WHERE id = $1;
@@ -0,0 +1,7 @@
+-- name: CreateFollow :one
+INSERT INTO follows (follower, followee) VALUES ($1, $2)
+ON CONFLICT (follower, followee) DO NOTHING
+RETURNING *;
+
+-- name: DeleteFollow :execrows
+DELETE FROM follows WHERE follower = $1 AND followee = $2;
@@ -0,0 +1,34 @@
+-- name: EnqueueJob :one
+INSERT INTO jobs (kind, payload, unique_key, max_attempts, run_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (unique_key) WHERE status IN ('pending', 'running') DO NOTHING
+RETURNING *;
+
+-- name: DequeueJobs :many
+UPDATE jobs SET status = 'running', attempts = attempts + 1, locked_at = CURRENT_TIMESTAMP, locked_by = sqlc.arg(worker)
+WHERE id IN (
+    SELECT id FROM jobs
+    WHERE status = 'pending' AND run_at <= CURRENT_TIMESTAMP AND kind = ANY(sqlc.arg(kinds)::text[])
+    ORDER BY run_at, id
+    LIMIT sqlc.arg(max_jobs)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: CompleteJob :exec
+DELETE FROM jobs WHERE id = $1;
+
+-- name: RetryJob :exec
+UPDATE jobs SET status = 'pending', run_at = $2, last_error = $3, locked_at = NULL, locked_by = NULL WHERE id = $1;
+
+-- name: KillJob :exec
+UPDATE jobs SET status = 'dead', last_error = $2, locked_at = NULL, locked_by = NULL WHERE id = $1;
+
+-- name: RescueJobs :execrows
+-- Releases the jobs locked for longer than lock_timeout, moving those out of
+-- attempts to the dead letter.
+UPDATE jobs SET
+    status = CASE WHEN attempts >= max_attempts THEN 'dead' ELSE 'pending' END,
+    last_error = 'lock expired, the worker died or timed out',
+    locked_at = NULL, locked_by = NULL
+WHERE status = 'running' AND locked_at < CURRENT_TIMESTAMP - sqlc.arg(lock_timeout)::interval;
@@ -0,0 +1,7 @@
+-- name: CreateLike :one
+INSERT INTO likes (post_id, liked_by) VALUES ($1, $2)
+ON CONFLICT (post_id, liked_by) DO NOTHING
+RETURNING *;
+
+-- name: DeleteLike :execrows
+DELETE FROM likes WHERE post_id = $1 AND liked_by = $2;
@@ -0,0 +1,51 @@
+-- name: UpsertNotification :execrows
+-- Adds actor to the unread notification of the group, or creates it. Nothing
+-- is written for recipients who aren't registered.
+INSERT INTO notifications (recipient, kind, group_key, post_id, comment_id, actors)
+SELECT $1, $2, $3, $4, $5, ARRAY[sqlc.arg(actor)::text]
+WHERE EXISTS (SELECT 1 FROM users WHERE addr = $1)
+ON CONFLICT (recipient, group_key) WHERE read_at IS NULL DO UPDATE
+SET actors = CASE WHEN EXCLUDED.actors[1] = ANY(notifications.actors) THEN notifications.actors
+                  ELSE EXCLUDED.actors || notifications.actors END,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: ListNotifications :many
+SELECT id, kind, post_id, comment_id, actors[1:3]::text[] AS actors, cardinality(actors)::int AS actor_count,
+       read_at, created_at, updated_at
+FROM notifications
+WHERE recipient = $1 AND (updated_at, id) < (sqlc.arg(before_updated_at)::timestamp, sqlc.arg(before_id)::bigint)
+ORDER BY updated_at DESC, id DESC
+LIMIT sqlc.arg(max_notifications);
+
+-- name: MarkNotificationsRead :execrows
+UPDATE notifications SET read_at = CURRENT_TIMESTAMP
+WHERE recipient = $1 AND read_at IS NULL AND id = ANY(sqlc.arg(ids)::bigint[]);
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications SET read_at = CURRENT_TIMESTAMP
+WHERE recipient = $1 AND read_at IS NULL;
+
+-- name: CountUnreadNotifications :one
+SELECT count(*) FROM notifications WHERE recipient = $1 AND read_at IS NULL;
+
+-- name: PruneNotifications :execrows
+DELETE FROM notifications WHERE updated_at < $1;
+
+-- name: GetNotificationPreferences :one
+SELECT * FROM notification_preferences WHERE account = $1;
+
+-- name: SaveNotificationPreferences :one
+INSERT INTO notification_preferences (account, likes, comments, replies, follows, mentions)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (account) DO UPDATE
+SET likes = EXCLUDED.likes, comments = EXCLUDED.comments, replies = EXCLUDED.replies,
+    follows = EXCLUDED.follows, mentions = EXCLUDED.mentions, updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: ListNotificationsSince :many
+SELECT id, kind, post_id, comment_id, actors[1:3]::text[] AS actors, cardinality(actors)::int AS actor_count,
+       read_at, created_at, updated_at
+FROM notifications
+WHERE recipient = $1 AND updated_at > sqlc.arg(since)::timestamp
+ORDER BY updated_at DESC, id DESC
+LIMIT sqlc.arg(max_notifications);
@@ -0,0 +1,21 @@
+-- name: InsertOutboxEvent :one
+INSERT INTO outbox (type, actor, payload) VALUES ($1, $2, $3) RETURNING *;
+
+-- name: ListOutboxEvents :many
+-- Only events of transactions older than every running transaction are
+-- listed, so an event committed later can never sort before the checkpoint.
+SELECT * FROM outbox
+WHERE (txid, id) > (sqlc.arg(after_txid)::bigint, sqlc.arg(after_id)::bigint)
+  AND txid < txid_snapshot_xmin(txid_current_snapshot())
+ORDER BY txid, id
+LIMIT sqlc.arg(max_events);
+
+-- name: GetOutboxCheckpoint :one
+SELECT * FROM outbox_checkpoints WHERE subscriber = $1;
+
+-- name: SaveOutboxCheckpoint :exec
+INSERT INTO outbox_checkpoints (subscriber, last_txid, last_id) VALUES ($1, $2, $3)
+ON CONFLICT (subscriber) DO UPDATE SET last_txid = EXCLUDED.last_txid, last_id = EXCLUDED.last_id, updated_at = CURRENT_TIMESTAMP;
+
+-- name: PruneOutbox :execrows
+DELETE FROM outbox WHERE created_at < $1;
@@ -0,0 +1,41 @@
+-- name: ReconcilePostCounters :execrows
+UPDATE posts SET like_count = c.likes, comment_count = c.comments
+FROM (
+    SELECT p.id,
+        (SELECT count(*) FROM likes l WHERE l.post_id = p.id)::int AS likes,
+        (SELECT count(*) FROM comments m WHERE m.post_id = p.id)::int AS comments
+    FROM posts p
+) c
+WHERE posts.id = c.id AND (posts.like_count IS DISTINCT FROM c.likes OR posts.comment_count IS DISTINCT FROM c.comments);
+
+-- name: GetPost :one
+SELECT * FROM posts WHERE id = $1;
+
+-- name: AddPostLikes :exec
+UPDATE posts SET like_count = COALESCE(like_count, 0) + sqlc.arg(delta)::int WHERE id = sqlc.arg(id);
+
+-- name: AddPostComments :exec
+UPDATE posts SET comment_count = COALESCE(comment_count, 0) + sqlc.arg(delta)::int WHERE id = sqlc.arg(id);
+
+-- name: ListPostsByHashtag :many
+-- Returns the visible posts with a visible comment holding the lowercase tag,
+-- latest first. Posts and comments of the users the viewer blocked or muted
+-- are left out.
+SELECT * FROM posts
+WHERE id IN (
+    SELECT h.post_id FROM comment_hashtags h JOIN comments c ON c.id = h.comment_id
+    WHERE h.tag = sqlc.arg(tag) AND c.visibility = 'visible'
+      AND NOT EXISTS (SELECT 1 FROM user_blocks b WHERE b.blocker = sqlc.arg(viewer)::text AND b.blocked = c.author)
+) AND visibility = 'visible' AND id < sqlc.arg(before_id)
+  AND NOT EXISTS (SELECT 1 FROM user_blocks b WHERE b.blocker = sqlc.arg(viewer)::text AND b.blocked = posts.author)
+ORDER BY id DESC
+LIMIT sqlc.arg(max_posts);
+
+-- name: ListPostsByID :many
+SELECT p.id, p.contract_addr, p.token_id, p.like_count, p.comment_count, p.author, p.created_at, m.name, m.description, m.image
+FROM posts p
+LEFT JOIN token_metadata m ON m.contract_addr = lower(p.contract_addr::text) AND m.token_id = p.token_id
+WHERE p.id = ANY(sqlc.arg(ids)::int[]);
+
+-- name: SetPostVisibility :execrows
+UPDATE posts SET visibility = $2 WHERE id = $1;
@@ -0,0 +1,19 @@
+-- name: SavePushSubscription :one
+-- A browser keeps its endpoint across accounts, so it moves to the latest
+-- account subscribing with it.
+INSERT INTO push_subscriptions (account, endpoint, p256dh, auth) VALUES ($1, $2, $3, $4)
+ON CONFLICT (endpoint) DO UPDATE
+SET account = EXCLUDED.account, p256dh = EXCLUDED.p256dh, auth = EXCLUDED.auth
+RETURNING *;
+
+-- name: DeletePushSubscription :execrows
+DELETE FROM push_subscriptions WHERE account = $1 AND endpoint = $2;
+
+-- name: DeletePushSubscriptionByID :exec
+DELETE FROM push_subscriptions WHERE id = $1;
+
+-- name: GetPushSubscription :one
+SELECT * FROM push_subscriptions WHERE id = $1;
+
+-- name: ListPushSubscriptions :many
+SELECT * FROM push_subscriptions WHERE account = $1 ORDER BY id;
@@ -0,0 +1,25 @@
+-- name: CreateReport :one
+-- Nothing is returned when the reporter already has an open report of the
+-- target.
+INSERT INTO reports (reporter, target_type, target_id, reason, details) VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (reporter, target_type, target_id) WHERE status = 'open' DO NOTHING
+RETURNING *;
+
+-- name: ListReports :many
+-- Returns the reports of a status, latest first. The other filters are
+-- ignored when empty.
+SELECT * FROM reports
+WHERE status = sqlc.arg(status)
+  AND (sqlc.arg(target_type)::text = '' OR target_type = sqlc.arg(target_type))
+  AND (sqlc.arg(target_id)::text = '' OR target_id = sqlc.arg(target_id))
+  AND (sqlc.arg(reason)::text = '' OR reason = sqlc.arg(reason))
+  AND id < sqlc.arg(before_id)
+ORDER BY id DESC
+LIMIT sqlc.arg(max_reports);
+
+-- name: ResolveReports :many
+-- Resolves the open reports of a target, and returns their reporters.
+UPDATE reports SET status = sqlc.arg(status), action = sqlc.arg(action)::text, resolved_by = sqlc.arg(resolved_by)::text,
+    resolved_at = CURRENT_TIMESTAMP
+WHERE target_type = sqlc.arg(target_type) AND target_id = sqlc.arg(target_id) AND status = 'open'
+RETURNING reporter;
@@ -0,0 +1,21 @@
+-- name: Search :many
+-- Ranks the documents of the given kinds matching query, a to_tsquery
+-- expression. Posts match on the metadata of their token. Hidden and removed
+-- content isn't matched, nor are the comments of hidden and removed posts.
+SELECT h.kind, h.key, h.rank FROM (
+    SELECT d.kind, d.key, ts_rank(d.vector, q.query) AS rank
+    FROM search_documents d, to_tsquery('simple', sqlc.arg(query)::text) AS q(query)
+    WHERE d.kind IN ('user', 'comment') AND d.kind = ANY(sqlc.arg(kinds)::text[]) AND d.vector @@ q.query
+      AND CASE WHEN d.kind = 'comment'
+          THEN EXISTS (SELECT 1 FROM comments c JOIN posts p ON p.id = c.post_id
+                      WHERE c.id = d.key::int AND c.visibility = 'visible' AND p.visibility = 'visible')
+          ELSE TRUE END
+    UNION ALL
+    SELECT 'post', p.id::text, ts_rank(d.vector, q.query)
+    FROM search_documents d
+    JOIN posts p ON lower(p.contract_addr::text) || ':' || p.token_id::text = d.key,
+    to_tsquery('simple', sqlc.arg(query)::text) AS q(query)
+    WHERE d.kind = 'token' AND p.visibility = 'visible' AND 'post' = ANY(sqlc.arg(kinds)::text[]) AND d.vector @@ q.query
+) h
+ORDER BY h.rank DESC, h.kind, h.key
+LIMIT sqlc.arg(max_hits) OFFSET sqlc.arg(skip_hits);
@@ -0,0 +1,40 @@
+-- name: CountRepeatedComments :one
+-- Counts the other posts author commented the same content on since.
+SELECT count(DISTINCT post_id) FROM comments
+WHERE author = sqlc.arg(author) AND lower(content) = lower(sqlc.arg(content)::text)
+  AND post_id <> sqlc.arg(post_id) AND created_at > sqlc.arg(since);
+
+-- name: CreateHeldComment :exec
+INSERT INTO held_comments (comment_id, score, reasons, event) VALUES ($1, $2, $3, $4);
+
+-- name: GetHeldComment :one
+SELECT * FROM held_comments WHERE comment_id = $1;
+
+-- name: DeleteHeldComment :execrows
+DELETE FROM held_comments WHERE comment_id = $1;
+
+-- name: ListHeldComments :many
+SELECT * FROM held_comments
+WHERE comment_id < sqlc.arg(before_id)
+ORDER BY comment_id DESC
+LIMIT sqlc.arg(max_comments);
+
+-- name: ListBlocklist :many
+SELECT * FROM blocklist ORDER BY kind, value;
+
+-- name: CreateBlocklistEntry :one
+INSERT INTO blocklist (kind, value, created_by) VALUES ($1, $2, $3)
+ON CONFLICT (kind, value) DO NOTHING
+RETURNING *;
+
+-- name: DeleteBlocklistEntry :one
+DELETE FROM blocklist WHERE id = $1 RETURNING *;
+
+-- name: GetReputation :one
+SELECT score FROM reputation WHERE account = $1;
+
+-- name: AdjustReputation :exec
+-- Adds delta to the reputation of account, within -100 and 100.
+INSERT INTO reputation (account, score) VALUES (sqlc.arg(account), GREATEST(-100, LEAST(100, sqlc.arg(delta)::int)))
+ON CONFLICT (account) DO UPDATE
+SET score = GREATEST(-100, LEAST(100, reputation.score + sqlc.arg(delta)::int)), updated_at = CURRENT_TIMESTAMP;
@@ -0,0 +1,22 @@
+-- name: ListStaleTokens :many
+-- Returns the tokens of posts without metadata, with metadata fetched before
+-- stale_before, or which failed to be fetched before retry_before.
+SELECT DISTINCT lower(p.contract_addr::text)::text AS contract_addr, p.token_id
+FROM posts p
+LEFT JOIN token_metadata m ON m.contract_addr = lower(p.contract_addr::text) AND m.token_id = p.token_id
+WHERE m.token_id IS NULL
+   OR m.fetched_at < sqlc.arg(stale_before)::timestamp
+   OR (m.error IS NOT NULL AND m.fetched_at < sqlc.arg(retry_before)::timestamp)
+LIMIT sqlc.arg(max_tokens);
+
+-- name: SaveTokenMetadata :exec
+INSERT INTO token_metadata (contract_addr, token_id, name, description, image) VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (contract_addr, token_id) DO UPDATE
+SET name = EXCLUDED.name, description = EXCLUDED.description, image = EXCLUDED.image,
+    error = NULL, fetched_at = CURRENT_TIMESTAMP;
+
+-- name: SaveTokenMetadataError :exec
+-- Records a failed fetch, keeping the metadata of the previous one.
+INSERT INTO token_metadata (contract_addr, token_id, error) VALUES ($1, $2, $3)
+ON CONFLICT (contract_addr, token_id) DO UPDATE
+SET error = EXCLUDED.error, fetched_at = CURRENT_TIMESTAMP;
@@ -6,3 +6,43 @@ INSERT INTO users (addr, name, random_msg) VALUES ($1, $2, $3) RETURNING *;
 
 -- name: UpdateUser :one
 UPDATE users SET name = $2, pfp=$3, random_msg=$4 WHERE addr = $1 RETURNING *;
+
+-- name: SetUserEmail :execrows
+-- Changing the email address resets its verification.
+UPDATE users SET email = $2, email_verified_at = NULL WHERE addr = $1;
+
+-- name: VerifyUserEmail :execrows
+UPDATE users SET email_verified_at = CURRENT_TIMESTAMP
+WHERE addr = $1 AND email = $2 AND email_verified_at IS NULL;
+
+-- name: SetUserDigest :execrows
+UPDATE users SET digest = $2 WHERE addr = $1;
+
+-- name: ListDueDigests :many
+SELECT addr, digest, digest_sent_at FROM users
+WHERE email_verified_at IS NOT NULL
+  AND ((digest = 'daily' AND (digest_sent_at IS NULL OR digest_sent_at < sqlc.arg(daily_before)::timestamp))
+    OR (digest = 'weekly' AND (digest_sent_at IS NULL OR digest_sent_at < sqlc.arg(weekly_before)::timestamp)))
+ORDER BY addr;
+
+-- name: MarkDigestSent :exec
+UPDATE users SET digest_sent_at = $2 WHERE addr = $1;
+
+-- name: ResolveMentions :many
+-- Resolves mentioned handles, lowercase addresses or names, to the accounts
+-- of the users. A name shared by several users is ambiguous and doesn't
+-- resolve.
+SELECT addr::text AS handle, addr::text AS account FROM users WHERE addr = ANY(sqlc.arg(handles)::text[])
+UNION ALL
+SELECT lower(name) AS handle, min(addr)::text AS account FROM users WHERE lower(name) = ANY(sqlc.arg(handles)::text[])
+GROUP BY lower(name) HAVING count(*) = 1;
+
+-- name: ListUsersByAddr :many
+SELECT addr, name, coalesce(pfp::text, '')::text AS pfp FROM users WHERE addr = ANY(sqlc.arg(addrs)::text[]);
+
+-- name: SuspendUser :execrows
+-- Suspends an account until suspended_until, or until lifted when null.
+UPDATE users SET suspended_at = CURRENT_TIMESTAMP, suspended_until = $2 WHERE addr = $1;
+
+-- name: LiftSuspension :execrows
+UPDATE users SET suspended_at = NULL, suspended_until = NULL WHERE addr = $1 AND suspended_at IS NOT NULL;
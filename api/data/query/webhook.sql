@@ -0,0 +1,59 @@
+-- name: CreateWebhook :one
+INSERT INTO webhooks (owner, contract_addr, url, event_types, secret) VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: CountWebhooks :one
+SELECT count(*) FROM webhooks WHERE owner = $1;
+
+-- name: GetWebhook :one
+SELECT * FROM webhooks WHERE id = $1;
+
+-- name: ListWebhooks :many
+SELECT * FROM webhooks WHERE owner = $1 ORDER BY id;
+
+-- name: ListEventWebhooks :many
+-- Returns the enabled webhooks of a contract subscribed to the events of
+-- event_type.
+SELECT * FROM webhooks
+WHERE enabled AND contract_addr = sqlc.arg(contract_addr) AND sqlc.arg(event_type)::text = ANY(event_types)
+ORDER BY id;
+
+-- name: UpdateWebhook :one
+-- Enabling a disabled webhook clears its failures.
+UPDATE webhooks
+SET url = sqlc.arg(url), event_types = sqlc.arg(event_types), enabled = sqlc.arg(enabled)::boolean,
+    failure_count = CASE WHEN sqlc.arg(enabled)::boolean AND NOT enabled THEN 0 ELSE failure_count END,
+    disabled_at = CASE WHEN sqlc.arg(enabled)::boolean THEN NULL ELSE COALESCE(disabled_at, CURRENT_TIMESTAMP) END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id) AND owner = sqlc.arg(owner)
+RETURNING *;
+
+-- name: DeleteWebhook :execrows
+DELETE FROM webhooks WHERE id = $1 AND owner = $2;
+
+-- name: RecordWebhookSuccess :exec
+UPDATE webhooks SET failure_count = 0 WHERE id = $1 AND failure_count > 0;
+
+-- name: RecordWebhookFailure :one
+-- Counts a failed delivery attempt, and disables the webhook on the
+-- max_failures consecutive one. Returns whether the webhook is still enabled.
+UPDATE webhooks
+SET failure_count = failure_count + 1,
+    enabled = enabled AND failure_count + 1 < sqlc.arg(max_failures)::int,
+    disabled_at = CASE WHEN enabled AND failure_count + 1 >= sqlc.arg(max_failures)::int THEN CURRENT_TIMESTAMP ELSE disabled_at END
+WHERE id = sqlc.arg(id)
+RETURNING enabled;
+
+-- name: CreateWebhookDelivery :one
+INSERT INTO webhook_deliveries (webhook_id, delivery_id, event_type, payload, status_code, error, response, duration_ms, success)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: ListWebhookDeliveries :many
+SELECT * FROM webhook_deliveries
+WHERE webhook_id = sqlc.arg(webhook_id) AND id < sqlc.arg(before_id)
+ORDER BY id DESC
+LIMIT sqlc.arg(max_deliveries);
+
+-- name: PruneWebhookDeliveries :execrows
+DELETE FROM webhook_deliveries WHERE created_at < $1;
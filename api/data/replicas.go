@@ -0,0 +1,308 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/goware/pgkit"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/nfteseum/nfteseum-learning-project/api/config"
+)
+
+type contextKey struct {
+	name string
+}
+
+func (k *contextKey) String() string {
+	return "data context value " + k.name
+}
+
+var (
+	primaryCtxKey = &contextKey{"Primary"}
+	sessionCtxKey = &contextKey{"Session"}
+)
+
+// WithPrimary returns a context whose queries are always sent to the primary,
+// for callers which must read their own writes.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryCtxKey, true)
+}
+
+// WithSession tags ctx with a key identifying the caller, ie. an account
+// address or client ip. Reads made with the same key shortly after a write
+// are sent to the primary so the caller sees its own changes.
+func WithSession(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, sessionCtxKey, key)
+}
+
+// router is the sqlc.DBTX behind data.DB. Writes go to the primary, while
+// read-only queries are balanced across healthy replicas and fall back to the
+// primary when none is available or a replica fails.
+type router struct {
+	primary  *pgxpool.Pool
+	replicas []*replica
+	next     uint32
+
+	interval time.Duration
+	maxLag   time.Duration
+	window   time.Duration
+
+	// writes tracks the time of the last write per session key.
+	writes sync.Map
+}
+
+type replica struct {
+	host    string
+	pool    *pgxpool.Pool
+	healthy int32
+}
+
+func (r *replica) isHealthy() bool {
+	return atomic.LoadInt32(&r.healthy) == 1
+}
+
+// setStatus marks the replica healthy if err is nil, and unhealthy otherwise.
+func (r *replica) setStatus(err error) {
+	var v int32
+	if err == nil {
+		v = 1
+	}
+	if atomic.SwapInt32(&r.healthy, v) != v {
+		if err == nil {
+			logger.Info().Str("replica", r.host).Msg("db replica is healthy, adding to rotation")
+		} else {
+			logger.Warn().Err(err).Str("replica", r.host).Msg("db replica is unhealthy, removing from rotation")
+		}
+	}
+}
+
+func newRouter(cfg *config.Config, primary *pgxpool.Pool) (*router, error) {
+	rcfg := cfg.DB.Replicas
+
+	r := &router{primary: primary}
+
+	var err error
+	r.interval, err = parseDuration(rcfg.HealthCheckInterval, 5*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("data: config invalid db.replicas.health_check_interval value: %w", err)
+	}
+	r.maxLag, err = parseDuration(rcfg.MaxLag, 0)
+	if err != nil {
+		return nil, fmt.Errorf("data: config invalid db.replicas.max_lag value: %w", err)
+	}
+	r.window, err = parseDuration(rcfg.ReadYourWritesWindow, 5*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("data: config invalid db.replicas.read_your_writes_window value: %w", err)
+	}
+
+	for _, host := range rcfg.Hosts {
+		poolCfg, err := poolConfig(cfg, cfg.ReplicaDBString(host))
+		if err != nil {
+			return nil, fmt.Errorf("data: invalid db replica %q: %w", host, err)
+		}
+		if rcfg.MaxConns > 0 {
+			poolCfg.MaxConns = rcfg.MaxConns
+		}
+
+		db, err := pgkit.ConnectWithPGX(cfg.Service.Name, poolCfg)
+		if err != nil {
+			return nil, err
+		}
+		r.replicas = append(r.replicas, &replica{host: host, pool: db.Conn})
+	}
+
+	return r, nil
+}
+
+func (r *router) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	r.markWrite(ctx)
+	return r.primary.Exec(ctx, sql, args...)
+}
+
+func (r *router) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	if rep := r.reader(ctx, sql); rep != nil {
+		rows, err := rep.pool.Query(ctx, sql, args...)
+		if !r.shouldFallback(ctx, rep, err) {
+			return rows, err
+		}
+	}
+	if !isReadOnlyQuery(sql) {
+		r.markWrite(ctx)
+	}
+	return r.primary.Query(ctx, sql, args...)
+}
+
+func (r *router) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	if rep := r.reader(ctx, sql); rep != nil {
+		return &fallbackRow{r: r, rep: rep, ctx: ctx, sql: sql, args: args}
+	}
+	if !isReadOnlyQuery(sql) {
+		r.markWrite(ctx)
+	}
+	return r.primary.QueryRow(ctx, sql, args...)
+}
+
+// fallbackRow runs a single-row query on a replica and retries it on the
+// primary if the replica fails.
+type fallbackRow struct {
+	r    *router
+	rep  *replica
+	ctx  context.Context
+	sql  string
+	args []interface{}
+}
+
+func (f *fallbackRow) Scan(dest ...interface{}) error {
+	err := f.rep.pool.QueryRow(f.ctx, f.sql, f.args...).Scan(dest...)
+	if !f.r.shouldFallback(f.ctx, f.rep, err) {
+		return err
+	}
+	return f.r.primary.QueryRow(f.ctx, f.sql, f.args...).Scan(dest...)
+}
+
+// reader returns the replica to run sql on, or nil if it must run on the
+// primary.
+func (r *router) reader(ctx context.Context, sql string) *replica {
+	if len(r.replicas) == 0 || !isReadOnlyQuery(sql) {
+		return nil
+	}
+	if v, _ := ctx.Value(primaryCtxKey).(bool); v {
+		return nil
+	}
+	if key, ok := ctx.Value(sessionCtxKey).(string); ok {
+		if t, ok := r.writes.Load(key); ok && time.Since(t.(time.Time)) < r.window {
+			return nil
+		}
+	}
+
+	n := uint32(len(r.replicas))
+	start := atomic.AddUint32(&r.next, 1)
+	for i := uint32(0); i < n; i++ {
+		rep := r.replicas[(start+i)%n]
+		if rep.isHealthy() {
+			return rep
+		}
+	}
+	return nil
+}
+
+// shouldFallback reports whether a query which returned err on a replica
+// should be retried on the primary. Connection failures also take the
+// replica out of rotation until the next successful health check.
+func (r *router) shouldFallback(ctx context.Context, rep *replica, err error) bool {
+	if err == nil || errors.Is(err, pgx.ErrNoRows) || ctx.Err() != nil {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		rep.setStatus(err)
+	}
+	return true
+}
+
+func (r *router) markWrite(ctx context.Context) {
+	if r == nil || len(r.replicas) == 0 {
+		return
+	}
+	if key, ok := ctx.Value(sessionCtxKey).(string); ok && key != "" {
+		r.writes.Store(key, time.Now())
+	}
+}
+
+// run probes the replicas until ctx is done.
+func (r *router) run(ctx context.Context) error {
+	if len(r.replicas) == 0 {
+		<-ctx.Done()
+		return nil
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.checkReplicas(ctx)
+		r.pruneWrites()
+
+		select {
+		case <-ctx.Done():
+			for _, rep := range r.replicas {
+				rep.pool.Close()
+			}
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *router) checkReplicas(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, rep := range r.replicas {
+		wg.Add(1)
+		go func(rep *replica) {
+			defer wg.Done()
+			rep.setStatus(r.checkReplica(ctx, rep))
+		}(rep)
+	}
+	wg.Wait()
+}
+
+func (r *router) checkReplica(ctx context.Context, rep *replica) error {
+	ctx, cancel := context.WithTimeout(ctx, r.interval)
+	defer cancel()
+
+	if err := rep.pool.Ping(ctx); err != nil {
+		return err
+	}
+	if r.maxLag == 0 {
+		return nil
+	}
+
+	// Replay timestamps go stale on an idle primary, so a replica which has
+	// replayed everything it received is considered to have no lag.
+	var lag float64
+	err := rep.pool.QueryRow(ctx, `SELECT CASE
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END::float8`).Scan(&lag)
+	if err != nil {
+		return err
+	}
+	if time.Duration(lag*float64(time.Second)) > r.maxLag {
+		return fmt.Errorf("replication lag of %.1fs exceeds %s", lag, r.maxLag)
+	}
+	return nil
+}
+
+func (r *router) pruneWrites() {
+	r.writes.Range(func(key, t interface{}) bool {
+		if time.Since(t.(time.Time)) >= r.window {
+			r.writes.Delete(key)
+		}
+		return true
+	})
+}
+
+// isReadOnlyQuery reports whether sql is a plain SELECT which is safe to run
+// on a replica. Leading comments, like sqlc's "-- name:" header, are skipped.
+func isReadOnlyQuery(sql string) bool {
+	s := strings.TrimSpace(sql)
+	for strings.HasPrefix(s, "--") {
+		i := strings.IndexByte(s, '\n')
+		if i == -1 {
+			return false
+		}
+		s = strings.TrimSpace(s[i+1:])
+	}
+	if len(s) < 6 || !strings.EqualFold(s[:6], "SELECT") {
+		return false
+	}
+	upper := strings.ToUpper(s)
+	return !strings.Contains(upper, "FOR UPDATE") && !strings.Contains(upper, "FOR SHARE") && !strings.Contains(upper, "NEXTVAL(")
+}
@@ -7,22 +7,150 @@ package sqlc
 import (
 	"database/sql"
 	"time"
+
+	"github.com/jackc/pgtype"
 )
 
-type Comments struct {
+type AuditLog struct {
+	ID         int64       `json:"id"`
+	Actor      string      `json:"actor"`
+	Action     string      `json:"action"`
+	TargetType string      `json:"targetType"`
+	TargetID   string      `json:"targetID"`
+	Before     pgtype.JSON `json:"before"`
+	After      pgtype.JSON `json:"after"`
+	RequestID  string      `json:"requestID"`
+	Ip         string      `json:"ip"`
+	CreatedAt  time.Time   `json:"createdAt"`
+	PrevHash   string      `json:"prevHash"`
+	Hash       string      `json:"hash"`
+}
+
+type Blocklist struct {
 	ID        int32     `json:"id"`
-	PostID    int32     `json:"postID"`
-	Author    string    `json:"author"`
-	Content   string    `json:"content"`
+	Kind      string    `json:"kind"`
+	Value     string    `json:"value"`
+	CreatedBy string    `json:"createdBy"`
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+type BusSpill struct {
+	ID        int64        `json:"id"`
+	Payload   pgtype.JSONB `json:"payload"`
+	CreatedAt time.Time    `json:"createdAt"`
+}
+
+type ChatMessages struct {
+	ID           int64     `json:"id"`
+	ContractAddr string    `json:"contractAddr"`
+	Author       string    `json:"author"`
+	Content      string    `json:"content"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type ChatPresence struct {
+	ContractAddr string    `json:"contractAddr"`
+	Account      string    `json:"account"`
+	ConnID       string    `json:"connID"`
+	LastSeen     time.Time `json:"lastSeen"`
+}
+
+type CommentHashtags struct {
+	CommentID int32  `json:"commentID"`
+	Tag       string `json:"tag"`
+	PostID    int32  `json:"postID"`
+}
+
+type CommentMentions struct {
+	CommentID int32  `json:"commentID"`
+	Handle    string `json:"handle"`
+	Account   string `json:"account"`
+}
+
+type Comments struct {
+	ID         int32         `json:"id"`
+	PostID     int32         `json:"postID"`
+	Author     string        `json:"author"`
+	Content    string        `json:"content"`
+	CreatedAt  time.Time     `json:"createdAt"`
+	ParentID   sql.NullInt32 `json:"parentID"`
+	Visibility string        `json:"visibility"`
+}
+
+type Follows struct {
+	Follower  string    `json:"follower"`
+	Followee  string    `json:"followee"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type HeldComments struct {
+	CommentID int32        `json:"commentID"`
+	Score     float32      `json:"score"`
+	Reasons   []string     `json:"reasons"`
+	Event     pgtype.JSONB `json:"event"`
+	CreatedAt time.Time    `json:"createdAt"`
+}
+
+type Jobs struct {
+	ID          int64          `json:"id"`
+	Kind        string         `json:"kind"`
+	Payload     pgtype.JSONB   `json:"payload"`
+	UniqueKey   sql.NullString `json:"uniqueKey"`
+	Status      string         `json:"status"`
+	Attempts    int32          `json:"attempts"`
+	MaxAttempts int32          `json:"maxAttempts"`
+	LastError   sql.NullString `json:"lastError"`
+	RunAt       time.Time      `json:"runAt"`
+	LockedAt    sql.NullTime   `json:"lockedAt"`
+	LockedBy    sql.NullString `json:"lockedBy"`
+	CreatedAt   time.Time      `json:"createdAt"`
+}
+
 type Likes struct {
 	ID      int32  `json:"id"`
 	PostID  int32  `json:"postID"`
 	LikedBy string `json:"likedBy"`
 }
 
+type NotificationPreferences struct {
+	Account   string    `json:"account"`
+	Likes     bool      `json:"likes"`
+	Comments  bool      `json:"comments"`
+	Replies   bool      `json:"replies"`
+	Follows   bool      `json:"follows"`
+	Mentions  bool      `json:"mentions"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type Notifications struct {
+	ID        int64         `json:"id"`
+	Recipient string        `json:"recipient"`
+	Kind      string        `json:"kind"`
+	GroupKey  string        `json:"groupKey"`
+	PostID    sql.NullInt32 `json:"postID"`
+	CommentID sql.NullInt32 `json:"commentID"`
+	Actors    []string      `json:"actors"`
+	ReadAt    sql.NullTime  `json:"readAt"`
+	CreatedAt time.Time     `json:"createdAt"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+}
+
+type Outbox struct {
+	ID        int64        `json:"id"`
+	Txid      int64        `json:"txid"`
+	Type      string       `json:"type"`
+	Actor     string       `json:"actor"`
+	Payload   pgtype.JSONB `json:"payload"`
+	CreatedAt time.Time    `json:"createdAt"`
+}
+
+type OutboxCheckpoints struct {
+	Subscriber string    `json:"subscriber"`
+	LastTxid   int64     `json:"lastTxid"`
+	LastID     int64     `json:"lastID"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
 type Posts struct {
 	ID           int32         `json:"id"`
 	ContractAddr string        `json:"contractAddr"`
@@ -31,12 +159,100 @@ type Posts struct {
 	CommentCount sql.NullInt32 `json:"commentCount"`
 	Author       string        `json:"author"`
 	CreatedAt    time.Time     `json:"createdAt"`
+	Visibility   string        `json:"visibility"`
+}
+
+type PushSubscriptions struct {
+	ID        int64     `json:"id"`
+	Account   string    `json:"account"`
+	Endpoint  string    `json:"endpoint"`
+	P256dh    string    `json:"p256dh"`
+	Auth      string    `json:"auth"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type Reports struct {
+	ID         int64          `json:"id"`
+	Reporter   string         `json:"reporter"`
+	TargetType string         `json:"targetType"`
+	TargetID   string         `json:"targetID"`
+	Reason     string         `json:"reason"`
+	Details    sql.NullString `json:"details"`
+	Status     string         `json:"status"`
+	Action     sql.NullString `json:"action"`
+	ResolvedBy sql.NullString `json:"resolvedBy"`
+	ResolvedAt sql.NullTime   `json:"resolvedAt"`
+	CreatedAt  time.Time      `json:"createdAt"`
+}
+
+type Reputation struct {
+	Account   string    `json:"account"`
+	Score     int32     `json:"score"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type SearchDocuments struct {
+	Kind   string      `json:"kind"`
+	Key    string      `json:"key"`
+	Vector interface{} `json:"vector"`
+}
+
+type TokenMetadata struct {
+	ContractAddr string         `json:"contractAddr"`
+	TokenID      int32          `json:"tokenID"`
+	Name         sql.NullString `json:"name"`
+	Description  sql.NullString `json:"description"`
+	Image        sql.NullString `json:"image"`
+	Error        sql.NullString `json:"error"`
+	FetchedAt    time.Time      `json:"fetchedAt"`
+}
+
+type UserBlocks struct {
+	Blocker   string    `json:"blocker"`
+	Blocked   string    `json:"blocked"`
+	Kind      string    `json:"kind"`
+	CreatedAt time.Time `json:"createdAt"`
 }
 
 type Users struct {
-	Addr      string       `json:"addr"`
-	Admin     sql.NullBool `json:"admin"`
-	Name      string       `json:"name"`
-	Pfp       interface{}  `json:"pfp"`
-	RandomMsg string       `json:"randomMsg"`
+	Addr            string         `json:"addr"`
+	Admin           sql.NullBool   `json:"admin"`
+	Name            string         `json:"name"`
+	Pfp             interface{}    `json:"pfp"`
+	RandomMsg       string         `json:"randomMsg"`
+	Email           sql.NullString `json:"email"`
+	EmailVerifiedAt sql.NullTime   `json:"emailVerifiedAt"`
+	Digest          string         `json:"digest"`
+	DigestSentAt    sql.NullTime   `json:"digestSentAt"`
+	SuspendedAt     sql.NullTime   `json:"suspendedAt"`
+	SuspendedUntil  sql.NullTime   `json:"suspendedUntil"`
+	CreatedAt       sql.NullTime   `json:"createdAt"`
+}
+
+type WebhookDeliveries struct {
+	ID         int64          `json:"id"`
+	WebhookID  int64          `json:"webhookID"`
+	DeliveryID string         `json:"deliveryID"`
+	EventType  string         `json:"eventType"`
+	Payload    pgtype.JSONB   `json:"payload"`
+	StatusCode sql.NullInt32  `json:"statusCode"`
+	Error      sql.NullString `json:"error"`
+	Response   sql.NullString `json:"response"`
+	DurationMs int32          `json:"durationMs"`
+	Success    bool           `json:"success"`
+	CreatedAt  time.Time      `json:"createdAt"`
+}
+
+type Webhooks struct {
+	ID           int64        `json:"id"`
+	Owner        string       `json:"owner"`
+	ContractAddr string       `json:"contractAddr"`
+	Url          string       `json:"url"`
+	EventTypes   []string     `json:"eventTypes"`
+	Secret       string       `json:"secret"`
+	Enabled      bool         `json:"enabled"`
+	FailureCount int32        `json:"failureCount"`
+	DisabledAt   sql.NullTime `json:"disabledAt"`
+	CreatedAt    time.Time    `json:"createdAt"`
+	UpdatedAt    time.Time    `json:"updatedAt"`
 }
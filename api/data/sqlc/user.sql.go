@@ -7,10 +7,12 @@ package sqlc
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 const createUser = `-- name: CreateUser :one
-INSERT INTO users (addr, name, random_msg) VALUES ($1, $2, $3) RETURNING addr, admin, name, pfp, random_msg
+INSERT INTO users (addr, name, random_msg) VALUES ($1, $2, $3) RETURNING addr, admin, name, pfp, random_msg, email, email_verified_at, digest, digest_sent_at, suspended_at, suspended_until, created_at
 `
 
 type CreateUserParams struct {
@@ -20,7 +22,7 @@ type CreateUserParams struct {
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (Users, error) {
-	row := q.db.QueryRowContext(ctx, createUser, arg.Addr, arg.Name, arg.RandomMsg)
+	row := q.db.QueryRow(ctx, createUser, arg.Addr, arg.Name, arg.RandomMsg)
 	var i Users
 	err := row.Scan(
 		&i.Addr,
@@ -28,16 +30,23 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (Users,
 		&i.Name,
 		&i.Pfp,
 		&i.RandomMsg,
+		&i.Email,
+		&i.EmailVerifiedAt,
+		&i.Digest,
+		&i.DigestSentAt,
+		&i.SuspendedAt,
+		&i.SuspendedUntil,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT addr, admin, name, pfp, random_msg FROM users WHERE addr = $1
+SELECT addr, admin, name, pfp, random_msg, email, email_verified_at, digest, digest_sent_at, suspended_at, suspended_until, created_at FROM users WHERE addr = $1
 `
 
 func (q *Queries) GetUser(ctx context.Context, addr string) (Users, error) {
-	row := q.db.QueryRowContext(ctx, getUser, addr)
+	row := q.db.QueryRow(ctx, getUser, addr)
 	var i Users
 	err := row.Scan(
 		&i.Addr,
@@ -45,12 +54,202 @@ func (q *Queries) GetUser(ctx context.Context, addr string) (Users, error) {
 		&i.Name,
 		&i.Pfp,
 		&i.RandomMsg,
+		&i.Email,
+		&i.EmailVerifiedAt,
+		&i.Digest,
+		&i.DigestSentAt,
+		&i.SuspendedAt,
+		&i.SuspendedUntil,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
+const liftSuspension = `-- name: LiftSuspension :execrows
+UPDATE users SET suspended_at = NULL, suspended_until = NULL WHERE addr = $1 AND suspended_at IS NOT NULL
+`
+
+func (q *Queries) LiftSuspension(ctx context.Context, addr string) (int64, error) {
+	result, err := q.db.Exec(ctx, liftSuspension, addr)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const listDueDigests = `-- name: ListDueDigests :many
+SELECT addr, digest, digest_sent_at FROM users
+WHERE email_verified_at IS NOT NULL
+  AND ((digest = 'daily' AND (digest_sent_at IS NULL OR digest_sent_at < $1::timestamp))
+    OR (digest = 'weekly' AND (digest_sent_at IS NULL OR digest_sent_at < $2::timestamp)))
+ORDER BY addr
+`
+
+type ListDueDigestsParams struct {
+	DailyBefore  time.Time `json:"dailyBefore"`
+	WeeklyBefore time.Time `json:"weeklyBefore"`
+}
+
+type ListDueDigestsRow struct {
+	Addr         string       `json:"addr"`
+	Digest       string       `json:"digest"`
+	DigestSentAt sql.NullTime `json:"digestSentAt"`
+}
+
+func (q *Queries) ListDueDigests(ctx context.Context, arg ListDueDigestsParams) ([]ListDueDigestsRow, error) {
+	rows, err := q.db.Query(ctx, listDueDigests, arg.DailyBefore, arg.WeeklyBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListDueDigestsRow
+	for rows.Next() {
+		var i ListDueDigestsRow
+		if err := rows.Scan(&i.Addr, &i.Digest, &i.DigestSentAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersByAddr = `-- name: ListUsersByAddr :many
+SELECT addr, name, coalesce(pfp::text, '')::text AS pfp FROM users WHERE addr = ANY($1::text[])
+`
+
+type ListUsersByAddrRow struct {
+	Addr string `json:"addr"`
+	Name string `json:"name"`
+	Pfp  string `json:"pfp"`
+}
+
+func (q *Queries) ListUsersByAddr(ctx context.Context, addrs []string) ([]ListUsersByAddrRow, error) {
+	rows, err := q.db.Query(ctx, listUsersByAddr, addrs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUsersByAddrRow
+	for rows.Next() {
+		var i ListUsersByAddrRow
+		if err := rows.Scan(&i.Addr, &i.Name, &i.Pfp); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markDigestSent = `-- name: MarkDigestSent :exec
+UPDATE users SET digest_sent_at = $2 WHERE addr = $1
+`
+
+type MarkDigestSentParams struct {
+	Addr         string       `json:"addr"`
+	DigestSentAt sql.NullTime `json:"digestSentAt"`
+}
+
+func (q *Queries) MarkDigestSent(ctx context.Context, arg MarkDigestSentParams) error {
+	_, err := q.db.Exec(ctx, markDigestSent, arg.Addr, arg.DigestSentAt)
+	return err
+}
+
+const resolveMentions = `-- name: ResolveMentions :many
+SELECT addr::text AS handle, addr::text AS account FROM users WHERE addr = ANY($1::text[])
+UNION ALL
+SELECT lower(name) AS handle, min(addr)::text AS account FROM users WHERE lower(name) = ANY($1::text[])
+GROUP BY lower(name) HAVING count(*) = 1
+`
+
+type ResolveMentionsRow struct {
+	Handle  string `json:"handle"`
+	Account string `json:"account"`
+}
+
+// Resolves mentioned handles, lowercase addresses or names, to the accounts
+// of the users. A name shared by several users is ambiguous and doesn't
+// resolve.
+func (q *Queries) ResolveMentions(ctx context.Context, handles []string) ([]ResolveMentionsRow, error) {
+	rows, err := q.db.Query(ctx, resolveMentions, handles)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ResolveMentionsRow
+	for rows.Next() {
+		var i ResolveMentionsRow
+		if err := rows.Scan(&i.Handle, &i.Account); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setUserDigest = `-- name: SetUserDigest :execrows
+UPDATE users SET digest = $2 WHERE addr = $1
+`
+
+type SetUserDigestParams struct {
+	Addr   string `json:"addr"`
+	Digest string `json:"digest"`
+}
+
+func (q *Queries) SetUserDigest(ctx context.Context, arg SetUserDigestParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setUserDigest, arg.Addr, arg.Digest)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setUserEmail = `-- name: SetUserEmail :execrows
+UPDATE users SET email = $2, email_verified_at = NULL WHERE addr = $1
+`
+
+type SetUserEmailParams struct {
+	Addr  string         `json:"addr"`
+	Email sql.NullString `json:"email"`
+}
+
+// Changing the email address resets its verification.
+func (q *Queries) SetUserEmail(ctx context.Context, arg SetUserEmailParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setUserEmail, arg.Addr, arg.Email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const suspendUser = `-- name: SuspendUser :execrows
+UPDATE users SET suspended_at = CURRENT_TIMESTAMP, suspended_until = $2 WHERE addr = $1
+`
+
+type SuspendUserParams struct {
+	Addr           string       `json:"addr"`
+	SuspendedUntil sql.NullTime `json:"suspendedUntil"`
+}
+
+// Suspends an account until suspended_until, or until lifted when null.
+func (q *Queries) SuspendUser(ctx context.Context, arg SuspendUserParams) (int64, error) {
+	result, err := q.db.Exec(ctx, suspendUser, arg.Addr, arg.SuspendedUntil)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateUser = `-- name: UpdateUser :one
-UPDATE users SET name = $2, pfp=$3, random_msg=$4 WHERE addr = $1 RETURNING addr, admin, name, pfp, random_msg
+UPDATE users SET name = $2, pfp=$3, random_msg=$4 WHERE addr = $1 RETURNING addr, admin, name, pfp, random_msg, email, email_verified_at, digest, digest_sent_at, suspended_at, suspended_until, created_at
 `
 
 type UpdateUserParams struct {
@@ -61,7 +260,7 @@ type UpdateUserParams struct {
 }
 
 func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (Users, error) {
-	row := q.db.QueryRowContext(ctx, updateUser,
+	row := q.db.QueryRow(ctx, updateUser,
 		arg.Addr,
 		arg.Name,
 		arg.Pfp,
@@ -74,6 +273,31 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (Users,
 		&i.Name,
 		&i.Pfp,
 		&i.RandomMsg,
+		&i.Email,
+		&i.EmailVerifiedAt,
+		&i.Digest,
+		&i.DigestSentAt,
+		&i.SuspendedAt,
+		&i.SuspendedUntil,
+		&i.CreatedAt,
 	)
 	return i, err
 }
+
+const verifyUserEmail = `-- name: VerifyUserEmail :execrows
+UPDATE users SET email_verified_at = CURRENT_TIMESTAMP
+WHERE addr = $1 AND email = $2 AND email_verified_at IS NULL
+`
+
+type VerifyUserEmailParams struct {
+	Addr  string         `json:"addr"`
+	Email sql.NullString `json:"email"`
+}
+
+func (q *Queries) VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (int64, error) {
+	result, err := q.db.Exec(ctx, verifyUserEmail, arg.Addr, arg.Email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
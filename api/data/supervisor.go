@@ -0,0 +1,81 @@
+package data
+
+import (
+	"context"
+	"math/rand"
+	"sync/atomic"
+	"time"
+)
+
+// supervisor keeps probing the primary database, flipping the connected state
+// as it goes down and comes back up, so callers can fail fast with
+// IsConnected while Postgres is unreachable.
+type supervisor struct {
+	// interval between probes while the database is reachable.
+	interval time.Duration
+
+	// minBackoff and maxBackoff bound the delay between probes while the
+	// database is unreachable.
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+func (s *supervisor) run(ctx context.Context) error {
+	failures := 0
+	for {
+		err := s.probe(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		var delay time.Duration
+		if err == nil {
+			if atomic.SwapInt32(&connected, 1) != 1 {
+				if failures > 0 {
+					logger.Info().Int("attempts", failures+1).Msg("connection to the database restored.")
+				} else {
+					logger.Info().Msg("connection to the database established.")
+				}
+			}
+			failures = 0
+			delay = s.interval
+		} else {
+			if atomic.SwapInt32(&connected, 0) == 1 {
+				logger.Error().Err(err).Msg("lost connection to the database, reconnecting..")
+			}
+			delay = backoff(failures, s.minBackoff, s.maxBackoff)
+			failures++
+			logger.Warn().Err(err).Int("attempt", failures).Dur("retryIn", delay).Msg("could not connect to the database. retrying..")
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+func (s *supervisor) probe(ctx context.Context) error {
+	timeout := s.interval
+	if timeout > 5*time.Second {
+		timeout = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	return pool.Ping(ctx)
+}
+
+// backoff returns the delay before the given retry attempt, doubling base on
+// every attempt up to max. Half of the delay is randomized so concurrent
+// retries spread out.
+func backoff(attempt int, base, max time.Duration) time.Duration {
+	delay := base << uint(attempt)
+	if delay <= 0 || delay > max {
+		delay = max
+	}
+	half := int64(delay) / 2
+	return time.Duration(half + rand.Int63n(half+1))
+}
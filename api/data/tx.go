@@ -0,0 +1,128 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v4"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/nfteseum/nfteseum-learning-project/api/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	// TxMaxRetries is the number of times a transaction is retried after a
+	// serialization failure or deadlock before giving up.
+	TxMaxRetries = 5
+
+	// TxRetryBaseDelay is the initial backoff between transaction retries,
+	// doubled on every attempt.
+	TxRetryBaseDelay = 20 * time.Millisecond
+
+	// TxRetryMaxDelay caps the backoff between transaction retries.
+	TxRetryMaxDelay = 1 * time.Second
+
+	// TxSlowThreshold is the duration above which a transaction is logged as slow.
+	TxSlowThreshold = 500 * time.Millisecond
+)
+
+var (
+	ErrNotConnected = errors.New("data: database is not connected")
+)
+
+// WithTx runs fn inside a database transaction, committing if fn returns nil
+// and rolling back otherwise. Transactions that fail with a serialization
+// failure or deadlock are retried with exponential backoff, so fn must be
+// safe to run more than once. Cancelling ctx aborts the transaction.
+//
+// The isolation level and access mode are set by opts, the zero value uses
+// the server defaults.
+func WithTx(ctx context.Context, opts pgx.TxOptions, fn func(q *sqlc.Queries) error) error {
+	if pool == nil || !IsConnected() {
+		return ErrNotConnected
+	}
+
+	ctx, span := tracing.Tracer().Start(ctx, "db transaction", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	start := time.Now()
+	attempt := 0
+	for {
+		err := runTx(ctx, opts, fn)
+		if err == nil && opts.AccessMode != pgx.ReadOnly {
+			dbRouter.markWrite(ctx)
+		}
+		if err == nil || !isRetryableTxErr(err) || attempt >= TxMaxRetries {
+			logSlowTx(start, attempt, err)
+			span.SetAttributes(attribute.Int("db.tx.retries", attempt))
+			tracing.RecordError(span, err)
+			return err
+		}
+
+		delay := txBackoff(attempt)
+		attempt++
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func runTx(ctx context.Context, opts pgx.TxOptions, fn func(q *sqlc.Queries) error) (err error) {
+	tx, err := pool.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback(ctx)
+			panic(p)
+		}
+		if err != nil {
+			tx.Rollback(ctx)
+		}
+	}()
+
+	if err = fn(sqlc.New(instrument(tx))); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// isRetryableTxErr reports whether err is a serialization failure or
+// deadlock, in which case the whole transaction can safely be retried.
+func isRetryableTxErr(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	switch pgErr.Code {
+	case pgerrcode.SerializationFailure, pgerrcode.DeadlockDetected:
+		return true
+	default:
+		return false
+	}
+}
+
+// txBackoff returns the delay before the given retry attempt, using
+// exponential backoff with jitter.
+func txBackoff(attempt int) time.Duration {
+	return backoff(attempt, TxRetryBaseDelay, TxRetryMaxDelay)
+}
+
+func logSlowTx(start time.Time, retries int, err error) {
+	elapsed := time.Since(start)
+	if elapsed < TxSlowThreshold {
+		return
+	}
+	logger.Warn().Err(err).Dur("duration", elapsed).Int("retries", retries).Msg("slow transaction")
+}
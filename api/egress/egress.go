@@ -0,0 +1,48 @@
+// Package egress makes the http requests to urls given by users, which must
+// not reach the internal network.
+package egress
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/tracing"
+)
+
+// ErrPrivateAddress is returned when a url resolves to a loopback, private or
+// otherwise non-public address.
+var ErrPrivateAddress = errors.New("egress: url resolves to a non-public address")
+
+// Transport returns a transport dialing only public addresses, unless
+// allowPrivate is set, ie. in development mode. Proxies aren't used, as they
+// would be dialed instead.
+func Transport(timeout time.Duration, allowPrivate bool) http.RoundTripper {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		// Checked once resolved, so a public name can't point at the
+		// internal network.
+		dialer.Control = publicOnly
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return tracing.Transport(transport)
+}
+
+// publicOnly is the net.Dialer Control refusing to connect to non-public
+// addresses.
+func publicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return ErrPrivateAddress
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+// Package emails composes the emails of the api: the activity digests and
+// the address verifications.
+package emails
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"net/url"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/config"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/nfteseum/nfteseum-learning-project/api/links"
+	"github.com/nfteseum/nfteseum-learning-project/api/mail"
+	"github.com/nfteseum/nfteseum-learning-project/api/notifications"
+)
+
+// Paths of the signed links handled by the rpc server.
+const (
+	VerifyPath      = "/email/verify"
+	UnsubscribePath = "/email/unsubscribe"
+)
+
+// Digest frequencies, as stored in users.digest.
+const (
+	DigestOff    = "off"
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// VerifyTTL is how long verification links are valid.
+const VerifyTTL = 48 * time.Hour
+
+// maxDigestItems caps the notifications summarised in a digest.
+const maxDigestItems = 20
+
+//go:embed templates
+var templates embed.FS
+
+type Builder struct {
+	links  *links.Signer
+	appURL string
+
+	digestText *texttemplate.Template
+	digestHTML *htmltemplate.Template
+	verifyText *texttemplate.Template
+}
+
+func NewBuilder(cfg *config.Config, signer *links.Signer) (*Builder, error) {
+	b := &Builder{links: signer, appURL: cfg.Service.URL}
+
+	var err error
+	b.digestText, err = texttemplate.ParseFS(templates, "templates/digest.txt")
+	if err != nil {
+		return nil, fmt.Errorf("emails: %w", err)
+	}
+	b.digestHTML, err = htmltemplate.ParseFS(templates, "templates/digest.html")
+	if err != nil {
+		return nil, fmt.Errorf("emails: %w", err)
+	}
+	b.verifyText, err = texttemplate.ParseFS(templates, "templates/verify.txt")
+	if err != nil {
+		return nil, fmt.Errorf("emails: %w", err)
+	}
+	return b, nil
+}
+
+// Digest returns the digest of the activity directed at account since the
+// given time, or nil if there was none.
+func (b *Builder) Digest(ctx context.Context, account, email, frequency string, since time.Time) (*mail.Message, error) {
+	rows, err := data.DB.ListNotificationsSince(ctx, sqlc.ListNotificationsSinceParams{
+		Recipient:        account,
+		Since:            since,
+		MaxNotifications: maxDigestItems,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	unsubscribe := b.UnsubscribeURL(account)
+	vars := map[string]interface{}{
+		"Period":         "today",
+		"Frequency":      frequency,
+		"Items":          summaries(rows),
+		"AppURL":         b.appURL,
+		"UnsubscribeURL": unsubscribe,
+	}
+	if frequency == DigestWeekly {
+		vars["Period"] = "this week"
+	}
+
+	var text, html strings.Builder
+	if err := b.digestText.Execute(&text, vars); err != nil {
+		return nil, err
+	}
+	if err := b.digestHTML.Execute(&html, vars); err != nil {
+		return nil, err
+	}
+
+	return &mail.Message{
+		To:      email,
+		Subject: fmt.Sprintf("Your %s nfteseum digest", frequency),
+		Text:    text.String(),
+		HTML:    html.String(),
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<" + unsubscribe + ">",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	}, nil
+}
+
+// Verification returns the email verifying account owns email.
+func (b *Builder) Verification(account, email string) (*mail.Message, error) {
+	verify := b.links.URL(VerifyPath, url.Values{"account": {account}, "email": {email}}, VerifyTTL)
+
+	var text strings.Builder
+	err := b.verifyText.Execute(&text, map[string]interface{}{
+		"Email":     email,
+		"VerifyURL": verify,
+		"ValidFor":  "48 hours",
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &mail.Message{
+		To:      email,
+		Subject: "Confirm your email address",
+		Text:    text.String(),
+	}, nil
+}
+
+// UnsubscribeURL returns the one-click link turning off the digest of
+// account. It doesn't expire.
+func (b *Builder) UnsubscribeURL(account string) string {
+	return b.links.URL(UnsubscribePath, url.Values{"account": {account}, "list": {"digest"}}, 0)
+}
+
+func summaries(rows []sqlc.ListNotificationsSinceRow) []string {
+	items := make([]string, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, notifications.Summary(row.Kind, row.Actors, row.ActorCount, row.PostID.Int32))
+	}
+	return items
+}
@@ -0,0 +1,107 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/bus"
+)
+
+// PostTopic is the bus topic of the activity on a post.
+func PostTopic(postID int32) string {
+	return fmt.Sprintf("post:%d", postID)
+}
+
+// UserTopic is the bus topic of the activity directed at a user.
+func UserTopic(addr string) string {
+	return "user:" + strings.ToLower(addr)
+}
+
+// Topics returns the bus topics an event is published on: the topic of the
+// post it concerns, and the topics of the users it notifies, save its actor.
+func Topics(ev *Event) ([]string, error) {
+	switch ev.Type {
+	case PostLiked:
+		var p PostLike
+		if err := ev.Decode(&p); err != nil {
+			return nil, err
+		}
+		return userTopics(ev.Actor, []string{PostTopic(p.PostID)}, p.PostAuthor), nil
+
+	case PostUnliked:
+		var p PostLike
+		if err := ev.Decode(&p); err != nil {
+			return nil, err
+		}
+		return []string{PostTopic(p.PostID)}, nil
+
+	case CommentCreated:
+		var p Comment
+		if err := ev.Decode(&p); err != nil {
+			return nil, err
+		}
+		users := append([]string{p.PostAuthor, p.ParentAuthor}, p.Mentions...)
+		return userTopics(ev.Actor, []string{PostTopic(p.PostID)}, users...), nil
+
+	case UserFollowed, UserUnfollowed:
+		var p Follow
+		if err := ev.Decode(&p); err != nil {
+			return nil, err
+		}
+		return userTopics(ev.Actor, nil, p.Followee), nil
+
+	case ContentModerated:
+		var p Moderation
+		if err := ev.Decode(&p); err != nil {
+			return nil, err
+		}
+		return userTopics(ev.Actor, nil, p.Reporters...), nil
+	}
+	return nil, nil
+}
+
+// userTopics appends the topics of users to topics, once each, leaving out
+// the actor and empty accounts.
+func userTopics(actor string, topics []string, users ...string) []string {
+	for _, u := range users {
+		if u == "" || strings.EqualFold(u, actor) {
+			continue
+		}
+		topic := UserTopic(u)
+		dup := false
+		for _, t := range topics {
+			if t == topic {
+				dup = true
+				break
+			}
+		}
+		if !dup {
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
+
+// Forward returns a relay handler publishing every event on its topics of the
+// bus, so the clients connected to any replica can be notified.
+func Forward(b bus.Bus) Handler {
+	return func(ctx context.Context, ev *Event) error {
+		topics, err := Topics(ev)
+		if err != nil {
+			// Retrying won't fix a malformed event.
+			return nil
+		}
+		msg, err := json.Marshal(ev)
+		if err != nil {
+			return nil
+		}
+		for _, topic := range topics {
+			if err := b.Publish(ctx, topic, msg); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,132 @@
+// Package events holds the domain events of the api. Events are written to
+// the outbox table in the same transaction as the change they describe, and
+// relayed from there to the subscribers, so an event is published if and only
+// if its change is committed.
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+)
+
+type Type string
+
+const (
+	PostLiked      Type = "post.liked"
+	PostUnliked    Type = "post.unliked"
+	CommentCreated Type = "comment.created"
+	UserFollowed   Type = "user.followed"
+	UserUnfollowed Type = "user.unfollowed"
+
+	ContentModerated Type = "content.moderated"
+)
+
+type Event struct {
+	ID        int64           `json:"id"`
+	Txid      int64           `json:"txid"`
+	Type      Type            `json:"type"`
+	Actor     string          `json:"actor"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// Decode unmarshals the data of the event into v, which should be the
+// payload type of the event.
+func (e *Event) Decode(v interface{}) error {
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return fmt.Errorf("events: invalid %s payload: %w", e.Type, err)
+	}
+	return nil
+}
+
+// PostLike is the payload of PostLiked and PostUnliked.
+type PostLike struct {
+	PostID     int32  `json:"postID"`
+	PostAuthor string `json:"postAuthor"`
+	Contract   string `json:"contract"`
+}
+
+// Comment is the payload of CommentCreated. ParentID and ParentAuthor are
+// only set for replies, and Mentions holds the accounts mentioned in the
+// content, but its author.
+type Comment struct {
+	CommentID    int32    `json:"commentID"`
+	PostID       int32    `json:"postID"`
+	PostAuthor   string   `json:"postAuthor"`
+	Contract     string   `json:"contract"`
+	ParentID     int32    `json:"parentID,omitempty"`
+	ParentAuthor string   `json:"parentAuthor,omitempty"`
+	Content      string   `json:"content"`
+	Mentions     []string `json:"mentions,omitempty"`
+}
+
+// Follow is the payload of UserFollowed and UserUnfollowed.
+type Follow struct {
+	Followee string `json:"followee"`
+}
+
+// Moderation is the payload of ContentModerated, published when action was
+// taken on reported content. PostID and CommentID are set for posts and
+// comments, and Reporters holds the accounts whose reports were resolved.
+type Moderation struct {
+	TargetType string   `json:"targetType"`
+	TargetID   string   `json:"targetID"`
+	Action     string   `json:"action"`
+	PostID     int32    `json:"postID,omitempty"`
+	CommentID  int32    `json:"commentID,omitempty"`
+	Reporters  []string `json:"reporters"`
+}
+
+// Publish writes an event to the outbox using tx, which must be the
+// transaction making the change the event describes.
+func Publish(ctx context.Context, tx *sqlc.Queries, typ Type, actor string, data interface{}) (*Event, error) {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("events: failed to encode %s payload: %w", typ, err)
+	}
+
+	row, err := tx.InsertOutboxEvent(ctx, sqlc.InsertOutboxEventParams{
+		Type:    string(typ),
+		Actor:   actor,
+		Payload: pgtype.JSONB{Bytes: payload, Status: pgtype.Present},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fromRow(&row), nil
+}
+
+// List returns up to limit events committed after the (txid, id) position of
+// an event, in commit order.
+func List(ctx context.Context, afterTxid, afterID int64, limit int32) ([]*Event, error) {
+	rows, err := data.DB.ListOutboxEvents(data.WithPrimary(ctx), sqlc.ListOutboxEventsParams{
+		AfterTxid: afterTxid,
+		AfterID:   afterID,
+		MaxEvents: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	evs := make([]*Event, len(rows))
+	for i := range rows {
+		evs[i] = fromRow(&rows[i])
+	}
+	return evs, nil
+}
+
+func fromRow(row *sqlc.Outbox) *Event {
+	return &Event{
+		ID:        row.ID,
+		Txid:      row.Txid,
+		Type:      Type(row.Type),
+		Actor:     row.Actor,
+		Data:      row.Payload.Bytes,
+		CreatedAt: row.CreatedAt,
+	}
+}
@@ -0,0 +1,225 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/config"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/rs/zerolog"
+	"golang.org/x/sync/errgroup"
+)
+
+// Handler consumes an event. Returning an error retries the same event with
+// backoff, holding back the later events of the subscriber, so errors which
+// can't be fixed by retrying should be logged and swallowed instead.
+type Handler func(ctx context.Context, ev *Event) error
+
+// Relay delivers the events of the outbox to its subscribers, in commit
+// order and at least once: each subscriber keeps its own checkpoint, saved
+// after its handler succeeded, so handlers must be idempotent.
+//
+// Only one relay may run at a time, so it's meant to run on the leader.
+type Relay struct {
+	log       zerolog.Logger
+	poll      time.Duration
+	retention time.Duration
+	batchSize int32
+
+	subscribers []*subscriber
+}
+
+type subscriber struct {
+	name  string
+	fn    Handler
+	types map[Type]bool
+}
+
+func NewRelay(cfg *config.Config, log zerolog.Logger) (*Relay, error) {
+	r := &Relay{
+		log:       log.With().Str("ps", "events").Logger(),
+		poll:      500 * time.Millisecond,
+		retention: 7 * 24 * time.Hour,
+		batchSize: 100,
+	}
+
+	var err error
+	if cfg.Events.PollInterval != "" {
+		r.poll, err = time.ParseDuration(cfg.Events.PollInterval)
+		if err != nil {
+			return nil, fmt.Errorf("events: config invalid events.poll_interval value: %w", err)
+		}
+	}
+	if cfg.Events.Retention != "" {
+		r.retention, err = time.ParseDuration(cfg.Events.Retention)
+		if err != nil {
+			return nil, fmt.Errorf("events: config invalid events.retention value: %w", err)
+		}
+	}
+	return r, nil
+}
+
+// Subscribe registers fn under name to receive the events of the given types,
+// or every event if none are given. The name identifies the checkpoint of the
+// subscriber, so it must stay the same across deploys. A new subscriber
+// starts from the oldest event still retained in the outbox.
+func (r *Relay) Subscribe(name string, fn Handler, types ...Type) {
+	s := &subscriber{name: name, fn: fn}
+	if len(types) > 0 {
+		s.types = map[Type]bool{}
+		for _, t := range types {
+			s.types[t] = true
+		}
+	}
+	r.subscribers = append(r.subscribers, s)
+}
+
+// Run relays the events to every subscriber until ctx is done.
+func (r *Relay) Run(ctx context.Context) error {
+	// The outbox is read from the primary only, as the visibility of
+	// transactions on a replica lags behind.
+	ctx = data.WithPrimary(ctx)
+
+	g, ctx := errgroup.WithContext(ctx)
+	for _, s := range r.subscribers {
+		s := s
+		g.Go(func() error {
+			return r.runSubscriber(ctx, s)
+		})
+	}
+	g.Go(func() error {
+		return r.runPruner(ctx)
+	})
+	return g.Wait()
+}
+
+func (r *Relay) runSubscriber(ctx context.Context, s *subscriber) error {
+	log := r.log.With().Str("subscriber", s.name).Logger()
+
+	var checkpoint *sqlc.OutboxCheckpoints
+	for {
+		full := false
+		if data.IsConnected() {
+			var err error
+			if checkpoint == nil {
+				checkpoint, err = loadCheckpoint(ctx, s.name)
+			}
+			if err == nil {
+				full, err = r.relayBatch(ctx, s, checkpoint)
+			}
+			if err != nil && ctx.Err() == nil {
+				log.Error().Err(err).Msg("failed to relay events")
+			}
+		}
+		if full {
+			continue
+		}
+
+		if !sleep(ctx, r.poll) {
+			return nil
+		}
+	}
+}
+
+// relayBatch delivers the next batch of events after checkpoint, and reports
+// whether the batch was full so more events may be waiting.
+func (r *Relay) relayBatch(ctx context.Context, s *subscriber, checkpoint *sqlc.OutboxCheckpoints) (bool, error) {
+	rows, err := data.DB.ListOutboxEvents(ctx, sqlc.ListOutboxEventsParams{
+		AfterTxid: checkpoint.LastTxid,
+		AfterID:   checkpoint.LastID,
+		MaxEvents: r.batchSize,
+	})
+	if err != nil || len(rows) == 0 {
+		return false, err
+	}
+
+	for i := range rows {
+		if s.types == nil || s.types[Type(rows[i].Type)] {
+			if err := r.deliver(ctx, s, fromRow(&rows[i])); err != nil {
+				return false, err
+			}
+		}
+		checkpoint.LastTxid, checkpoint.LastID = rows[i].Txid, rows[i].ID
+	}
+
+	err = data.DB.SaveOutboxCheckpoint(ctx, sqlc.SaveOutboxCheckpointParams{
+		Subscriber: s.name,
+		LastTxid:   checkpoint.LastTxid,
+		LastID:     checkpoint.LastID,
+	})
+	return len(rows) == int(r.batchSize), err
+}
+
+// deliver calls the subscriber until it handles ev, backing off between
+// attempts. It only fails if ctx is done.
+func (r *Relay) deliver(ctx context.Context, s *subscriber, ev *Event) error {
+	for attempt := 0; ; attempt++ {
+		err := s.fn(ctx, ev)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		delay := retryDelay(attempt)
+		r.log.Warn().Err(err).Str("subscriber", s.name).Int64("eventID", ev.ID).Str("type", string(ev.Type)).
+			Int("attempt", attempt+1).Dur("retryIn", delay).Msg("event handler failed, retrying")
+		if !sleep(ctx, delay) {
+			return ctx.Err()
+		}
+	}
+}
+
+func loadCheckpoint(ctx context.Context, name string) (*sqlc.OutboxCheckpoints, error) {
+	checkpoint, err := data.DB.GetOutboxCheckpoint(ctx, name)
+	if errors.Is(err, data.ErrNoRows) {
+		return &sqlc.OutboxCheckpoints{Subscriber: name}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+// runPruner deletes the events older than the retention period every hour.
+func (r *Relay) runPruner(ctx context.Context) error {
+	for {
+		if data.IsConnected() {
+			n, err := data.DB.PruneOutbox(ctx, time.Now().Add(-r.retention))
+			if err != nil && ctx.Err() == nil {
+				r.log.Error().Err(err).Msg("failed to prune the outbox")
+			} else if n > 0 {
+				r.log.Info().Int64("events", n).Msg("pruned the outbox")
+			}
+		}
+		if !sleep(ctx, time.Hour) {
+			return nil
+		}
+	}
+}
+
+// retryDelay doubles from one second up to a minute, with up to 25% jitter.
+func retryDelay(attempt int) time.Duration {
+	delay := time.Minute
+	if attempt < 6 {
+		delay = time.Second << uint(attempt)
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay)/4+1))
+}
+
+// sleep waits for d, and returns false if ctx is done first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
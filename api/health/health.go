@@ -0,0 +1,151 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CheckFunc reports whether a dependency is healthy, returning an error
+// describing the problem if it isn't.
+type CheckFunc func(ctx context.Context) error
+
+// Health tracks the readiness checks of the service, and serves the liveness
+// and readiness endpoints used by load balancers and orchestrators.
+type Health struct {
+	// Timeout bounds how long a single check may take.
+	Timeout time.Duration
+
+	mu     sync.RWMutex
+	checks map[string]CheckFunc
+
+	shuttingDown int32
+}
+
+type Status string
+
+const (
+	StatusOK   Status = "ok"
+	StatusFail Status = "fail"
+)
+
+type Result struct {
+	Status   Status `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+type Report struct {
+	Status Status            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+func New() *Health {
+	return &Health{
+		Timeout: 3 * time.Second,
+		checks:  map[string]CheckFunc{},
+	}
+}
+
+// Add registers a readiness check under name, replacing any previous check
+// with the same name.
+func (h *Health) Add(name string, check CheckFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks[name] = check
+}
+
+// SetShuttingDown makes readiness fail from now on, so load balancers stop
+// routing new traffic while in-flight requests drain.
+func (h *Health) SetShuttingDown() {
+	atomic.StoreInt32(&h.shuttingDown, 1)
+}
+
+func (h *Health) IsShuttingDown() bool {
+	return atomic.LoadInt32(&h.shuttingDown) == 1
+}
+
+// Ready runs all checks concurrently and reports their results.
+func (h *Health) Ready(ctx context.Context) Report {
+	h.mu.RLock()
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	checks := make([]CheckFunc, len(names))
+	for i, name := range names {
+		checks[i] = h.checks[name]
+	}
+	h.mu.RUnlock()
+
+	results := make([]Result, len(names))
+	var wg sync.WaitGroup
+	for i := range checks {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = h.run(ctx, checks[i])
+		}(i)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: map[string]Result{}}
+	if h.IsShuttingDown() {
+		report.Status = StatusFail
+		report.Checks["shutdown"] = Result{Status: StatusFail, Error: "service is shutting down"}
+	}
+	for i, name := range names {
+		report.Checks[name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+func (h *Health) run(ctx context.Context, check CheckFunc) Result {
+	ctx, cancel := context.WithTimeout(ctx, h.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	res := Result{Status: StatusOK, Duration: time.Since(start).String()}
+	if err != nil {
+		res.Status = StatusFail
+		res.Error = err.Error()
+	}
+	return res
+}
+
+// LiveHandler responds OK as long as the process is able to serve requests.
+func (h *Health) LiveHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respond(w, http.StatusOK, Report{Status: StatusOK, Checks: map[string]Result{}})
+	})
+}
+
+// ReadyHandler responds OK only if every readiness check passes and the
+// service isn't shutting down, and 503 otherwise.
+func (h *Health) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := h.Ready(r.Context())
+		status := http.StatusOK
+		if report.Status != StatusOK {
+			status = http.StatusServiceUnavailable
+		}
+		respond(w, status, report)
+	})
+}
+
+func respond(w http.ResponseWriter, status int, report Report) {
+	body, _ := json.Marshal(report)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.WriteHeader(status)
+	w.Write(body)
+}
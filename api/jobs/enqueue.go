@@ -0,0 +1,91 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+)
+
+var (
+	// ErrDuplicate is returned when enqueueing a job whose unique key is
+	// already held by a pending or running job.
+	ErrDuplicate = errors.New("jobs: duplicate job")
+
+	ErrUnknownKind = errors.New("jobs: no handler registered for job kind")
+)
+
+// EnqueueOptions are the optional settings of a single job.
+type EnqueueOptions struct {
+	// RunAt delays the job until the given time.
+	RunAt time.Time
+
+	// Delay delays the job by the given duration, when RunAt isn't set.
+	Delay time.Duration
+
+	// UniqueKey dedupes the job: while a job with the same key is pending
+	// or running, enqueueing returns ErrDuplicate.
+	UniqueKey string
+
+	// MaxAttempts overrides the max attempts of the job handler.
+	MaxAttempts int
+}
+
+// Enqueue adds a job of kind with args as its payload. opts may be nil.
+func (q *Queue) Enqueue(ctx context.Context, kind string, args interface{}, opts *EnqueueOptions) (*sqlc.Jobs, error) {
+	if data.DB == nil {
+		return nil, data.ErrNotConnected
+	}
+	return q.EnqueueTx(ctx, data.DB, kind, args, opts)
+}
+
+// EnqueueTx adds a job using tx, usually the queries of a data.WithTx
+// transaction, so the job is only enqueued if the transaction commits.
+func (q *Queue) EnqueueTx(ctx context.Context, tx *sqlc.Queries, kind string, args interface{}, opts *EnqueueOptions) (*sqlc.Jobs, error) {
+	h, ok := q.handlers[kind]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKind, kind)
+	}
+	if opts == nil {
+		opts = &EnqueueOptions{}
+	}
+
+	payload := []byte("{}")
+	if args != nil {
+		var err error
+		payload, err = json.Marshal(args)
+		if err != nil {
+			return nil, fmt.Errorf("jobs: failed to encode %q payload: %w", kind, err)
+		}
+	}
+
+	runAt := opts.RunAt
+	if runAt.IsZero() {
+		runAt = time.Now().Add(opts.Delay)
+	}
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = h.maxAttempts
+	}
+
+	job, err := tx.EnqueueJob(ctx, sqlc.EnqueueJobParams{
+		Kind:        kind,
+		Payload:     pgtype.JSONB{Bytes: payload, Status: pgtype.Present},
+		UniqueKey:   sql.NullString{String: opts.UniqueKey, Valid: opts.UniqueKey != ""},
+		MaxAttempts: int32(maxAttempts),
+		RunAt:       runAt,
+	})
+	if errors.Is(err, data.ErrNoRows) {
+		return nil, ErrDuplicate
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
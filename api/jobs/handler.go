@@ -0,0 +1,96 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultMaxAttempts is the number of times a job is run before it's
+	// dead-lettered, unless set by its handler or when enqueued.
+	DefaultMaxAttempts = 10
+
+	// DefaultTimeout bounds a single run of a job, unless set by its handler.
+	DefaultTimeout = 5 * time.Minute
+)
+
+// HandlerOptions configures how the jobs of a kind are run. The zero value
+// uses DefaultMaxAttempts and DefaultTimeout.
+type HandlerOptions struct {
+	MaxAttempts int
+	Timeout     time.Duration
+}
+
+type handler struct {
+	kind        string
+	maxAttempts int
+	timeout     time.Duration
+	run         func(ctx context.Context, payload []byte) error
+}
+
+// Register sets fn as the handler of the jobs of kind, which receive their
+// payload decoded into T. Handlers must be registered before the queue runs,
+// and a kind can only be registered once.
+//
+// A job which returns an error is retried with exponential backoff, until it
+// has run MaxAttempts times and is dead-lettered. Wrap the error with
+// Permanent to dead-letter the job right away.
+func Register[T any](q *Queue, kind string, opts HandlerOptions, fn func(ctx context.Context, args T) error) {
+	if _, ok := q.handlers[kind]; ok {
+		panic(fmt.Sprintf("jobs: handler for %q registered twice", kind))
+	}
+
+	h := &handler{
+		kind:        kind,
+		maxAttempts: opts.MaxAttempts,
+		timeout:     opts.Timeout,
+		run: func(ctx context.Context, payload []byte) error {
+			var args T
+			if err := json.Unmarshal(payload, &args); err != nil {
+				return Permanent(fmt.Errorf("invalid payload: %w", err))
+			}
+			return fn(ctx, args)
+		},
+	}
+	if h.maxAttempts <= 0 {
+		h.maxAttempts = DefaultMaxAttempts
+	}
+	if h.timeout <= 0 {
+		h.timeout = DefaultTimeout
+	}
+	if h.timeout >= q.lockTimeout {
+		q.log.Warn().Str("kind", kind).Dur("timeout", h.timeout).Dur("lockTimeout", q.lockTimeout).
+			Msg("job timeout is longer than the lock timeout, slow jobs may run twice")
+	}
+
+	q.handlers[kind] = h
+}
+
+// Permanent marks err as a failure which won't go away by retrying, so the
+// job is dead-lettered instead of being retried.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+func isPermanent(err error) bool {
+	var perr *permanentError
+	return errors.As(err, &perr)
+}
@@ -0,0 +1,330 @@
+// Package jobs is a durable background job queue backed by the jobs table.
+//
+// Workers claim due jobs with FOR UPDATE SKIP LOCKED, so any number of
+// api-server replicas can process the same queue without running a job
+// twice.
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/nfteseum/nfteseum-learning-project/api/config"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/nfteseum/nfteseum-learning-project/api/metrics"
+	"github.com/nfteseum/nfteseum-learning-project/api/tracing"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	// RetryBaseDelay is the delay before the first retry of a failed job,
+	// doubled on every attempt.
+	RetryBaseDelay = 15 * time.Second
+
+	// RetryMaxDelay caps the delay between retries of a failed job.
+	RetryMaxDelay = 6 * time.Hour
+)
+
+type Queue struct {
+	log         zerolog.Logger
+	worker      string
+	workers     int
+	poll        time.Duration
+	lockTimeout time.Duration
+
+	handlers  map[string]*handler
+	schedules []*schedule
+
+	// inflight jobs, and freed is signalled whenever one of them is done.
+	inflight int32
+	freed    chan struct{}
+	wg       sync.WaitGroup
+
+	// jobsCtx is the context of running jobs. It's only cancelled when
+	// Stop runs out of time to drain them.
+	jobsCtx    context.Context
+	cancelJobs context.CancelFunc
+
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+	running  int32
+}
+
+func NewQueue(cfg *config.Config, log zerolog.Logger) (*Queue, error) {
+	q := &Queue{
+		log:      log.With().Str("ps", "jobs").Logger(),
+		workers:  cfg.Jobs.Workers,
+		handlers: map[string]*handler{},
+		freed:    make(chan struct{}, 1),
+		stopCh:   make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if q.workers == 0 {
+		q.workers = 4
+	}
+
+	var err error
+	q.poll, err = parseDuration(cfg.Jobs.PollInterval, time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("jobs: config invalid jobs.poll_interval value: %w", err)
+	}
+	q.lockTimeout, err = parseDuration(cfg.Jobs.LockTimeout, 30*time.Minute)
+	if err != nil {
+		return nil, fmt.Errorf("jobs: config invalid jobs.lock_timeout value: %w", err)
+	}
+
+	hostname, _ := os.Hostname()
+	q.worker = fmt.Sprintf("%s:%d", hostname, os.Getpid())
+
+	q.jobsCtx, q.cancelJobs = context.WithCancel(context.Background())
+	return q, nil
+}
+
+func parseDuration(s string, defaultValue time.Duration) (time.Duration, error) {
+	if s == "" {
+		return defaultValue, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// Run processes jobs until ctx is done or Stop is called. It then waits for
+// the running jobs to finish before returning. Scheduled jobs are enqueued
+// by RunScheduler.
+func (q *Queue) Run(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&q.running, 0, 1) {
+		return fmt.Errorf("jobs: already running")
+	}
+	defer close(q.done)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		q.runRescuer(ctx)
+	}()
+
+	if q.workers > 0 && len(q.handlers) > 0 {
+		q.runWorkers(ctx)
+	}
+
+	wg.Wait()
+	q.wg.Wait()
+	return nil
+}
+
+// Stop stops claiming new jobs and waits for the running ones to finish.
+// Jobs still running once timeoutCtx is done are cancelled, and retried
+// later by whichever worker picks them up.
+func (q *Queue) Stop(timeoutCtx context.Context) {
+	if atomic.LoadInt32(&q.running) == 0 {
+		return
+	}
+	q.stopOnce.Do(func() {
+		q.log.Info().Str("op", "stop").Int32("inflight", atomic.LoadInt32(&q.inflight)).Msg("-> jobs: stopping..")
+		close(q.stopCh)
+	})
+
+	select {
+	case <-q.done:
+	case <-timeoutCtx.Done():
+		q.log.Warn().Str("op", "stop").Msg("-> jobs: drain timed out, cancelling running jobs")
+		q.cancelJobs()
+		<-q.done
+	}
+	q.log.Info().Str("op", "stop").Msg("-> jobs: stopped.")
+}
+
+func (q *Queue) runWorkers(ctx context.Context) {
+	kinds := make([]string, 0, len(q.handlers))
+	for kind := range q.handlers {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	for {
+		free := q.workers - int(atomic.LoadInt32(&q.inflight))
+
+		claimed := 0
+		if free > 0 && data.IsConnected() {
+			jobs, err := data.DB.DequeueJobs(ctx, sqlc.DequeueJobsParams{
+				Worker:  sql.NullString{String: q.worker, Valid: true},
+				Kinds:   kinds,
+				MaxJobs: int32(free),
+			})
+			if err != nil && ctx.Err() == nil {
+				q.log.Error().Err(err).Msg("failed to dequeue jobs")
+			}
+			for i := range jobs {
+				q.start(&jobs[i])
+			}
+			claimed = len(jobs)
+		}
+
+		// With every worker busy, or a full batch claimed, more jobs are
+		// likely due so look again as soon as a worker is free.
+		var freed chan struct{}
+		if free <= 0 || claimed == free {
+			freed = q.freed
+		}
+
+		timer := time.NewTimer(q.poll)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-q.stopCh:
+			timer.Stop()
+			return
+		case <-freed:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+func (q *Queue) start(job *sqlc.Jobs) {
+	atomic.AddInt32(&q.inflight, 1)
+	q.wg.Add(1)
+	go func() {
+		defer func() {
+			atomic.AddInt32(&q.inflight, -1)
+			q.wg.Done()
+			select {
+			case q.freed <- struct{}{}:
+			default:
+			}
+		}()
+		q.process(job)
+	}()
+}
+
+func (q *Queue) process(job *sqlc.Jobs) {
+	h := q.handlers[job.Kind]
+	log := q.log.With().Int64("jobID", job.ID).Str("kind", job.Kind).Int32("attempt", job.Attempts).Logger()
+
+	ctx, cancel := context.WithTimeout(q.jobsCtx, h.timeout)
+	defer cancel()
+	ctx, span := tracing.Tracer().Start(ctx, "job "+job.Kind,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.Int64("job.id", job.ID),
+			attribute.String("job.kind", job.Kind),
+			attribute.Int("job.attempt", int(job.Attempts)),
+		),
+	)
+	defer span.End()
+
+	start := time.Now()
+	err := runHandler(ctx, h, job.Payload.Bytes)
+	metrics.JobDuration.WithLabelValues(job.Kind).Observe(time.Since(start).Seconds())
+	tracing.RecordError(span, err)
+
+	// The job context may be cancelled by now, record the outcome regardless.
+	ctx = trace.ContextWithSpan(context.Background(), span)
+
+	var result string
+	switch {
+	case err == nil:
+		result = "ok"
+		err = data.DB.CompleteJob(ctx, job.ID)
+
+	case isPermanent(err) || job.Attempts >= job.MaxAttempts:
+		result = "dead"
+		log.Error().Err(err).Msg("job failed, moved to dead letter")
+		err = data.DB.KillJob(ctx, sqlc.KillJobParams{
+			ID:        job.ID,
+			LastError: sql.NullString{String: err.Error(), Valid: true},
+		})
+
+	default:
+		result = "retry"
+		delay := retryDelay(int(job.Attempts))
+		if q.jobsCtx.Err() != nil {
+			delay = 0
+		}
+		log.Warn().Err(err).Dur("retryIn", delay).Msg("job failed, retrying")
+		err = data.DB.RetryJob(ctx, sqlc.RetryJobParams{
+			ID:        job.ID,
+			RunAt:     time.Now().Add(delay),
+			LastError: sql.NullString{String: err.Error(), Valid: true},
+		})
+	}
+	metrics.JobsProcessed.WithLabelValues(job.Kind, result).Inc()
+
+	if err != nil {
+		// The job stays locked, and is run again once its lock times out.
+		log.Error().Err(err).Str("result", result).Msg("failed to record job result")
+	}
+}
+
+func runHandler(ctx context.Context, h *handler, payload []byte) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return h.run(ctx, payload)
+}
+
+// runRescuer periodically releases the jobs locked by workers which died,
+// so they're run again, or moved to the dead letter once out of attempts.
+func (q *Queue) runRescuer(ctx context.Context) {
+	interval := q.lockTimeout / 4
+	if interval > time.Minute {
+		interval = time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.stopCh:
+			return
+		case <-ticker.C:
+		}
+		if !data.IsConnected() {
+			continue
+		}
+
+		// The cutoff is computed by the database, so the clock of the
+		// replica doesn't matter.
+		n, err := data.DB.RescueJobs(ctx, pgtype.Interval{Microseconds: q.lockTimeout.Microseconds(), Status: pgtype.Present})
+		if err != nil {
+			if ctx.Err() == nil {
+				q.log.Error().Err(err).Msg("failed to rescue jobs")
+			}
+			continue
+		}
+		if n > 0 {
+			q.log.Warn().Int64("jobs", n).Msg("rescued jobs from dead workers")
+		}
+	}
+}
+
+// retryDelay returns the delay before retrying a job which failed the given
+// number of attempts, doubling RetryBaseDelay every attempt with up to 25%
+// jitter.
+func retryDelay(attempts int) time.Duration {
+	delay := RetryBaseDelay
+	for i := 1; i < attempts && delay < RetryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > RetryMaxDelay {
+		delay = RetryMaxDelay
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay)/4+1))
+}
@@ -0,0 +1,89 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+type schedule struct {
+	spec  string
+	kind  string
+	args  interface{}
+	sched cron.Schedule
+	next  time.Time
+}
+
+// Schedule enqueues a job of kind with args at the times given by spec, a
+// standard five field cron expression or a descriptor like "@hourly" or
+// "@every 10m". Schedules must be added before the queue runs.
+//
+// The scheduled jobs are enqueued by RunScheduler, which should only run on
+// one replica. The job of a given time is also keyed by its schedule, so it's
+// only enqueued once while it's pending.
+func (q *Queue) Schedule(spec, kind string, args interface{}) error {
+	if _, ok := q.handlers[kind]; !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownKind, kind)
+	}
+	sched, err := cron.ParseStandard(spec)
+	if err != nil {
+		return fmt.Errorf("jobs: invalid schedule %q for %q: %w", spec, kind, err)
+	}
+	q.schedules = append(q.schedules, &schedule{spec: spec, kind: kind, args: args, sched: sched})
+	return nil
+}
+
+// RunScheduler enqueues the scheduled jobs as they come due, until ctx is
+// done or the queue is stopped.
+func (q *Queue) RunScheduler(ctx context.Context) error {
+	if len(q.schedules) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	for _, s := range q.schedules {
+		s.next = s.sched.Next(now)
+	}
+
+	for {
+		next := q.schedules[0].next
+		for _, s := range q.schedules[1:] {
+			if s.next.Before(next) {
+				next = s.next
+			}
+		}
+
+		timer := time.NewTimer(time.Until(next))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-q.stopCh:
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		now := time.Now()
+		for _, s := range q.schedules {
+			if s.next.After(now) {
+				continue
+			}
+			q.enqueueScheduled(ctx, s)
+			s.next = s.sched.Next(now)
+		}
+	}
+}
+
+func (q *Queue) enqueueScheduled(ctx context.Context, s *schedule) {
+	_, err := q.Enqueue(ctx, s.kind, s.args, &EnqueueOptions{
+		RunAt:     s.next,
+		UniqueKey: fmt.Sprintf("cron:%s:%s:%d", s.kind, s.spec, s.next.Unix()),
+	})
+	if err != nil && !errors.Is(err, ErrDuplicate) {
+		q.log.Error().Err(err).Str("kind", s.kind).Str("schedule", s.spec).Msg("failed to enqueue scheduled job")
+	}
+}
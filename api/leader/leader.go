@@ -0,0 +1,217 @@
+// Package leader elects one api-server replica as leader with a Postgres
+// advisory lock, so singleton workers run on exactly one replica.
+//
+// The lock is session-scoped and held on a dedicated connection: if the
+// leader dies or loses its connection, Postgres releases the lock and another
+// replica takes over on its next heartbeat.
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/nfteseum/nfteseum-learning-project/api/config"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/metrics"
+	"github.com/rs/zerolog"
+)
+
+type Elector struct {
+	log       zerolog.Logger
+	name      string
+	key       int64
+	heartbeat time.Duration
+
+	running int32
+
+	mu      sync.Mutex
+	leading bool
+	changed chan struct{}
+}
+
+// New returns an elector campaigning for the leadership of the service, all
+// replicas with the same service name compete for the same lock.
+func New(cfg *config.Config, log zerolog.Logger) (*Elector, error) {
+	heartbeat := 5 * time.Second
+	if cfg.Leader.Heartbeat != "" {
+		var err error
+		heartbeat, err = time.ParseDuration(cfg.Leader.Heartbeat)
+		if err != nil {
+			return nil, fmt.Errorf("leader: config invalid leader.heartbeat value: %w", err)
+		}
+	}
+
+	return &Elector{
+		log:       log.With().Str("ps", "leader").Logger(),
+		name:      cfg.Service.Name,
+		key:       lockKey(cfg.Service.Name),
+		heartbeat: heartbeat,
+		changed:   make(chan struct{}),
+	}, nil
+}
+
+// lockKey maps name to a 64-bit advisory lock key.
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("leader:" + name))
+	return int64(h.Sum64())
+}
+
+// IsLeader reports whether this replica currently holds leadership.
+func (e *Elector) IsLeader() bool {
+	leading, _ := e.state()
+	return leading
+}
+
+func (e *Elector) state() (bool, <-chan struct{}) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.leading, e.changed
+}
+
+func (e *Elector) setLeading(leading bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.leading == leading {
+		return
+	}
+	e.leading = leading
+	close(e.changed)
+	e.changed = make(chan struct{})
+
+	if leading {
+		metrics.Leader.Set(1)
+		e.log.Info().Str("lock", e.name).Msg("acquired leadership")
+	} else {
+		metrics.Leader.Set(0)
+		e.log.Warn().Str("lock", e.name).Msg("lost leadership")
+	}
+}
+
+// Run campaigns for leadership until ctx is done, and releases it on return.
+func (e *Elector) Run(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&e.running, 0, 1) {
+		return fmt.Errorf("leader: already running")
+	}
+
+	var conn *pgx.Conn
+	defer func() {
+		e.setLeading(false)
+		if conn != nil {
+			// Closing the session releases the lock.
+			conn.Close(context.Background())
+		}
+	}()
+
+	ticker := time.NewTicker(e.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		if data.IsConnected() {
+			var err error
+			conn, err = e.campaign(ctx, conn)
+			if err != nil && ctx.Err() == nil {
+				e.log.Warn().Err(err).Msg("leader election failed")
+			}
+		} else {
+			// Postgres may drop the session, and its lock, while the
+			// database is unreachable, letting another replica lead. Step
+			// down until the database is back.
+			e.setLeading(false)
+			if conn != nil {
+				conn.Close(context.Background())
+				conn = nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// campaign checks the leader still holds its lock, or tries to take it. It
+// returns the connection to keep using, which is nil if it was lost.
+func (e *Elector) campaign(ctx context.Context, conn *pgx.Conn) (*pgx.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.heartbeat)
+	defer cancel()
+
+	if conn == nil {
+		var err error
+		conn, err = data.Conn(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if e.IsLeader() {
+		// The lock lives as long as the session, so a live session means
+		// leadership is still held. Otherwise step down right away, since
+		// the lock may already be taken by another replica.
+		err := conn.Ping(ctx)
+		if err != nil {
+			e.setLeading(false)
+			conn.Close(context.Background())
+			return nil, err
+		}
+		return conn, nil
+	}
+
+	var acquired bool
+	err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, e.key).Scan(&acquired)
+	if err != nil {
+		conn.Close(context.Background())
+		return nil, err
+	}
+	if acquired {
+		e.setLeading(true)
+	}
+	return conn, nil
+}
+
+// OnLeader runs fn while this replica is the leader, until ctx is done or fn
+// returns by itself. The context passed to fn is cancelled when leadership is
+// lost, and fn is started again once it's regained.
+func (e *Elector) OnLeader(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	for {
+		leading, changed := e.state()
+		if !leading {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-changed:
+				continue
+			}
+		}
+
+		e.log.Info().Str("worker", name).Msg("starting singleton worker")
+
+		fnCtx, cancel := context.WithCancel(ctx)
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- fn(fnCtx)
+		}()
+
+		select {
+		case err := <-errCh:
+			cancel()
+			return err
+
+		case <-changed:
+			e.log.Warn().Str("worker", name).Msg("stopping singleton worker, leadership lost")
+			cancel()
+			err := <-errCh
+			if err != nil && !errors.Is(err, context.Canceled) {
+				return err
+			}
+		}
+	}
+}
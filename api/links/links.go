@@ -0,0 +1,84 @@
+// Package links builds the links sent by email, ie. to verify an address or
+// to unsubscribe in one click. Links are signed with the server secret, so
+// they can't be forged, and may expire.
+package links
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/config"
+)
+
+var (
+	ErrInvalid = errors.New("links: invalid signature")
+	ErrExpired = errors.New("links: link expired")
+)
+
+type Signer struct {
+	base string
+	key  []byte
+}
+
+func New(cfg *config.Config) *Signer {
+	// Derive a key of its own, so link signatures can't be mistaken for
+	// anything else signed with the secret.
+	mac := hmac.New(sha256.New, []byte(cfg.Auth.JWTSecret))
+	mac.Write([]byte("links"))
+
+	return &Signer{
+		base: strings.TrimRight(cfg.Service.URL, "/"),
+		key:  mac.Sum(nil),
+	}
+}
+
+// URL returns the absolute url of path with the query values, signed and
+// expiring after ttl, or never if ttl is zero.
+func (s *Signer) URL(path string, values url.Values, ttl time.Duration) string {
+	q := url.Values{}
+	for k, v := range values {
+		q[k] = v
+	}
+	if ttl > 0 {
+		q.Set("exp", strconv.FormatInt(time.Now().Add(ttl).Unix(), 10))
+	}
+	q.Set("sig", s.sign(path, q))
+	return s.base + path + "?" + q.Encode()
+}
+
+// Verify checks the signature and expiry of the query values of a link to
+// path.
+func (s *Signer) Verify(path string, values url.Values) error {
+	sig := values.Get("sig")
+	if sig == "" || !hmac.Equal([]byte(sig), []byte(s.sign(path, values))) {
+		return ErrInvalid
+	}
+	if exp := values.Get("exp"); exp != "" {
+		unix, err := strconv.ParseInt(exp, 10, 64)
+		if err != nil {
+			return ErrInvalid
+		}
+		if time.Now().Unix() > unix {
+			return ErrExpired
+		}
+	}
+	return nil
+}
+
+func (s *Signer) sign(path string, values url.Values) string {
+	q := url.Values{}
+	for k, v := range values {
+		if k != "sig" {
+			q[k] = v
+		}
+	}
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(path + "?" + q.Encode()))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,50 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Log doesn't send the messages, but writes them to dir as .eml files to be
+// opened with a mail client, or to the log when dir is empty. It's meant for
+// development.
+type Log struct {
+	from string
+	dir  string
+	log  zerolog.Logger
+}
+
+func NewLog(from, dir string, log zerolog.Logger) *Log {
+	return &Log{
+		from: from,
+		dir:  dir,
+		log:  log.With().Str("ps", "mail").Logger(),
+	}
+}
+
+func (l *Log) Send(ctx context.Context, msg *Message) error {
+	body, err := encode(l.from, msg)
+	if err != nil {
+		return err
+	}
+
+	if l.dir == "" {
+		l.log.Info().Str("to", msg.To).Str("subject", msg.Subject).Msgf("mail not sent:\n%s", msg.Text)
+		return nil
+	}
+
+	if err := os.MkdirAll(l.dir, 0o755); err != nil {
+		return err
+	}
+	name := filepath.Join(l.dir, fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405.000000"), msg.To))
+	if err := os.WriteFile(name, body, 0o644); err != nil {
+		return err
+	}
+	l.log.Info().Str("to", msg.To).Str("subject", msg.Subject).Str("file", name).Msg("mail written")
+	return nil
+}
@@ -0,0 +1,155 @@
+// Package mail sends emails through a pluggable transport: SMTP in
+// production, or the log for development.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/config"
+	"github.com/rs/zerolog"
+)
+
+// Message is an email with a plain text body, and optionally an HTML
+// alternative.
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+
+	// Headers are extra headers, ie. List-Unsubscribe.
+	Headers map[string]string
+}
+
+type Sender interface {
+	// Send delivers msg, from the configured sender address.
+	Send(ctx context.Context, msg *Message) error
+}
+
+// New returns the sender set by the [mail] config: "log", the default, or
+// "smtp".
+func New(cfg *config.Config, log zerolog.Logger) (Sender, error) {
+	from := cfg.Mail.From
+	if from == "" {
+		from = cfg.Service.Name + " <noreply@localhost>"
+	}
+	if _, err := mail.ParseAddress(from); err != nil {
+		return nil, fmt.Errorf("mail: config invalid mail.from value: %w", err)
+	}
+
+	switch cfg.Mail.Driver {
+	case "", "log":
+		return NewLog(from, cfg.Mail.Dir, log), nil
+	case "smtp":
+		return NewSMTP(from, cfg.Mail)
+	default:
+		return nil, fmt.Errorf("mail: config invalid mail.driver value %q", cfg.Mail.Driver)
+	}
+}
+
+// ValidAddress reports whether addr is a bare email address, ie.
+// "user@example.com".
+func ValidAddress(addr string) bool {
+	a, err := mail.ParseAddress(addr)
+	return err == nil && a.Address == addr && a.Name == ""
+}
+
+// encode returns msg as a MIME message from the from address.
+func encode(from string, msg *Message) ([]byte, error) {
+	if !ValidAddress(msg.To) {
+		return nil, fmt.Errorf("mail: invalid recipient address %q", msg.To)
+	}
+	for k, v := range msg.Headers {
+		if strings.ContainsAny(k+v, "\r\n") {
+			return nil, fmt.Errorf("mail: invalid header %q", k)
+		}
+	}
+
+	var buf bytes.Buffer
+	headers := map[string]string{
+		"From":         from,
+		"To":           msg.To,
+		"Subject":      mime.QEncoding.Encode("utf-8", msg.Subject),
+		"Date":         time.Now().Format(time.RFC1123Z),
+		"Message-ID":   messageID(from),
+		"MIME-Version": "1.0",
+	}
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+
+	var body bytes.Buffer
+	if msg.HTML == "" {
+		headers["Content-Type"] = "text/plain; charset=utf-8"
+		headers["Content-Transfer-Encoding"] = "quoted-printable"
+		if err := writeQP(&body, msg.Text); err != nil {
+			return nil, err
+		}
+	} else {
+		mw := multipart.NewWriter(&body)
+		headers["Content-Type"] = "multipart/alternative; boundary=" + mw.Boundary()
+		for _, part := range []struct{ typ, content string }{
+			{"text/plain; charset=utf-8", msg.Text},
+			{"text/html; charset=utf-8", msg.HTML},
+		} {
+			w, err := mw.CreatePart(textproto.MIMEHeader{
+				"Content-Type":              {part.typ},
+				"Content-Transfer-Encoding": {"quoted-printable"},
+			})
+			if err != nil {
+				return nil, err
+			}
+			if err := writeQP(w, part.content); err != nil {
+				return nil, err
+			}
+		}
+		if err := mw.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&buf, "%s: %s\r\n", k, headers[k])
+	}
+	buf.WriteString("\r\n")
+	buf.Write(body.Bytes())
+	return buf.Bytes(), nil
+}
+
+func writeQP(w io.Writer, s string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(s)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+	if a, err := mail.ParseAddress(from); err == nil {
+		if i := strings.LastIndexByte(a.Address, '@'); i != -1 {
+			domain = a.Address[i+1:]
+		}
+	}
+	b := make([]byte, 16)
+	rand.Read(b)
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
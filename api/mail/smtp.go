@@ -0,0 +1,105 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/config"
+)
+
+// SMTP sends the messages to an SMTP server, upgrading the connection with
+// STARTTLS unless the server is configured as insecure.
+type SMTP struct {
+	from     string
+	sender   string
+	addr     string
+	host     string
+	auth     smtp.Auth
+	insecure bool
+}
+
+func NewSMTP(from string, cfg config.MailConfig) (*SMTP, error) {
+	if cfg.SMTPHost == "" {
+		return nil, fmt.Errorf("mail: config invalid mail.smtp_host value: required by the smtp driver")
+	}
+	port := cfg.SMTPPort
+	if port == 0 {
+		port = 587
+	}
+	a, _ := mail.ParseAddress(from)
+
+	s := &SMTP{
+		from:     from,
+		sender:   a.Address,
+		addr:     net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(port)),
+		host:     cfg.SMTPHost,
+		insecure: cfg.SMTPInsecure,
+	}
+	if cfg.SMTPUsername != "" {
+		s.auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+	return s, nil
+}
+
+func (s *SMTP) Send(ctx context.Context, msg *Message) error {
+	body, err := encode(s.from, msg)
+	if err != nil {
+		return err
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("mail: failed to connect to %s: %w", s.addr, err)
+	}
+	// net/smtp has no context support, bound the whole exchange instead.
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(time.Minute)
+	}
+	conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("mail: smtp handshake failed: %w", err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return fmt.Errorf("mail: starttls failed: %w", err)
+		}
+	} else if !s.insecure {
+		return fmt.Errorf("mail: %s does not support STARTTLS", s.addr)
+	}
+	if s.auth != nil {
+		if err := c.Auth(s.auth); err != nil {
+			return fmt.Errorf("mail: smtp auth failed: %w", err)
+		}
+	}
+
+	if err := c.Mail(s.sender); err != nil {
+		return fmt.Errorf("mail: smtp MAIL FROM failed: %w", err)
+	}
+	if err := c.Rcpt(msg.To); err != nil {
+		return fmt.Errorf("mail: smtp RCPT TO failed: %w", err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("mail: smtp DATA failed: %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("mail: failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("mail: message rejected: %w", err)
+	}
+	return c.Quit()
+}
@@ -0,0 +1,220 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/config"
+)
+
+// fakeSMTP is a local SMTP server recording the commands and messages it
+// receives. It doesn't support STARTTLS unless told to advertise it, in
+// which case it fails the upgrade.
+type fakeSMTP struct {
+	ln        net.Listener
+	starttls  bool
+	wg        sync.WaitGroup
+	mu        sync.Mutex
+	commands  []string
+	messages  [][]byte
+	connected int
+}
+
+func newFakeSMTP(t *testing.T, starttls bool) *fakeSMTP {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &fakeSMTP{ln: ln, starttls: starttls}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				s.serve(conn)
+			}()
+		}
+	}()
+	t.Cleanup(s.close)
+	return s
+}
+
+func (s *fakeSMTP) close() {
+	s.ln.Close()
+	s.wg.Wait()
+}
+
+func (s *fakeSMTP) config(insecure bool) config.MailConfig {
+	host, port, _ := net.SplitHostPort(s.ln.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return config.MailConfig{Driver: "smtp", SMTPHost: host, SMTPPort: p, SMTPInsecure: insecure}
+}
+
+func (s *fakeSMTP) serve(conn net.Conn) {
+	defer conn.Close()
+	s.mu.Lock()
+	s.connected++
+	s.mu.Unlock()
+
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 localhost ESMTP fake")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.commands = append(s.commands, line)
+		s.mu.Unlock()
+
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch verb {
+		case "EHLO":
+			if s.starttls {
+				tp.PrintfLine("250-localhost")
+				tp.PrintfLine("250 STARTTLS")
+			} else {
+				tp.PrintfLine("250 localhost")
+			}
+		case "HELO", "MAIL", "RCPT", "RSET", "NOOP":
+			tp.PrintfLine("250 OK")
+		case "STARTTLS":
+			tp.PrintfLine("454 TLS not available")
+		case "DATA":
+			tp.PrintfLine("354 go ahead")
+			msg, err := io.ReadAll(tp.DotReader())
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+			tp.PrintfLine("250 OK queued")
+		case "QUIT":
+			tp.PrintfLine("221 bye")
+			return
+		default:
+			tp.PrintfLine("502 unknown command")
+		}
+	}
+}
+
+func TestSMTPSend(t *testing.T) {
+	srv := newFakeSMTP(t, false)
+	s, err := NewSMTP("nfteseum <noreply@nfteseum.xyz>", srv.config(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.Send(context.Background(), &Message{
+		To:      "alice@example.com",
+		Subject: "Your weekly digest ✨",
+		Text:    "3 new likes",
+		HTML:    "<p>3 new likes</p>",
+		Headers: map[string]string{
+			"List-Unsubscribe":      "<https://api.nfteseum.xyz/unsubscribe?token=abc>",
+			"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.close()
+
+	var cmds []string
+	for _, c := range srv.commands {
+		if strings.HasPrefix(c, "MAIL") || strings.HasPrefix(c, "RCPT") || c == "DATA" {
+			cmds = append(cmds, c)
+		}
+	}
+	want := []string{"MAIL FROM:<noreply@nfteseum.xyz>", "RCPT TO:<alice@example.com>", "DATA"}
+	if strings.Join(cmds, "\n") != strings.Join(want, "\n") {
+		t.Errorf("commands = %q, want %q", cmds, want)
+	}
+	if len(srv.messages) != 1 {
+		t.Fatalf("got %d messages, want 1", len(srv.messages))
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(srv.messages[0]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != "Your weekly digest ✨" {
+		t.Errorf("Subject = %q (%v), want the decoded subject", msg.Header.Get("Subject"), err)
+	}
+	for k, v := range map[string]string{
+		"From":                  "nfteseum <noreply@nfteseum.xyz>",
+		"To":                    "alice@example.com",
+		"MIME-Version":          "1.0",
+		"List-Unsubscribe":      "<https://api.nfteseum.xyz/unsubscribe?token=abc>",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	} {
+		if got := msg.Header.Get(k); got != v {
+			t.Errorf("%s = %q, want %q", k, got, v)
+		}
+	}
+	if _, err := msg.Header.Date(); err != nil {
+		t.Errorf("Date: %v", err)
+	}
+	if !strings.HasSuffix(msg.Header.Get("Message-ID"), "@nfteseum.xyz>") {
+		t.Errorf("Message-ID = %q, want one of the sender domain", msg.Header.Get("Message-ID"))
+	}
+
+	typ, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || typ != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, want multipart/alternative", msg.Header.Get("Content-Type"))
+	}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	for _, want := range []string{"text/plain", "text/html"} {
+		part, err := mr.NextPart()
+		if err != nil {
+			t.Fatalf("%s part: %v", want, err)
+		}
+		if !strings.HasPrefix(part.Header.Get("Content-Type"), want) {
+			t.Errorf("part Content-Type = %q, want %s", part.Header.Get("Content-Type"), want)
+		}
+	}
+}
+
+func TestSMTPRequiresSTARTTLS(t *testing.T) {
+	for _, starttls := range []bool{false, true} {
+		srv := newFakeSMTP(t, starttls)
+		s, err := NewSMTP("noreply@nfteseum.xyz", srv.config(false))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = s.Send(context.Background(), &Message{To: "alice@example.com", Subject: "hi", Text: "hi"})
+		if err == nil {
+			t.Errorf("starttls %v: sent without TLS", starttls)
+		}
+		srv.close()
+
+		if srv.connected != 1 {
+			t.Errorf("starttls %v: connected %d times, want 1", starttls, srv.connected)
+		}
+		for _, c := range srv.commands {
+			if strings.HasPrefix(c, "MAIL") {
+				t.Errorf("starttls %v: got %q without TLS", starttls, c)
+			}
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/nfteseum/nfteseum-learning-project/api/proto"
+)
+
+// HTTP counts the requests served per route pattern and status, and tracks
+// the number of requests in flight.
+func HTTP(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		HTTPRequestsInFlight.Inc()
+		defer HTTPRequestsInFlight.Dec()
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		route := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		HTTPRequests.WithLabelValues(route, strconv.Itoa(status)).Inc()
+	})
+}
+
+// RateLimited is used as the httprate limit handler, counting rejected
+// requests before responding with 429 Too Many Requests.
+func RateLimited(w http.ResponseWriter, r *http.Request) {
+	HTTPRateLimited.Inc()
+	http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+}
+
+// RPC records the count and latency of webrpc requests by method and error
+// code. It must wrap the handler mounted at /rpc/*.
+func RPC(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		rec := &rpcRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		method := rpcMethod(r.URL.Path)
+		code := rec.errorCode()
+		RPCRequests.WithLabelValues(method, code).Inc()
+		RPCDuration.WithLabelValues(method, code).Observe(time.Since(start).Seconds())
+	})
+}
+
+// rpcMethods holds the known method names, so unroutable paths don't
+// create unbounded label values.
+var rpcMethods = func() map[string]bool {
+	m := map[string]bool{}
+	for _, method := range proto.WebRPCServices["API"] {
+		m[method] = true
+	}
+	return m
+}()
+
+func rpcMethod(path string) string {
+	method := strings.TrimPrefix(path, proto.APIPathPrefix)
+	if !rpcMethods[method] {
+		return "unknown"
+	}
+	return method
+}
+
+// rpcRecorder captures the status and, for errors, the start of the response
+// body to read the webrpc error code from.
+type rpcRecorder struct {
+	http.ResponseWriter
+	status  int
+	errBody []byte
+}
+
+func (w *rpcRecorder) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *rpcRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if w.status != http.StatusOK && len(w.errBody) < 4096 {
+		w.errBody = append(w.errBody, b...)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *rpcRecorder) errorCode() string {
+	if w.status == 0 || w.status == http.StatusOK {
+		return "ok"
+	}
+	var payload proto.ErrorPayload
+	if err := json.Unmarshal(w.errBody, &payload); err == nil && payload.Code != "" && proto.IsValidErrorCode(proto.ErrorCode(payload.Code)) {
+		return payload.Code
+	}
+	return strconv.Itoa(w.status)
+}
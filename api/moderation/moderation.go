@@ -0,0 +1,417 @@
+// Package moderation implements the reports of posts, comments and profiles
+// by users, and the actions admins take on them: hiding, removing and
+// restoring content, and suspending accounts.
+package moderation
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/nfteseum/nfteseum-learning-project/api/audit"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/nfteseum/nfteseum-learning-project/api/events"
+)
+
+// Types of targets.
+const (
+	TargetPost    = "post"
+	TargetComment = "comment"
+	TargetUser    = "user"
+)
+
+// Visibilities of posts and comments. Held comments wait for review after
+// being flagged by the spam filter.
+const (
+	Visible = "visible"
+	Hidden  = "hidden"
+	Removed = "removed"
+	Held    = "held"
+)
+
+// Moderation actions. Restore makes content visible again, or lifts the
+// suspension of a user, and Dismiss resolves the reports of a target without
+// changing it.
+const (
+	ActionHide    = "hide"
+	ActionRemove  = "remove"
+	ActionRestore = "restore"
+	ActionSuspend = "suspend"
+	ActionDismiss = "dismiss"
+)
+
+// Statuses of reports.
+const (
+	StatusOpen      = "open"
+	StatusActioned  = "actioned"
+	StatusDismissed = "dismissed"
+)
+
+// Reputation changes of the authors of moderated content and of suspended
+// users. Restoring content which was out of sight makes up for a false
+// positive.
+const (
+	reputationHide    = -5
+	reputationRemove  = -10
+	reputationSuspend = -25
+	reputationRestore = 5
+)
+
+// Reasons are the reasons a target may be reported for.
+var Reasons = []string{"spam", "harassment", "hate", "violence", "sexual", "impersonation", "other"}
+
+var (
+	// ErrAlreadyReported is returned when the reporter has an open report of
+	// the target.
+	ErrAlreadyReported = errors.New("moderation: target is already reported")
+
+	// ErrSelfReport is returned when users report their own profile.
+	ErrSelfReport = errors.New("moderation: cannot report yourself")
+
+	// ErrInvalidTarget is returned for malformed post and comment ids.
+	ErrInvalidTarget = errors.New("moderation: invalid target id")
+
+	// ErrInvalidAction is returned for actions which don't apply to the type
+	// of the target, ie. suspending a post.
+	ErrInvalidAction = errors.New("moderation: action does not apply to target")
+)
+
+// Target is a post or comment, by id, or a user, by lowercase address.
+type Target struct {
+	Type string
+	ID   string
+}
+
+// ValidReason reports whether reason is one of Reasons.
+func ValidReason(reason string) bool {
+	for _, r := range Reasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// Suspended reports whether user is suspended at now.
+func Suspended(user *sqlc.Users, now time.Time) bool {
+	return user.SuspendedAt.Valid && (!user.SuspendedUntil.Valid || user.SuspendedUntil.Time.After(now))
+}
+
+// Report files a report of target by reporter. Hidden and removed content
+// can't be reported, being out of sight already.
+func Report(ctx context.Context, reporter string, target Target, reason, details string) (*sqlc.Reports, error) {
+	switch target.Type {
+	case TargetPost:
+		id, err := targetID(target)
+		if err != nil {
+			return nil, err
+		}
+		post, err := data.DB.GetPost(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if post.Visibility != Visible {
+			return nil, data.ErrNoRows
+		}
+	case TargetComment:
+		id, err := targetID(target)
+		if err != nil {
+			return nil, err
+		}
+		comment, err := data.DB.GetComment(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if comment.Visibility != Visible {
+			return nil, data.ErrNoRows
+		}
+	case TargetUser:
+		if strings.EqualFold(target.ID, reporter) {
+			return nil, ErrSelfReport
+		}
+		if _, err := data.DB.GetUser(ctx, target.ID); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrInvalidTarget
+	}
+
+	report, err := data.DB.CreateReport(ctx, sqlc.CreateReportParams{
+		Reporter:   reporter,
+		TargetType: target.Type,
+		TargetID:   target.ID,
+		Reason:     reason,
+		Details:    sql.NullString{String: details, Valid: details != ""},
+	})
+	if errors.Is(err, data.ErrNoRows) {
+		return nil, ErrAlreadyReported
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Moderate takes action on target on behalf of moderator, and resolves its
+// open reports: as actioned when the target was hidden, removed or
+// suspended, and dismissed otherwise. The reporters of actioned reports are
+// notified. Suspensions last until the given time, or until lifted when
+// until is nil. The action is recorded in the audit log, with the state of
+// the target before and after it, and adjusts the reputation of the author.
+// Restoring a held comment publishes it. It returns the number of reports
+// resolved.
+func Moderate(ctx context.Context, moderator string, target Target, action string, until *time.Time) (int, error) {
+	status := StatusDismissed
+	if action == ActionHide || action == ActionRemove || action == ActionSuspend {
+		status = StatusActioned
+	}
+
+	resolved := 0
+	err := data.WithTx(ctx, pgx.TxOptions{}, func(q *sqlc.Queries) error {
+		payload := events.Moderation{
+			TargetType: target.Type,
+			TargetID:   target.ID,
+			Action:     action,
+		}
+		before, err := state(ctx, q, target)
+		if err != nil {
+			return err
+		}
+		if err := apply(ctx, q, target, action, until, &payload); err != nil {
+			return err
+		}
+		after, err := state(ctx, q, target)
+		if err != nil {
+			return err
+		}
+		err = audit.Record(ctx, q, moderator, audit.Entry{
+			Action:     audit.ActionModerate + "." + action,
+			TargetType: target.Type,
+			TargetID:   target.ID,
+			Before:     before,
+			After:      after,
+		})
+		if err != nil {
+			return err
+		}
+		if err := adjustReputation(ctx, q, before, action); err != nil {
+			return err
+		}
+
+		reporters, err := q.ResolveReports(ctx, sqlc.ResolveReportsParams{
+			Status:     status,
+			Action:     action,
+			ResolvedBy: moderator,
+			TargetType: target.Type,
+			TargetID:   target.ID,
+		})
+		if err != nil {
+			return err
+		}
+		resolved = len(reporters)
+		if status != StatusActioned || len(reporters) == 0 {
+			return nil
+		}
+
+		for _, reporter := range reporters {
+			payload.Reporters = append(payload.Reporters, strings.TrimSpace(reporter))
+		}
+		_, err = events.Publish(ctx, q, events.ContentModerated, moderator, payload)
+		return err
+	})
+	return resolved, err
+}
+
+// apply changes the visibility of a post or comment, or the suspension of a
+// user, as action requires. Targets which don't exist are reported with
+// data.ErrNoRows.
+func apply(ctx context.Context, q *sqlc.Queries, target Target, action string, until *time.Time, payload *events.Moderation) error {
+	switch target.Type {
+	case TargetPost, TargetComment:
+		id, err := targetID(target)
+		if err != nil {
+			return err
+		}
+		var visibility string
+		switch action {
+		case ActionHide:
+			visibility = Hidden
+		case ActionRemove:
+			visibility = Removed
+		case ActionRestore:
+			visibility = Visible
+		case ActionDismiss:
+		default:
+			return ErrInvalidAction
+		}
+
+		if target.Type == TargetPost {
+			payload.PostID = id
+			if visibility == "" {
+				_, err = q.GetPost(ctx, id)
+				return err
+			}
+			n, err := q.SetPostVisibility(ctx, sqlc.SetPostVisibilityParams{ID: id, Visibility: visibility})
+			if err == nil && n == 0 {
+				err = data.ErrNoRows
+			}
+			return err
+		}
+
+		comment, err := q.GetComment(ctx, id)
+		if err != nil {
+			return err
+		}
+		payload.PostID = comment.PostID
+		payload.CommentID = id
+		if visibility == "" {
+			return nil
+		}
+		_, err = q.SetCommentVisibility(ctx, sqlc.SetCommentVisibilityParams{ID: id, Visibility: visibility})
+		if err != nil {
+			return err
+		}
+		return settleHold(ctx, q, &comment, visibility)
+
+	case TargetUser:
+		if _, err := q.GetUser(ctx, target.ID); err != nil {
+			return err
+		}
+		switch action {
+		case ActionSuspend:
+			suspendedUntil := sql.NullTime{}
+			if until != nil {
+				suspendedUntil = sql.NullTime{Time: until.UTC(), Valid: true}
+			}
+			_, err := q.SuspendUser(ctx, sqlc.SuspendUserParams{Addr: target.ID, SuspendedUntil: suspendedUntil})
+			return err
+		case ActionRestore:
+			_, err := q.LiftSuspension(ctx, target.ID)
+			return err
+		case ActionDismiss:
+			return nil
+		}
+		return ErrInvalidAction
+	}
+	return ErrInvalidTarget
+}
+
+// settleHold publishes a comment held by the spam filter once it's
+// restored, as it would have been when created, and counts it on its post.
+// Removed comments are never published, and hidden ones stay pending.
+func settleHold(ctx context.Context, q *sqlc.Queries, comment *sqlc.Comments, visibility string) error {
+	if visibility == Hidden {
+		return nil
+	}
+	held, err := q.GetHeldComment(ctx, comment.ID)
+	if errors.Is(err, data.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := q.DeleteHeldComment(ctx, comment.ID); err != nil {
+		return err
+	}
+	if visibility != Visible {
+		return nil
+	}
+
+	err = q.AddPostComments(ctx, sqlc.AddPostCommentsParams{ID: comment.PostID, Delta: 1})
+	if err != nil {
+		return err
+	}
+	var payload events.Comment
+	if err := json.Unmarshal(held.Event.Bytes, &payload); err != nil {
+		return fmt.Errorf("moderation: invalid held event of comment %d: %w", comment.ID, err)
+	}
+	_, err = events.Publish(ctx, q, events.CommentCreated, strings.TrimRight(comment.Author, " "), payload)
+	return err
+}
+
+// adjustReputation lowers the reputation of the author of a target action
+// was taken on, or raises it when content out of sight is restored.
+func adjustReputation(ctx context.Context, q *sqlc.Queries, before *targetState, action string) error {
+	var delta int32
+	switch action {
+	case ActionHide:
+		delta = reputationHide
+	case ActionRemove:
+		delta = reputationRemove
+	case ActionSuspend:
+		delta = reputationSuspend
+	case ActionRestore:
+		if before.Visibility != "" && before.Visibility != Visible {
+			delta = reputationRestore
+		}
+	}
+	if delta == 0 {
+		return nil
+	}
+	return q.AdjustReputation(ctx, sqlc.AdjustReputationParams{Account: before.author, Delta: delta})
+}
+
+// targetState is the moderated state of a target, recorded in the audit log:
+// the visibility of a post or comment, or the suspension of a user.
+type targetState struct {
+	// author is the author of the post or comment, or the user.
+	author string
+
+	Visibility     string     `json:"visibility,omitempty"`
+	SuspendedAt    *time.Time `json:"suspendedAt,omitempty"`
+	SuspendedUntil *time.Time `json:"suspendedUntil,omitempty"`
+}
+
+// state returns the moderated state of target.
+func state(ctx context.Context, q *sqlc.Queries, target Target) (*targetState, error) {
+	switch target.Type {
+	case TargetPost, TargetComment:
+		id, err := targetID(target)
+		if err != nil {
+			return nil, err
+		}
+		if target.Type == TargetPost {
+			post, err := q.GetPost(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			return &targetState{author: strings.TrimRight(post.Author, " "), Visibility: post.Visibility}, nil
+		}
+		comment, err := q.GetComment(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return &targetState{author: strings.TrimRight(comment.Author, " "), Visibility: comment.Visibility}, nil
+
+	case TargetUser:
+		user, err := q.GetUser(ctx, target.ID)
+		if err != nil {
+			return nil, err
+		}
+		s := &targetState{author: strings.TrimRight(user.Addr, " ")}
+		if user.SuspendedAt.Valid {
+			s.SuspendedAt = &user.SuspendedAt.Time
+		}
+		if user.SuspendedUntil.Valid {
+			s.SuspendedUntil = &user.SuspendedUntil.Time
+		}
+		return s, nil
+	}
+	return nil, ErrInvalidTarget
+}
+
+// targetID returns the id of a post or comment target.
+func targetID(target Target) (int32, error) {
+	id, err := strconv.ParseInt(target.ID, 10, 32)
+	if err != nil || id <= 0 {
+		return 0, ErrInvalidTarget
+	}
+	return int32(id), nil
+}
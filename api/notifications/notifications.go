@@ -0,0 +1,356 @@
+// Package notifications turns the domain events directed at a user into
+// in-app notifications. Repeated events on the same subject are aggregated
+// while unread, ie. "12 people liked your post".
+package notifications
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/config"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/nfteseum/nfteseum-learning-project/api/events"
+	"github.com/nfteseum/nfteseum-learning-project/api/jobs"
+	"github.com/nfteseum/nfteseum-learning-project/api/push"
+	"github.com/rs/zerolog"
+)
+
+// Kinds of notifications.
+const (
+	Like    = "like"
+	Comment = "comment"
+	Reply   = "reply"
+	Follow  = "follow"
+	Mention = "mention"
+	Report  = "report"
+)
+
+// Types are the event types notified, to subscribe the notifier with.
+var Types = []events.Type{
+	events.PostLiked,
+	events.CommentCreated,
+	events.UserFollowed,
+	events.ContentModerated,
+}
+
+// Notifier writes the notifications of the events it's handed by the relay,
+// and pushes them to the browsers of the recipients when web push is
+// enabled.
+type Notifier struct {
+	log   zerolog.Logger
+	queue *jobs.Queue
+	push  bool
+	url   string
+}
+
+func NewNotifier(cfg *config.Config, log zerolog.Logger, queue *jobs.Queue) *Notifier {
+	return &Notifier{
+		log:   log.With().Str("ps", "notifications").Logger(),
+		queue: queue,
+		push:  push.Enabled(cfg),
+		url:   cfg.Service.URL,
+	}
+}
+
+// notification is a notification of one actor, before aggregation.
+type notification struct {
+	recipient string
+	kind      string
+	group     string
+	postID    int32
+	commentID int32
+
+	// anonymous leaves the actor out, ie. the admin acting on a report.
+	anonymous bool
+}
+
+// Handle is the events.Handler of the notifier. Notifications are aggregated
+// per actor, so redelivered events don't count twice. Recipients aren't
+// notified of the users they blocked or muted.
+func (n *Notifier) Handle(ctx context.Context, ev *events.Event) error {
+	notes, err := n.notifications(ev)
+	if err != nil {
+		// Retrying won't fix a malformed payload.
+		n.log.Error().Err(err).Int64("eventID", ev.ID).Msg("skipping event")
+		return nil
+	}
+
+	for _, note := range notes {
+		if strings.EqualFold(note.recipient, ev.Actor) {
+			continue
+		}
+		if !note.anonymous {
+			muted, err := data.DB.IsMuted(ctx, sqlc.IsMutedParams{Blocker: note.recipient, Blocked: ev.Actor})
+			if err != nil {
+				return err
+			}
+			if muted {
+				continue
+			}
+		}
+		prefs, err := GetPreferences(ctx, note.recipient)
+		if err != nil {
+			return err
+		}
+		if !prefs.Enabled(note.kind) {
+			continue
+		}
+
+		actor := ev.Actor
+		if note.anonymous {
+			actor = ""
+		}
+		rows, err := data.DB.UpsertNotification(ctx, sqlc.UpsertNotificationParams{
+			Recipient: note.recipient,
+			Kind:      note.kind,
+			GroupKey:  note.group,
+			PostID:    sql.NullInt32{Int32: note.postID, Valid: note.postID != 0},
+			CommentID: sql.NullInt32{Int32: note.commentID, Valid: note.commentID != 0},
+			Actor:     actor,
+		})
+		if err != nil {
+			return fmt.Errorf("notifications: failed to notify %s of event %d: %w", note.kind, ev.ID, err)
+		}
+		if rows > 0 && n.push {
+			if err := n.enqueuePush(ctx, ev, &note); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// enqueuePush enqueues the delivery of a notification to every browser
+// subscribed by its recipient. Deliveries are deduped per event, so
+// redelivered events aren't pushed twice while pending.
+func (n *Notifier) enqueuePush(ctx context.Context, ev *events.Event, note *notification) error {
+	subs, err := data.DB.ListPushSubscriptions(ctx, note.recipient)
+	if err != nil || len(subs) == 0 {
+		return err
+	}
+
+	msg := &push.Message{
+		Title: "nfteseum",
+		Body:  Summary(note.kind, []string{ev.Actor}, 1, note.postID),
+		URL:   n.url,
+		Tag:   note.group,
+	}
+	for _, sub := range subs {
+		_, err := n.queue.Enqueue(ctx, push.SendJob, push.SendArgs{SubscriptionID: sub.ID, Message: msg}, &jobs.EnqueueOptions{
+			UniqueKey: fmt.Sprintf("push:%d:%d", ev.ID, sub.ID),
+		})
+		if err != nil && !errors.Is(err, jobs.ErrDuplicate) {
+			return err
+		}
+	}
+	return nil
+}
+
+// notifications returns the notifications of ev, at most one per recipient.
+func (n *Notifier) notifications(ev *events.Event) ([]notification, error) {
+	switch ev.Type {
+	case events.PostLiked:
+		var p events.PostLike
+		if err := ev.Decode(&p); err != nil {
+			return nil, err
+		}
+		return []notification{{
+			recipient: p.PostAuthor,
+			kind:      Like,
+			group:     fmt.Sprintf("like:post:%d", p.PostID),
+			postID:    p.PostID,
+		}}, nil
+
+	case events.CommentCreated:
+		var p events.Comment
+		if err := ev.Decode(&p); err != nil {
+			return nil, err
+		}
+		var notes []notification
+		if p.ParentID != 0 {
+			notes = append(notes, notification{
+				recipient: p.ParentAuthor,
+				kind:      Reply,
+				group:     fmt.Sprintf("reply:comment:%d", p.ParentID),
+				postID:    p.PostID,
+				commentID: p.ParentID,
+			})
+		}
+		// The author of the post is only told once when replied to.
+		if p.ParentID == 0 || !strings.EqualFold(p.ParentAuthor, p.PostAuthor) {
+			notes = append(notes, notification{
+				recipient: p.PostAuthor,
+				kind:      Comment,
+				group:     fmt.Sprintf("comment:post:%d", p.PostID),
+				postID:    p.PostID,
+			})
+		}
+		for _, account := range p.Mentions {
+			if notified(notes, account) {
+				continue
+			}
+			notes = append(notes, notification{
+				recipient: account,
+				kind:      Mention,
+				group:     fmt.Sprintf("mention:comment:%d", p.CommentID),
+				postID:    p.PostID,
+				commentID: p.CommentID,
+			})
+		}
+		return notes, nil
+
+	case events.UserFollowed:
+		var p events.Follow
+		if err := ev.Decode(&p); err != nil {
+			return nil, err
+		}
+		return []notification{{
+			recipient: p.Followee,
+			kind:      Follow,
+			group:     "follow",
+		}}, nil
+
+	case events.ContentModerated:
+		var p events.Moderation
+		if err := ev.Decode(&p); err != nil {
+			return nil, err
+		}
+		notes := make([]notification, len(p.Reporters))
+		for i, reporter := range p.Reporters {
+			notes[i] = notification{
+				recipient: reporter,
+				kind:      Report,
+				group:     fmt.Sprintf("report:%s:%s", p.TargetType, p.TargetID),
+				postID:    p.PostID,
+				commentID: p.CommentID,
+				anonymous: true,
+			}
+		}
+		return notes, nil
+	}
+	return nil, nil
+}
+
+// notified reports whether notes hold a notification of recipient.
+func notified(notes []notification, recipient string) bool {
+	for _, note := range notes {
+		if strings.EqualFold(note.recipient, recipient) {
+			return true
+		}
+	}
+	return false
+}
+
+// Summary describes a notification of kind, ie. "0x1234…cdef and 11 others
+// liked your post #3", from its latest actors and its count of actors.
+func Summary(kind string, actors []string, actorCount int32, postID int32) string {
+	who := "someone"
+	if len(actors) > 0 {
+		who = shortAddr(actors[0])
+	}
+	switch {
+	case actorCount == 2:
+		who += " and 1 other"
+	case actorCount > 2:
+		who += fmt.Sprintf(" and %d others", actorCount-1)
+	}
+
+	switch kind {
+	case Like:
+		return fmt.Sprintf("%s liked your post #%d", who, postID)
+	case Comment:
+		return fmt.Sprintf("%s commented on your post #%d", who, postID)
+	case Reply:
+		return fmt.Sprintf("%s replied to your comment on post #%d", who, postID)
+	case Follow:
+		return fmt.Sprintf("%s followed you", who)
+	case Mention:
+		return fmt.Sprintf("%s mentioned you on post #%d", who, postID)
+	case Report:
+		return "Action was taken on content you reported"
+	}
+	return fmt.Sprintf("%s interacted with you", who)
+}
+
+// shortAddr abbreviates an address, ie. "0x1234…cdef".
+func shortAddr(addr string) string {
+	addr = strings.TrimSpace(addr)
+	if len(addr) != 42 {
+		return addr
+	}
+	return addr[:6] + "…" + addr[38:]
+}
+
+// Preferences are the kinds of notifications an account receives.
+type Preferences struct {
+	Likes    bool
+	Comments bool
+	Replies  bool
+	Follows  bool
+	Mentions bool
+}
+
+// DefaultPreferences are the preferences of accounts which didn't set any.
+var DefaultPreferences = Preferences{
+	Likes:    true,
+	Comments: true,
+	Replies:  true,
+	Follows:  true,
+	Mentions: true,
+}
+
+// Enabled reports whether notifications of kind are enabled.
+func (p *Preferences) Enabled(kind string) bool {
+	switch kind {
+	case Like:
+		return p.Likes
+	case Comment:
+		return p.Comments
+	case Reply:
+		return p.Replies
+	case Follow:
+		return p.Follows
+	case Mention:
+		return p.Mentions
+	case Report:
+		// Reporters asked to hear back.
+		return true
+	}
+	return false
+}
+
+// GetPreferences returns the notification preferences of account.
+func GetPreferences(ctx context.Context, account string) (*Preferences, error) {
+	row, err := data.DB.GetNotificationPreferences(ctx, account)
+	if errors.Is(err, data.ErrNoRows) {
+		prefs := DefaultPreferences
+		return &prefs, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Preferences{
+		Likes:    row.Likes,
+		Comments: row.Comments,
+		Replies:  row.Replies,
+		Follows:  row.Follows,
+		Mentions: row.Mentions,
+	}, nil
+}
+
+// SavePreferences replaces the notification preferences of account.
+func SavePreferences(ctx context.Context, account string, prefs *Preferences) error {
+	_, err := data.DB.SaveNotificationPreferences(ctx, sqlc.SaveNotificationPreferencesParams{
+		Account:  account,
+		Likes:    prefs.Likes,
+		Comments: prefs.Comments,
+		Replies:  prefs.Replies,
+		Follows:  prefs.Follows,
+		Mentions: prefs.Mentions,
+	})
+	return err
+}
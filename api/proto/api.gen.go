@@ -1,4 +1,4 @@
-// nfteseum-api v0.0.1 9e05f01b91f92696612279f042463c2f2b2a7b2a
+// nfteseum-api v0.0.1 386e69bd906f592b6d66b9037e7ae393c79338ba
 // --
 // This file has been generated by https://github.com/webrpc/webrpc using gen/golang
 // Do not edit by hand. Update your webrpc schema and re-generate.
@@ -15,6 +15,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 )
 
 // WebRPC description and code-gen version
@@ -29,7 +30,7 @@ func WebRPCSchemaVersion() string {
 
 // Schema hash generated from your RIDL schema
 func WebRPCSchemaHash() string {
-	return "9e05f01b91f92696612279f042463c2f2b2a7b2a"
+	return "386e69bd906f592b6d66b9037e7ae393c79338ba"
 }
 
 //
@@ -43,15 +44,245 @@ type Version struct {
 	AppVersion    string `json:"appVersion"`
 }
 
+type Post struct {
+	ID           int32          `json:"id"`
+	Contract     string         `json:"contract"`
+	TokenID      int32          `json:"tokenID"`
+	Author       string         `json:"author"`
+	LikeCount    int32          `json:"likeCount"`
+	CommentCount int32          `json:"commentCount"`
+	CreatedAt    time.Time      `json:"createdAt"`
+	Metadata     *TokenMetadata `json:"metadata"`
+}
+
+type TokenMetadata struct {
+	Name        *string `json:"name"`
+	Description *string `json:"description"`
+	Image       *string `json:"image"`
+}
+
+type User struct {
+	Addr string  `json:"addr"`
+	Name string  `json:"name"`
+	Pfp  *string `json:"pfp"`
+}
+
+type Comment struct {
+	ID        int32     `json:"id"`
+	PostID    int32     `json:"postID"`
+	ParentID  *int32    `json:"parentID"`
+	Author    string    `json:"author"`
+	Content   string    `json:"content"`
+	Entities  []*Entity `json:"entities"`
+	CreatedAt time.Time `json:"createdAt"`
+	Held      bool      `json:"held"`
+}
+
+type Entity struct {
+	Type    string  `json:"type"`
+	Start   int32   `json:"start"`
+	End     int32   `json:"end"`
+	Value   string  `json:"value"`
+	Account *string `json:"account"`
+}
+
+type SearchHit struct {
+	Type    string   `json:"type"`
+	Rank    float32  `json:"rank"`
+	User    *User    `json:"user"`
+	Post    *Post    `json:"post"`
+	Comment *Comment `json:"comment"`
+}
+
+type Report struct {
+	ID         int64      `json:"id"`
+	Reporter   string     `json:"reporter"`
+	TargetType string     `json:"targetType"`
+	TargetID   string     `json:"targetID"`
+	Reason     string     `json:"reason"`
+	Details    *string    `json:"details"`
+	Status     string     `json:"status"`
+	Action     *string    `json:"action"`
+	ResolvedBy *string    `json:"resolvedBy"`
+	ResolvedAt *time.Time `json:"resolvedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+type HeldComment struct {
+	Comment          *Comment  `json:"comment"`
+	Score            float32   `json:"score"`
+	Reasons          []string  `json:"reasons"`
+	AuthorReputation int32     `json:"authorReputation"`
+	HeldAt           time.Time `json:"heldAt"`
+}
+
+type BlocklistEntry struct {
+	ID        int32     `json:"id"`
+	Kind      string    `json:"kind"`
+	Value     string    `json:"value"`
+	CreatedBy string    `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type AuditEntry struct {
+	ID         int64     `json:"id"`
+	Actor      string    `json:"actor"`
+	Action     string    `json:"action"`
+	TargetType string    `json:"targetType"`
+	TargetID   string    `json:"targetID"`
+	Before     *string   `json:"before"`
+	After      *string   `json:"after"`
+	RequestID  string    `json:"requestID"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"createdAt"`
+	PrevHash   string    `json:"prevHash"`
+	Hash       string    `json:"hash"`
+}
+
+type Notification struct {
+	ID         int64     `json:"id"`
+	Kind       string    `json:"kind"`
+	PostID     *int32    `json:"postID"`
+	CommentID  *int32    `json:"commentID"`
+	Actors     []string  `json:"actors"`
+	ActorCount int32     `json:"actorCount"`
+	Read       bool      `json:"read"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+type EmailSettings struct {
+	Email    *string `json:"email"`
+	Verified bool    `json:"verified"`
+	Digest   string  `json:"digest"`
+}
+
+type PushSubscription struct {
+	Endpoint string `json:"endpoint"`
+	P256dh   string `json:"p256dh"`
+	Auth     string `json:"auth"`
+}
+
+type Webhook struct {
+	ID           int64      `json:"id"`
+	Contract     string     `json:"contract"`
+	URL          string     `json:"url"`
+	EventTypes   []string   `json:"eventTypes"`
+	Enabled      bool       `json:"enabled"`
+	FailureCount int32      `json:"failureCount"`
+	DisabledAt   *time.Time `json:"disabledAt"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+}
+
+type WebhookDelivery struct {
+	ID         int64     `json:"id"`
+	DeliveryID string    `json:"deliveryID"`
+	EventType  string    `json:"eventType"`
+	Payload    string    `json:"payload"`
+	StatusCode *int32    `json:"statusCode"`
+	Error      *string   `json:"error"`
+	Response   *string   `json:"response"`
+	DurationMs int32     `json:"durationMs"`
+	Success    bool      `json:"success"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type NotificationPreferences struct {
+	Likes    bool `json:"likes"`
+	Comments bool `json:"comments"`
+	Replies  bool `json:"replies"`
+	Follows  bool `json:"follows"`
+	Mentions bool `json:"mentions"`
+}
+
 type API interface {
 	Ping(ctx context.Context) (bool, error)
 	Version(ctx context.Context) (*Version, error)
+	AddComment(ctx context.Context, postID int32, content string, parentID *int32) (*Comment, error)
+	ListComments(ctx context.Context, postID int32, cursor *string, limit *int32) ([]*Comment, string, error)
+	ListPostsByHashtag(ctx context.Context, tag string, cursor *string, limit *int32) ([]*Post, string, error)
+	LikePost(ctx context.Context, postID int32) (bool, error)
+	UnlikePost(ctx context.Context, postID int32) (bool, error)
+	FollowUser(ctx context.Context, account string) (bool, error)
+	UnfollowUser(ctx context.Context, account string) (bool, error)
+	BlockUser(ctx context.Context, account string) (bool, error)
+	UnblockUser(ctx context.Context, account string) (bool, error)
+	MuteUser(ctx context.Context, account string) (bool, error)
+	UnmuteUser(ctx context.Context, account string) (bool, error)
+	ListBlockedUsers(ctx context.Context, kind string, cursor *string, limit *int32) ([]string, string, error)
+	Search(ctx context.Context, query string, types []string, cursor *string, limit *int32) ([]*SearchHit, string, error)
+	ReportContent(ctx context.Context, targetType string, targetID string, reason string, details *string) (*Report, error)
+	ListReports(ctx context.Context, status *string, targetType *string, targetID *string, reason *string, cursor *string, limit *int32) ([]*Report, string, error)
+	ModerateContent(ctx context.Context, targetType string, targetID string, action string, suspendedUntil *time.Time) (int32, error)
+	ListHeldComments(ctx context.Context, cursor *string, limit *int32) ([]*HeldComment, string, error)
+	ListBlocklist(ctx context.Context) ([]*BlocklistEntry, error)
+	AddBlocklistEntry(ctx context.Context, kind string, value string) (*BlocklistEntry, error)
+	RemoveBlocklistEntry(ctx context.Context, id int32) (bool, error)
+	ListAuditLog(ctx context.Context, actor *string, action *string, targetType *string, targetID *string, cursor *string, limit *int32) ([]*AuditEntry, string, error)
+	VerifyAuditLog(ctx context.Context) (bool, int64, error)
+	ListNotifications(ctx context.Context, cursor *string, limit *int32) ([]*Notification, string, error)
+	MarkNotificationsRead(ctx context.Context, ids []int64, all bool) (int64, error)
+	GetUnreadCount(ctx context.Context) (int64, error)
+	GetNotificationPreferences(ctx context.Context) (*NotificationPreferences, error)
+	UpdateNotificationPreferences(ctx context.Context, preferences *NotificationPreferences) (*NotificationPreferences, error)
+	GetEmailSettings(ctx context.Context) (*EmailSettings, error)
+	SetEmail(ctx context.Context, email string) (*EmailSettings, error)
+	SetDigest(ctx context.Context, frequency string) (*EmailSettings, error)
+	GetPushPublicKey(ctx context.Context) (string, error)
+	RegisterPushSubscription(ctx context.Context, subscription *PushSubscription) (bool, error)
+	RemovePushSubscription(ctx context.Context, endpoint string) (bool, error)
+	CreateWebhook(ctx context.Context, contract string, url string, eventTypes []string) (*Webhook, string, error)
+	ListWebhooks(ctx context.Context) ([]*Webhook, error)
+	UpdateWebhook(ctx context.Context, id int64, url *string, eventTypes []string, enabled *bool) (*Webhook, error)
+	DeleteWebhook(ctx context.Context, id int64) (bool, error)
+	ListWebhookDeliveries(ctx context.Context, id int64, cursor *string, limit *int32) ([]*WebhookDelivery, string, error)
+	SendTestWebhook(ctx context.Context, id int64) (*WebhookDelivery, error)
 }
 
 var WebRPCServices = map[string][]string{
 	"API": {
 		"Ping",
 		"Version",
+		"AddComment",
+		"ListComments",
+		"ListPostsByHashtag",
+		"LikePost",
+		"UnlikePost",
+		"FollowUser",
+		"UnfollowUser",
+		"BlockUser",
+		"UnblockUser",
+		"MuteUser",
+		"UnmuteUser",
+		"ListBlockedUsers",
+		"Search",
+		"ReportContent",
+		"ListReports",
+		"ModerateContent",
+		"ListHeldComments",
+		"ListBlocklist",
+		"AddBlocklistEntry",
+		"RemoveBlocklistEntry",
+		"ListAuditLog",
+		"VerifyAuditLog",
+		"ListNotifications",
+		"MarkNotificationsRead",
+		"GetUnreadCount",
+		"GetNotificationPreferences",
+		"UpdateNotificationPreferences",
+		"GetEmailSettings",
+		"SetEmail",
+		"SetDigest",
+		"GetPushPublicKey",
+		"RegisterPushSubscription",
+		"RemovePushSubscription",
+		"CreateWebhook",
+		"ListWebhooks",
+		"UpdateWebhook",
+		"DeleteWebhook",
+		"ListWebhookDeliveries",
+		"SendTestWebhook",
 	},
 }
 
@@ -92,6 +323,123 @@ func (s *aPIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	case "/rpc/API/Version":
 		s.serveVersion(ctx, w, r)
 		return
+	case "/rpc/API/AddComment":
+		s.serveAddComment(ctx, w, r)
+		return
+	case "/rpc/API/ListComments":
+		s.serveListComments(ctx, w, r)
+		return
+	case "/rpc/API/ListPostsByHashtag":
+		s.serveListPostsByHashtag(ctx, w, r)
+		return
+	case "/rpc/API/LikePost":
+		s.serveLikePost(ctx, w, r)
+		return
+	case "/rpc/API/UnlikePost":
+		s.serveUnlikePost(ctx, w, r)
+		return
+	case "/rpc/API/FollowUser":
+		s.serveFollowUser(ctx, w, r)
+		return
+	case "/rpc/API/UnfollowUser":
+		s.serveUnfollowUser(ctx, w, r)
+		return
+	case "/rpc/API/BlockUser":
+		s.serveBlockUser(ctx, w, r)
+		return
+	case "/rpc/API/UnblockUser":
+		s.serveUnblockUser(ctx, w, r)
+		return
+	case "/rpc/API/MuteUser":
+		s.serveMuteUser(ctx, w, r)
+		return
+	case "/rpc/API/UnmuteUser":
+		s.serveUnmuteUser(ctx, w, r)
+		return
+	case "/rpc/API/ListBlockedUsers":
+		s.serveListBlockedUsers(ctx, w, r)
+		return
+	case "/rpc/API/Search":
+		s.serveSearch(ctx, w, r)
+		return
+	case "/rpc/API/ReportContent":
+		s.serveReportContent(ctx, w, r)
+		return
+	case "/rpc/API/ListReports":
+		s.serveListReports(ctx, w, r)
+		return
+	case "/rpc/API/ModerateContent":
+		s.serveModerateContent(ctx, w, r)
+		return
+	case "/rpc/API/ListHeldComments":
+		s.serveListHeldComments(ctx, w, r)
+		return
+	case "/rpc/API/ListBlocklist":
+		s.serveListBlocklist(ctx, w, r)
+		return
+	case "/rpc/API/AddBlocklistEntry":
+		s.serveAddBlocklistEntry(ctx, w, r)
+		return
+	case "/rpc/API/RemoveBlocklistEntry":
+		s.serveRemoveBlocklistEntry(ctx, w, r)
+		return
+	case "/rpc/API/ListAuditLog":
+		s.serveListAuditLog(ctx, w, r)
+		return
+	case "/rpc/API/VerifyAuditLog":
+		s.serveVerifyAuditLog(ctx, w, r)
+		return
+	case "/rpc/API/ListNotifications":
+		s.serveListNotifications(ctx, w, r)
+		return
+	case "/rpc/API/MarkNotificationsRead":
+		s.serveMarkNotificationsRead(ctx, w, r)
+		return
+	case "/rpc/API/GetUnreadCount":
+		s.serveGetUnreadCount(ctx, w, r)
+		return
+	case "/rpc/API/GetNotificationPreferences":
+		s.serveGetNotificationPreferences(ctx, w, r)
+		return
+	case "/rpc/API/UpdateNotificationPreferences":
+		s.serveUpdateNotificationPreferences(ctx, w, r)
+		return
+	case "/rpc/API/GetEmailSettings":
+		s.serveGetEmailSettings(ctx, w, r)
+		return
+	case "/rpc/API/SetEmail":
+		s.serveSetEmail(ctx, w, r)
+		return
+	case "/rpc/API/SetDigest":
+		s.serveSetDigest(ctx, w, r)
+		return
+	case "/rpc/API/GetPushPublicKey":
+		s.serveGetPushPublicKey(ctx, w, r)
+		return
+	case "/rpc/API/RegisterPushSubscription":
+		s.serveRegisterPushSubscription(ctx, w, r)
+		return
+	case "/rpc/API/RemovePushSubscription":
+		s.serveRemovePushSubscription(ctx, w, r)
+		return
+	case "/rpc/API/CreateWebhook":
+		s.serveCreateWebhook(ctx, w, r)
+		return
+	case "/rpc/API/ListWebhooks":
+		s.serveListWebhooks(ctx, w, r)
+		return
+	case "/rpc/API/UpdateWebhook":
+		s.serveUpdateWebhook(ctx, w, r)
+		return
+	case "/rpc/API/DeleteWebhook":
+		s.serveDeleteWebhook(ctx, w, r)
+		return
+	case "/rpc/API/ListWebhookDeliveries":
+		s.serveListWebhookDeliveries(ctx, w, r)
+		return
+	case "/rpc/API/SendTestWebhook":
+		s.serveSendTestWebhook(ctx, w, r)
+		return
 	default:
 		err := Errorf(ErrBadRoute, "no handler for path %q", r.URL.Path)
 		RespondWithError(w, err)
@@ -203,59 +551,3258 @@ func (s *aPIServer) serveVersionJSON(ctx context.Context, w http.ResponseWriter,
 	w.Write(respBody)
 }
 
-func RespondWithError(w http.ResponseWriter, err error) {
-	rpcErr, ok := err.(Error)
-	if !ok {
-		rpcErr = WrapError(ErrInternal, err, "webrpc error")
+func (s *aPIServer) serveAddComment(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
 	}
 
-	statusCode := HTTPStatusFromErrorCode(rpcErr.Code())
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveAddCommentJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
+func (s *aPIServer) serveAddCommentJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "AddComment")
+	reqContent := struct {
+		Arg0 int32  `json:"postID"`
+		Arg1 string `json:"content"`
+		Arg2 *int32 `json:"parentID"`
+	}{}
 
-	respBody, _ := json.Marshal(rpcErr.Payload())
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 *Comment
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.AddComment(ctx, reqContent.Arg0, reqContent.Arg1, reqContent.Arg2)
+	}()
+	respContent := struct {
+		Ret0 *Comment `json:"comment"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
 	w.Write(respBody)
 }
 
-//
-// Client
-//
+func (s *aPIServer) serveListComments(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
 
-const APIPathPrefix = "/rpc/API/"
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveListCommentsJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
 
-type aPIClient struct {
-	client HTTPClient
-	urls   [2]string
+func (s *aPIServer) serveListCommentsJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "ListComments")
+	reqContent := struct {
+		Arg0 int32   `json:"postID"`
+		Arg1 *string `json:"cursor"`
+		Arg2 *int32  `json:"limit"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 []*Comment
+	var ret1 string
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, ret1, err = s.API.ListComments(ctx, reqContent.Arg0, reqContent.Arg1, reqContent.Arg2)
+	}()
+	respContent := struct {
+		Ret0 []*Comment `json:"comments"`
+		Ret1 string     `json:"nextCursor"`
+	}{ret0, ret1}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
 }
 
-func NewAPIClient(addr string, client HTTPClient) API {
-	prefix := urlBase(addr) + APIPathPrefix
-	urls := [2]string{
-		prefix + "Ping",
-		prefix + "Version",
+func (s *aPIServer) serveListPostsByHashtag(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
 	}
-	return &aPIClient{
-		client: client,
-		urls:   urls,
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveListPostsByHashtagJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
 	}
 }
 
-func (c *aPIClient) Ping(ctx context.Context) (bool, error) {
-	out := struct {
-		Ret0 bool `json:"status"`
+func (s *aPIServer) serveListPostsByHashtagJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "ListPostsByHashtag")
+	reqContent := struct {
+		Arg0 string  `json:"tag"`
+		Arg1 *string `json:"cursor"`
+		Arg2 *int32  `json:"limit"`
 	}{}
 
-	err := doJSONRequest(ctx, c.client, c.urls[0], nil, &out)
-	return out.Ret0, err
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 []*Post
+	var ret1 string
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, ret1, err = s.API.ListPostsByHashtag(ctx, reqContent.Arg0, reqContent.Arg1, reqContent.Arg2)
+	}()
+	respContent := struct {
+		Ret0 []*Post `json:"posts"`
+		Ret1 string  `json:"nextCursor"`
+	}{ret0, ret1}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
 }
 
-func (c *aPIClient) Version(ctx context.Context) (*Version, error) {
-	out := struct {
-		Ret0 *Version `json:"version"`
-	}{}
+func (s *aPIServer) serveLikePost(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
 
-	err := doJSONRequest(ctx, c.client, c.urls[1], nil, &out)
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveLikePostJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveLikePostJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "LikePost")
+	reqContent := struct {
+		Arg0 int32 `json:"postID"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 bool
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.LikePost(ctx, reqContent.Arg0)
+	}()
+	respContent := struct {
+		Ret0 bool `json:"liked"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveUnlikePost(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveUnlikePostJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveUnlikePostJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "UnlikePost")
+	reqContent := struct {
+		Arg0 int32 `json:"postID"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 bool
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.UnlikePost(ctx, reqContent.Arg0)
+	}()
+	respContent := struct {
+		Ret0 bool `json:"unliked"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveFollowUser(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveFollowUserJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveFollowUserJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "FollowUser")
+	reqContent := struct {
+		Arg0 string `json:"account"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 bool
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.FollowUser(ctx, reqContent.Arg0)
+	}()
+	respContent := struct {
+		Ret0 bool `json:"followed"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveUnfollowUser(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveUnfollowUserJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveUnfollowUserJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "UnfollowUser")
+	reqContent := struct {
+		Arg0 string `json:"account"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 bool
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.UnfollowUser(ctx, reqContent.Arg0)
+	}()
+	respContent := struct {
+		Ret0 bool `json:"unfollowed"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveBlockUser(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveBlockUserJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveBlockUserJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "BlockUser")
+	reqContent := struct {
+		Arg0 string `json:"account"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 bool
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.BlockUser(ctx, reqContent.Arg0)
+	}()
+	respContent := struct {
+		Ret0 bool `json:"blocked"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveUnblockUser(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveUnblockUserJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveUnblockUserJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "UnblockUser")
+	reqContent := struct {
+		Arg0 string `json:"account"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 bool
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.UnblockUser(ctx, reqContent.Arg0)
+	}()
+	respContent := struct {
+		Ret0 bool `json:"unblocked"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveMuteUser(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveMuteUserJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveMuteUserJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "MuteUser")
+	reqContent := struct {
+		Arg0 string `json:"account"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 bool
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.MuteUser(ctx, reqContent.Arg0)
+	}()
+	respContent := struct {
+		Ret0 bool `json:"muted"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveUnmuteUser(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveUnmuteUserJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveUnmuteUserJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "UnmuteUser")
+	reqContent := struct {
+		Arg0 string `json:"account"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 bool
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.UnmuteUser(ctx, reqContent.Arg0)
+	}()
+	respContent := struct {
+		Ret0 bool `json:"unmuted"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveListBlockedUsers(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveListBlockedUsersJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveListBlockedUsersJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "ListBlockedUsers")
+	reqContent := struct {
+		Arg0 string  `json:"kind"`
+		Arg1 *string `json:"cursor"`
+		Arg2 *int32  `json:"limit"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 []string
+	var ret1 string
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, ret1, err = s.API.ListBlockedUsers(ctx, reqContent.Arg0, reqContent.Arg1, reqContent.Arg2)
+	}()
+	respContent := struct {
+		Ret0 []string `json:"accounts"`
+		Ret1 string   `json:"nextCursor"`
+	}{ret0, ret1}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveSearch(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveSearchJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveSearchJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "Search")
+	reqContent := struct {
+		Arg0 string   `json:"query"`
+		Arg1 []string `json:"types"`
+		Arg2 *string  `json:"cursor"`
+		Arg3 *int32   `json:"limit"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 []*SearchHit
+	var ret1 string
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, ret1, err = s.API.Search(ctx, reqContent.Arg0, reqContent.Arg1, reqContent.Arg2, reqContent.Arg3)
+	}()
+	respContent := struct {
+		Ret0 []*SearchHit `json:"hits"`
+		Ret1 string       `json:"nextCursor"`
+	}{ret0, ret1}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveReportContent(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveReportContentJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveReportContentJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "ReportContent")
+	reqContent := struct {
+		Arg0 string  `json:"targetType"`
+		Arg1 string  `json:"targetID"`
+		Arg2 string  `json:"reason"`
+		Arg3 *string `json:"details"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 *Report
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.ReportContent(ctx, reqContent.Arg0, reqContent.Arg1, reqContent.Arg2, reqContent.Arg3)
+	}()
+	respContent := struct {
+		Ret0 *Report `json:"report"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveListReports(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveListReportsJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveListReportsJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "ListReports")
+	reqContent := struct {
+		Arg0 *string `json:"status"`
+		Arg1 *string `json:"targetType"`
+		Arg2 *string `json:"targetID"`
+		Arg3 *string `json:"reason"`
+		Arg4 *string `json:"cursor"`
+		Arg5 *int32  `json:"limit"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 []*Report
+	var ret1 string
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, ret1, err = s.API.ListReports(ctx, reqContent.Arg0, reqContent.Arg1, reqContent.Arg2, reqContent.Arg3, reqContent.Arg4, reqContent.Arg5)
+	}()
+	respContent := struct {
+		Ret0 []*Report `json:"reports"`
+		Ret1 string    `json:"nextCursor"`
+	}{ret0, ret1}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveModerateContent(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveModerateContentJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveModerateContentJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "ModerateContent")
+	reqContent := struct {
+		Arg0 string     `json:"targetType"`
+		Arg1 string     `json:"targetID"`
+		Arg2 string     `json:"action"`
+		Arg3 *time.Time `json:"suspendedUntil"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 int32
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.ModerateContent(ctx, reqContent.Arg0, reqContent.Arg1, reqContent.Arg2, reqContent.Arg3)
+	}()
+	respContent := struct {
+		Ret0 int32 `json:"resolvedReports"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveListHeldComments(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveListHeldCommentsJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveListHeldCommentsJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "ListHeldComments")
+	reqContent := struct {
+		Arg0 *string `json:"cursor"`
+		Arg1 *int32  `json:"limit"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 []*HeldComment
+	var ret1 string
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, ret1, err = s.API.ListHeldComments(ctx, reqContent.Arg0, reqContent.Arg1)
+	}()
+	respContent := struct {
+		Ret0 []*HeldComment `json:"comments"`
+		Ret1 string         `json:"nextCursor"`
+	}{ret0, ret1}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveListBlocklist(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveListBlocklistJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveListBlocklistJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "ListBlocklist")
+
+	// Call service method
+	var ret0 []*BlocklistEntry
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.ListBlocklist(ctx)
+	}()
+	respContent := struct {
+		Ret0 []*BlocklistEntry `json:"entries"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveAddBlocklistEntry(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveAddBlocklistEntryJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveAddBlocklistEntryJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "AddBlocklistEntry")
+	reqContent := struct {
+		Arg0 string `json:"kind"`
+		Arg1 string `json:"value"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 *BlocklistEntry
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.AddBlocklistEntry(ctx, reqContent.Arg0, reqContent.Arg1)
+	}()
+	respContent := struct {
+		Ret0 *BlocklistEntry `json:"entry"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveRemoveBlocklistEntry(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveRemoveBlocklistEntryJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveRemoveBlocklistEntryJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "RemoveBlocklistEntry")
+	reqContent := struct {
+		Arg0 int32 `json:"id"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 bool
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.RemoveBlocklistEntry(ctx, reqContent.Arg0)
+	}()
+	respContent := struct {
+		Ret0 bool `json:"removed"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveListAuditLog(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveListAuditLogJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveListAuditLogJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "ListAuditLog")
+	reqContent := struct {
+		Arg0 *string `json:"actor"`
+		Arg1 *string `json:"action"`
+		Arg2 *string `json:"targetType"`
+		Arg3 *string `json:"targetID"`
+		Arg4 *string `json:"cursor"`
+		Arg5 *int32  `json:"limit"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 []*AuditEntry
+	var ret1 string
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, ret1, err = s.API.ListAuditLog(ctx, reqContent.Arg0, reqContent.Arg1, reqContent.Arg2, reqContent.Arg3, reqContent.Arg4, reqContent.Arg5)
+	}()
+	respContent := struct {
+		Ret0 []*AuditEntry `json:"entries"`
+		Ret1 string        `json:"nextCursor"`
+	}{ret0, ret1}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveVerifyAuditLog(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveVerifyAuditLogJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveVerifyAuditLogJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "VerifyAuditLog")
+
+	// Call service method
+	var ret0 bool
+	var ret1 int64
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, ret1, err = s.API.VerifyAuditLog(ctx)
+	}()
+	respContent := struct {
+		Ret0 bool  `json:"valid"`
+		Ret1 int64 `json:"brokenAt"`
+	}{ret0, ret1}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveListNotifications(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveListNotificationsJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveListNotificationsJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "ListNotifications")
+	reqContent := struct {
+		Arg0 *string `json:"cursor"`
+		Arg1 *int32  `json:"limit"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 []*Notification
+	var ret1 string
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, ret1, err = s.API.ListNotifications(ctx, reqContent.Arg0, reqContent.Arg1)
+	}()
+	respContent := struct {
+		Ret0 []*Notification `json:"notifications"`
+		Ret1 string          `json:"nextCursor"`
+	}{ret0, ret1}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveMarkNotificationsRead(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveMarkNotificationsReadJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveMarkNotificationsReadJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "MarkNotificationsRead")
+	reqContent := struct {
+		Arg0 []int64 `json:"ids"`
+		Arg1 bool    `json:"all"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 int64
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.MarkNotificationsRead(ctx, reqContent.Arg0, reqContent.Arg1)
+	}()
+	respContent := struct {
+		Ret0 int64 `json:"count"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveGetUnreadCount(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveGetUnreadCountJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveGetUnreadCountJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "GetUnreadCount")
+
+	// Call service method
+	var ret0 int64
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.GetUnreadCount(ctx)
+	}()
+	respContent := struct {
+		Ret0 int64 `json:"count"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveGetNotificationPreferences(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveGetNotificationPreferencesJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveGetNotificationPreferencesJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "GetNotificationPreferences")
+
+	// Call service method
+	var ret0 *NotificationPreferences
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.GetNotificationPreferences(ctx)
+	}()
+	respContent := struct {
+		Ret0 *NotificationPreferences `json:"preferences"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveUpdateNotificationPreferences(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveUpdateNotificationPreferencesJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveUpdateNotificationPreferencesJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "UpdateNotificationPreferences")
+	reqContent := struct {
+		Arg0 *NotificationPreferences `json:"preferences"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 *NotificationPreferences
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.UpdateNotificationPreferences(ctx, reqContent.Arg0)
+	}()
+	respContent := struct {
+		Ret0 *NotificationPreferences `json:"preferences"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveGetEmailSettings(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveGetEmailSettingsJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveGetEmailSettingsJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "GetEmailSettings")
+
+	// Call service method
+	var ret0 *EmailSettings
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.GetEmailSettings(ctx)
+	}()
+	respContent := struct {
+		Ret0 *EmailSettings `json:"settings"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveSetEmail(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveSetEmailJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveSetEmailJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "SetEmail")
+	reqContent := struct {
+		Arg0 string `json:"email"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 *EmailSettings
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.SetEmail(ctx, reqContent.Arg0)
+	}()
+	respContent := struct {
+		Ret0 *EmailSettings `json:"settings"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveSetDigest(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveSetDigestJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveSetDigestJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "SetDigest")
+	reqContent := struct {
+		Arg0 string `json:"frequency"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 *EmailSettings
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.SetDigest(ctx, reqContent.Arg0)
+	}()
+	respContent := struct {
+		Ret0 *EmailSettings `json:"settings"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveGetPushPublicKey(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveGetPushPublicKeyJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveGetPushPublicKeyJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "GetPushPublicKey")
+
+	// Call service method
+	var ret0 string
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.GetPushPublicKey(ctx)
+	}()
+	respContent := struct {
+		Ret0 string `json:"key"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveRegisterPushSubscription(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveRegisterPushSubscriptionJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveRegisterPushSubscriptionJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "RegisterPushSubscription")
+	reqContent := struct {
+		Arg0 *PushSubscription `json:"subscription"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 bool
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.RegisterPushSubscription(ctx, reqContent.Arg0)
+	}()
+	respContent := struct {
+		Ret0 bool `json:"status"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveRemovePushSubscription(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveRemovePushSubscriptionJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveRemovePushSubscriptionJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "RemovePushSubscription")
+	reqContent := struct {
+		Arg0 string `json:"endpoint"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 bool
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.RemovePushSubscription(ctx, reqContent.Arg0)
+	}()
+	respContent := struct {
+		Ret0 bool `json:"removed"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveCreateWebhook(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveCreateWebhookJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveCreateWebhookJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "CreateWebhook")
+	reqContent := struct {
+		Arg0 string   `json:"contract"`
+		Arg1 string   `json:"url"`
+		Arg2 []string `json:"eventTypes"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 *Webhook
+	var ret1 string
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, ret1, err = s.API.CreateWebhook(ctx, reqContent.Arg0, reqContent.Arg1, reqContent.Arg2)
+	}()
+	respContent := struct {
+		Ret0 *Webhook `json:"webhook"`
+		Ret1 string   `json:"secret"`
+	}{ret0, ret1}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveListWebhooks(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveListWebhooksJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveListWebhooksJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "ListWebhooks")
+
+	// Call service method
+	var ret0 []*Webhook
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.ListWebhooks(ctx)
+	}()
+	respContent := struct {
+		Ret0 []*Webhook `json:"webhooks"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveUpdateWebhook(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveUpdateWebhookJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveUpdateWebhookJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "UpdateWebhook")
+	reqContent := struct {
+		Arg0 int64    `json:"id"`
+		Arg1 *string  `json:"url"`
+		Arg2 []string `json:"eventTypes"`
+		Arg3 *bool    `json:"enabled"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 *Webhook
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.UpdateWebhook(ctx, reqContent.Arg0, reqContent.Arg1, reqContent.Arg2, reqContent.Arg3)
+	}()
+	respContent := struct {
+		Ret0 *Webhook `json:"webhook"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveDeleteWebhook(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveDeleteWebhookJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveDeleteWebhookJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "DeleteWebhook")
+	reqContent := struct {
+		Arg0 int64 `json:"id"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 bool
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.DeleteWebhook(ctx, reqContent.Arg0)
+	}()
+	respContent := struct {
+		Ret0 bool `json:"deleted"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveListWebhookDeliveries(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveListWebhookDeliveriesJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveListWebhookDeliveriesJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "ListWebhookDeliveries")
+	reqContent := struct {
+		Arg0 int64   `json:"id"`
+		Arg1 *string `json:"cursor"`
+		Arg2 *int32  `json:"limit"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 []*WebhookDelivery
+	var ret1 string
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, ret1, err = s.API.ListWebhookDeliveries(ctx, reqContent.Arg0, reqContent.Arg1, reqContent.Arg2)
+	}()
+	respContent := struct {
+		Ret0 []*WebhookDelivery `json:"deliveries"`
+		Ret1 string             `json:"nextCursor"`
+	}{ret0, ret1}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func (s *aPIServer) serveSendTestWebhook(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	header := r.Header.Get("Content-Type")
+	i := strings.Index(header, ";")
+	if i == -1 {
+		i = len(header)
+	}
+
+	switch strings.TrimSpace(strings.ToLower(header[:i])) {
+	case "application/json":
+		s.serveSendTestWebhookJSON(ctx, w, r)
+	default:
+		err := Errorf(ErrBadRoute, "unexpected Content-Type: %q", r.Header.Get("Content-Type"))
+		RespondWithError(w, err)
+	}
+}
+
+func (s *aPIServer) serveSendTestWebhookJSON(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx = context.WithValue(ctx, MethodNameCtxKey, "SendTestWebhook")
+	reqContent := struct {
+		Arg0 int64 `json:"id"`
+	}{}
+
+	reqBody, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to read request data")
+		RespondWithError(w, err)
+		return
+	}
+	defer r.Body.Close()
+
+	err = json.Unmarshal(reqBody, &reqContent)
+	if err != nil {
+		err = WrapError(ErrInvalidArgument, err, "failed to unmarshal request data")
+		RespondWithError(w, err)
+		return
+	}
+
+	// Call service method
+	var ret0 *WebhookDelivery
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if rr := recover(); rr != nil {
+				RespondWithError(w, ErrorInternal("internal service panic"))
+				panic(rr)
+			}
+		}()
+		ret0, err = s.API.SendTestWebhook(ctx, reqContent.Arg0)
+	}()
+	respContent := struct {
+		Ret0 *WebhookDelivery `json:"delivery"`
+	}{ret0}
+
+	if err != nil {
+		RespondWithError(w, err)
+		return
+	}
+	respBody, err := json.Marshal(respContent)
+	if err != nil {
+		err = WrapError(ErrInternal, err, "failed to marshal json response")
+		RespondWithError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	w.Write(respBody)
+}
+
+func RespondWithError(w http.ResponseWriter, err error) {
+	rpcErr, ok := err.(Error)
+	if !ok {
+		rpcErr = WrapError(ErrInternal, err, "webrpc error")
+	}
+
+	statusCode := HTTPStatusFromErrorCode(rpcErr.Code())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+
+	respBody, _ := json.Marshal(rpcErr.Payload())
+	w.Write(respBody)
+}
+
+//
+// Client
+//
+
+const APIPathPrefix = "/rpc/API/"
+
+type aPIClient struct {
+	client HTTPClient
+	urls   [41]string
+}
+
+func NewAPIClient(addr string, client HTTPClient) API {
+	prefix := urlBase(addr) + APIPathPrefix
+	urls := [41]string{
+		prefix + "Ping",
+		prefix + "Version",
+		prefix + "AddComment",
+		prefix + "ListComments",
+		prefix + "ListPostsByHashtag",
+		prefix + "LikePost",
+		prefix + "UnlikePost",
+		prefix + "FollowUser",
+		prefix + "UnfollowUser",
+		prefix + "BlockUser",
+		prefix + "UnblockUser",
+		prefix + "MuteUser",
+		prefix + "UnmuteUser",
+		prefix + "ListBlockedUsers",
+		prefix + "Search",
+		prefix + "ReportContent",
+		prefix + "ListReports",
+		prefix + "ModerateContent",
+		prefix + "ListHeldComments",
+		prefix + "ListBlocklist",
+		prefix + "AddBlocklistEntry",
+		prefix + "RemoveBlocklistEntry",
+		prefix + "ListAuditLog",
+		prefix + "VerifyAuditLog",
+		prefix + "ListNotifications",
+		prefix + "MarkNotificationsRead",
+		prefix + "GetUnreadCount",
+		prefix + "GetNotificationPreferences",
+		prefix + "UpdateNotificationPreferences",
+		prefix + "GetEmailSettings",
+		prefix + "SetEmail",
+		prefix + "SetDigest",
+		prefix + "GetPushPublicKey",
+		prefix + "RegisterPushSubscription",
+		prefix + "RemovePushSubscription",
+		prefix + "CreateWebhook",
+		prefix + "ListWebhooks",
+		prefix + "UpdateWebhook",
+		prefix + "DeleteWebhook",
+		prefix + "ListWebhookDeliveries",
+		prefix + "SendTestWebhook",
+	}
+	return &aPIClient{
+		client: client,
+		urls:   urls,
+	}
+}
+
+func (c *aPIClient) Ping(ctx context.Context) (bool, error) {
+	out := struct {
+		Ret0 bool `json:"status"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[0], nil, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) Version(ctx context.Context) (*Version, error) {
+	out := struct {
+		Ret0 *Version `json:"version"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[1], nil, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) AddComment(ctx context.Context, postID int32, content string, parentID *int32) (*Comment, error) {
+	in := struct {
+		Arg0 int32  `json:"postID"`
+		Arg1 string `json:"content"`
+		Arg2 *int32 `json:"parentID"`
+	}{postID, content, parentID}
+	out := struct {
+		Ret0 *Comment `json:"comment"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[2], in, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) ListComments(ctx context.Context, postID int32, cursor *string, limit *int32) ([]*Comment, string, error) {
+	in := struct {
+		Arg0 int32   `json:"postID"`
+		Arg1 *string `json:"cursor"`
+		Arg2 *int32  `json:"limit"`
+	}{postID, cursor, limit}
+	out := struct {
+		Ret0 []*Comment `json:"comments"`
+		Ret1 string     `json:"nextCursor"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[3], in, &out)
+	return out.Ret0, out.Ret1, err
+}
+
+func (c *aPIClient) ListPostsByHashtag(ctx context.Context, tag string, cursor *string, limit *int32) ([]*Post, string, error) {
+	in := struct {
+		Arg0 string  `json:"tag"`
+		Arg1 *string `json:"cursor"`
+		Arg2 *int32  `json:"limit"`
+	}{tag, cursor, limit}
+	out := struct {
+		Ret0 []*Post `json:"posts"`
+		Ret1 string  `json:"nextCursor"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[4], in, &out)
+	return out.Ret0, out.Ret1, err
+}
+
+func (c *aPIClient) LikePost(ctx context.Context, postID int32) (bool, error) {
+	in := struct {
+		Arg0 int32 `json:"postID"`
+	}{postID}
+	out := struct {
+		Ret0 bool `json:"liked"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[5], in, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) UnlikePost(ctx context.Context, postID int32) (bool, error) {
+	in := struct {
+		Arg0 int32 `json:"postID"`
+	}{postID}
+	out := struct {
+		Ret0 bool `json:"unliked"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[6], in, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) FollowUser(ctx context.Context, account string) (bool, error) {
+	in := struct {
+		Arg0 string `json:"account"`
+	}{account}
+	out := struct {
+		Ret0 bool `json:"followed"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[7], in, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) UnfollowUser(ctx context.Context, account string) (bool, error) {
+	in := struct {
+		Arg0 string `json:"account"`
+	}{account}
+	out := struct {
+		Ret0 bool `json:"unfollowed"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[8], in, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) BlockUser(ctx context.Context, account string) (bool, error) {
+	in := struct {
+		Arg0 string `json:"account"`
+	}{account}
+	out := struct {
+		Ret0 bool `json:"blocked"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[9], in, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) UnblockUser(ctx context.Context, account string) (bool, error) {
+	in := struct {
+		Arg0 string `json:"account"`
+	}{account}
+	out := struct {
+		Ret0 bool `json:"unblocked"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[10], in, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) MuteUser(ctx context.Context, account string) (bool, error) {
+	in := struct {
+		Arg0 string `json:"account"`
+	}{account}
+	out := struct {
+		Ret0 bool `json:"muted"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[11], in, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) UnmuteUser(ctx context.Context, account string) (bool, error) {
+	in := struct {
+		Arg0 string `json:"account"`
+	}{account}
+	out := struct {
+		Ret0 bool `json:"unmuted"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[12], in, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) ListBlockedUsers(ctx context.Context, kind string, cursor *string, limit *int32) ([]string, string, error) {
+	in := struct {
+		Arg0 string  `json:"kind"`
+		Arg1 *string `json:"cursor"`
+		Arg2 *int32  `json:"limit"`
+	}{kind, cursor, limit}
+	out := struct {
+		Ret0 []string `json:"accounts"`
+		Ret1 string   `json:"nextCursor"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[13], in, &out)
+	return out.Ret0, out.Ret1, err
+}
+
+func (c *aPIClient) Search(ctx context.Context, query string, types []string, cursor *string, limit *int32) ([]*SearchHit, string, error) {
+	in := struct {
+		Arg0 string   `json:"query"`
+		Arg1 []string `json:"types"`
+		Arg2 *string  `json:"cursor"`
+		Arg3 *int32   `json:"limit"`
+	}{query, types, cursor, limit}
+	out := struct {
+		Ret0 []*SearchHit `json:"hits"`
+		Ret1 string       `json:"nextCursor"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[14], in, &out)
+	return out.Ret0, out.Ret1, err
+}
+
+func (c *aPIClient) ReportContent(ctx context.Context, targetType string, targetID string, reason string, details *string) (*Report, error) {
+	in := struct {
+		Arg0 string  `json:"targetType"`
+		Arg1 string  `json:"targetID"`
+		Arg2 string  `json:"reason"`
+		Arg3 *string `json:"details"`
+	}{targetType, targetID, reason, details}
+	out := struct {
+		Ret0 *Report `json:"report"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[15], in, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) ListReports(ctx context.Context, status *string, targetType *string, targetID *string, reason *string, cursor *string, limit *int32) ([]*Report, string, error) {
+	in := struct {
+		Arg0 *string `json:"status"`
+		Arg1 *string `json:"targetType"`
+		Arg2 *string `json:"targetID"`
+		Arg3 *string `json:"reason"`
+		Arg4 *string `json:"cursor"`
+		Arg5 *int32  `json:"limit"`
+	}{status, targetType, targetID, reason, cursor, limit}
+	out := struct {
+		Ret0 []*Report `json:"reports"`
+		Ret1 string    `json:"nextCursor"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[16], in, &out)
+	return out.Ret0, out.Ret1, err
+}
+
+func (c *aPIClient) ModerateContent(ctx context.Context, targetType string, targetID string, action string, suspendedUntil *time.Time) (int32, error) {
+	in := struct {
+		Arg0 string     `json:"targetType"`
+		Arg1 string     `json:"targetID"`
+		Arg2 string     `json:"action"`
+		Arg3 *time.Time `json:"suspendedUntil"`
+	}{targetType, targetID, action, suspendedUntil}
+	out := struct {
+		Ret0 int32 `json:"resolvedReports"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[17], in, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) ListHeldComments(ctx context.Context, cursor *string, limit *int32) ([]*HeldComment, string, error) {
+	in := struct {
+		Arg0 *string `json:"cursor"`
+		Arg1 *int32  `json:"limit"`
+	}{cursor, limit}
+	out := struct {
+		Ret0 []*HeldComment `json:"comments"`
+		Ret1 string         `json:"nextCursor"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[18], in, &out)
+	return out.Ret0, out.Ret1, err
+}
+
+func (c *aPIClient) ListBlocklist(ctx context.Context) ([]*BlocklistEntry, error) {
+	out := struct {
+		Ret0 []*BlocklistEntry `json:"entries"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[19], nil, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) AddBlocklistEntry(ctx context.Context, kind string, value string) (*BlocklistEntry, error) {
+	in := struct {
+		Arg0 string `json:"kind"`
+		Arg1 string `json:"value"`
+	}{kind, value}
+	out := struct {
+		Ret0 *BlocklistEntry `json:"entry"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[20], in, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) RemoveBlocklistEntry(ctx context.Context, id int32) (bool, error) {
+	in := struct {
+		Arg0 int32 `json:"id"`
+	}{id}
+	out := struct {
+		Ret0 bool `json:"removed"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[21], in, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) ListAuditLog(ctx context.Context, actor *string, action *string, targetType *string, targetID *string, cursor *string, limit *int32) ([]*AuditEntry, string, error) {
+	in := struct {
+		Arg0 *string `json:"actor"`
+		Arg1 *string `json:"action"`
+		Arg2 *string `json:"targetType"`
+		Arg3 *string `json:"targetID"`
+		Arg4 *string `json:"cursor"`
+		Arg5 *int32  `json:"limit"`
+	}{actor, action, targetType, targetID, cursor, limit}
+	out := struct {
+		Ret0 []*AuditEntry `json:"entries"`
+		Ret1 string        `json:"nextCursor"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[22], in, &out)
+	return out.Ret0, out.Ret1, err
+}
+
+func (c *aPIClient) VerifyAuditLog(ctx context.Context) (bool, int64, error) {
+	out := struct {
+		Ret0 bool  `json:"valid"`
+		Ret1 int64 `json:"brokenAt"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[23], nil, &out)
+	return out.Ret0, out.Ret1, err
+}
+
+func (c *aPIClient) ListNotifications(ctx context.Context, cursor *string, limit *int32) ([]*Notification, string, error) {
+	in := struct {
+		Arg0 *string `json:"cursor"`
+		Arg1 *int32  `json:"limit"`
+	}{cursor, limit}
+	out := struct {
+		Ret0 []*Notification `json:"notifications"`
+		Ret1 string          `json:"nextCursor"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[24], in, &out)
+	return out.Ret0, out.Ret1, err
+}
+
+func (c *aPIClient) MarkNotificationsRead(ctx context.Context, ids []int64, all bool) (int64, error) {
+	in := struct {
+		Arg0 []int64 `json:"ids"`
+		Arg1 bool    `json:"all"`
+	}{ids, all}
+	out := struct {
+		Ret0 int64 `json:"count"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[25], in, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) GetUnreadCount(ctx context.Context) (int64, error) {
+	out := struct {
+		Ret0 int64 `json:"count"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[26], nil, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) GetNotificationPreferences(ctx context.Context) (*NotificationPreferences, error) {
+	out := struct {
+		Ret0 *NotificationPreferences `json:"preferences"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[27], nil, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) UpdateNotificationPreferences(ctx context.Context, preferences *NotificationPreferences) (*NotificationPreferences, error) {
+	in := struct {
+		Arg0 *NotificationPreferences `json:"preferences"`
+	}{preferences}
+	out := struct {
+		Ret0 *NotificationPreferences `json:"preferences"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[28], in, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) GetEmailSettings(ctx context.Context) (*EmailSettings, error) {
+	out := struct {
+		Ret0 *EmailSettings `json:"settings"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[29], nil, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) SetEmail(ctx context.Context, email string) (*EmailSettings, error) {
+	in := struct {
+		Arg0 string `json:"email"`
+	}{email}
+	out := struct {
+		Ret0 *EmailSettings `json:"settings"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[30], in, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) SetDigest(ctx context.Context, frequency string) (*EmailSettings, error) {
+	in := struct {
+		Arg0 string `json:"frequency"`
+	}{frequency}
+	out := struct {
+		Ret0 *EmailSettings `json:"settings"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[31], in, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) GetPushPublicKey(ctx context.Context) (string, error) {
+	out := struct {
+		Ret0 string `json:"key"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[32], nil, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) RegisterPushSubscription(ctx context.Context, subscription *PushSubscription) (bool, error) {
+	in := struct {
+		Arg0 *PushSubscription `json:"subscription"`
+	}{subscription}
+	out := struct {
+		Ret0 bool `json:"status"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[33], in, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) RemovePushSubscription(ctx context.Context, endpoint string) (bool, error) {
+	in := struct {
+		Arg0 string `json:"endpoint"`
+	}{endpoint}
+	out := struct {
+		Ret0 bool `json:"removed"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[34], in, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) CreateWebhook(ctx context.Context, contract string, url string, eventTypes []string) (*Webhook, string, error) {
+	in := struct {
+		Arg0 string   `json:"contract"`
+		Arg1 string   `json:"url"`
+		Arg2 []string `json:"eventTypes"`
+	}{contract, url, eventTypes}
+	out := struct {
+		Ret0 *Webhook `json:"webhook"`
+		Ret1 string   `json:"secret"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[35], in, &out)
+	return out.Ret0, out.Ret1, err
+}
+
+func (c *aPIClient) ListWebhooks(ctx context.Context) ([]*Webhook, error) {
+	out := struct {
+		Ret0 []*Webhook `json:"webhooks"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[36], nil, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) UpdateWebhook(ctx context.Context, id int64, url *string, eventTypes []string, enabled *bool) (*Webhook, error) {
+	in := struct {
+		Arg0 int64    `json:"id"`
+		Arg1 *string  `json:"url"`
+		Arg2 []string `json:"eventTypes"`
+		Arg3 *bool    `json:"enabled"`
+	}{id, url, eventTypes, enabled}
+	out := struct {
+		Ret0 *Webhook `json:"webhook"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[37], in, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) DeleteWebhook(ctx context.Context, id int64) (bool, error) {
+	in := struct {
+		Arg0 int64 `json:"id"`
+	}{id}
+	out := struct {
+		Ret0 bool `json:"deleted"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[38], in, &out)
+	return out.Ret0, err
+}
+
+func (c *aPIClient) ListWebhookDeliveries(ctx context.Context, id int64, cursor *string, limit *int32) ([]*WebhookDelivery, string, error) {
+	in := struct {
+		Arg0 int64   `json:"id"`
+		Arg1 *string `json:"cursor"`
+		Arg2 *int32  `json:"limit"`
+	}{id, cursor, limit}
+	out := struct {
+		Ret0 []*WebhookDelivery `json:"deliveries"`
+		Ret1 string             `json:"nextCursor"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[39], in, &out)
+	return out.Ret0, out.Ret1, err
+}
+
+func (c *aPIClient) SendTestWebhook(ctx context.Context, id int64) (*WebhookDelivery, error) {
+	in := struct {
+		Arg0 int64 `json:"id"`
+	}{id}
+	out := struct {
+		Ret0 *WebhookDelivery `json:"delivery"`
+	}{}
+
+	err := doJSONRequest(ctx, c.client, c.urls[40], in, &out)
 	return out.Ret0, err
 }
 
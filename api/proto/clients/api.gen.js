@@ -1,4 +1,4 @@
-// nfteseum-api v0.0.1 9e05f01b91f92696612279f042463c2f2b2a7b2a
+// nfteseum-api v0.0.1 386e69bd906f592b6d66b9037e7ae393c79338ba
 // --
 // This file has been generated by https://github.com/webrpc/webrpc using gen/javascript
 // Do not edit by hand. Update your webrpc schema and re-generate.
@@ -10,7 +10,7 @@ export const WebRPCVersion = "v1"
 export const WebRPCSchemaVersion = "v0.0.1"
 
 // Schema hash generated from your RIDL schema
-export const WebRPCSchemaHash = "9e05f01b91f92696612279f042463c2f2b2a7b2a"
+export const WebRPCSchemaHash = "386e69bd906f592b6d66b9037e7ae393c79338ba"
 
 
 //
@@ -58,26 +58,1400 @@ export class Version {
   }
 }
 
+export class Post {
+  constructor(_data) {
+    this._data = {}
+    if (_data) {
+      this._data['id'] = _data['id']
+      this._data['contract'] = _data['contract']
+      this._data['tokenID'] = _data['tokenID']
+      this._data['author'] = _data['author']
+      this._data['likeCount'] = _data['likeCount']
+      this._data['commentCount'] = _data['commentCount']
+      this._data['createdAt'] = _data['createdAt']
+      this._data['metadata'] = _data['metadata']
+      
+    }
+  }
+  get id() {
+    return this._data['id']
+  }
+  set id(value) {
+    this._data['id'] = value
+  }
+  get contract() {
+    return this._data['contract']
+  }
+  set contract(value) {
+    this._data['contract'] = value
+  }
+  get tokenID() {
+    return this._data['tokenID']
+  }
+  set tokenID(value) {
+    this._data['tokenID'] = value
+  }
+  get author() {
+    return this._data['author']
+  }
+  set author(value) {
+    this._data['author'] = value
+  }
+  get likeCount() {
+    return this._data['likeCount']
+  }
+  set likeCount(value) {
+    this._data['likeCount'] = value
+  }
+  get commentCount() {
+    return this._data['commentCount']
+  }
+  set commentCount(value) {
+    this._data['commentCount'] = value
+  }
+  get createdAt() {
+    return this._data['createdAt']
+  }
+  set createdAt(value) {
+    this._data['createdAt'] = value
+  }
+  get metadata() {
+    return this._data['metadata']
+  }
+  set metadata(value) {
+    this._data['metadata'] = value
+  }
+  
+  toJSON() {
+    return this._data
+  }
+}
+
+export class TokenMetadata {
+  constructor(_data) {
+    this._data = {}
+    if (_data) {
+      this._data['name'] = _data['name']
+      this._data['description'] = _data['description']
+      this._data['image'] = _data['image']
+      
+    }
+  }
+  get name() {
+    return this._data['name']
+  }
+  set name(value) {
+    this._data['name'] = value
+  }
+  get description() {
+    return this._data['description']
+  }
+  set description(value) {
+    this._data['description'] = value
+  }
+  get image() {
+    return this._data['image']
+  }
+  set image(value) {
+    this._data['image'] = value
+  }
+  
+  toJSON() {
+    return this._data
+  }
+}
+
+export class User {
+  constructor(_data) {
+    this._data = {}
+    if (_data) {
+      this._data['addr'] = _data['addr']
+      this._data['name'] = _data['name']
+      this._data['pfp'] = _data['pfp']
+      
+    }
+  }
+  get addr() {
+    return this._data['addr']
+  }
+  set addr(value) {
+    this._data['addr'] = value
+  }
+  get name() {
+    return this._data['name']
+  }
+  set name(value) {
+    this._data['name'] = value
+  }
+  get pfp() {
+    return this._data['pfp']
+  }
+  set pfp(value) {
+    this._data['pfp'] = value
+  }
+  
+  toJSON() {
+    return this._data
+  }
+}
+
+export class Comment {
+  constructor(_data) {
+    this._data = {}
+    if (_data) {
+      this._data['id'] = _data['id']
+      this._data['postID'] = _data['postID']
+      this._data['parentID'] = _data['parentID']
+      this._data['author'] = _data['author']
+      this._data['content'] = _data['content']
+      this._data['entities'] = _data['entities']
+      this._data['createdAt'] = _data['createdAt']
+      this._data['held'] = _data['held']
+      
+    }
+  }
+  get id() {
+    return this._data['id']
+  }
+  set id(value) {
+    this._data['id'] = value
+  }
+  get postID() {
+    return this._data['postID']
+  }
+  set postID(value) {
+    this._data['postID'] = value
+  }
+  get parentID() {
+    return this._data['parentID']
+  }
+  set parentID(value) {
+    this._data['parentID'] = value
+  }
+  get author() {
+    return this._data['author']
+  }
+  set author(value) {
+    this._data['author'] = value
+  }
+  get content() {
+    return this._data['content']
+  }
+  set content(value) {
+    this._data['content'] = value
+  }
+  get entities() {
+    return this._data['entities']
+  }
+  set entities(value) {
+    this._data['entities'] = value
+  }
+  get createdAt() {
+    return this._data['createdAt']
+  }
+  set createdAt(value) {
+    this._data['createdAt'] = value
+  }
+  get held() {
+    return this._data['held']
+  }
+  set held(value) {
+    this._data['held'] = value
+  }
+  
+  toJSON() {
+    return this._data
+  }
+}
+
+export class Entity {
+  constructor(_data) {
+    this._data = {}
+    if (_data) {
+      this._data['type'] = _data['type']
+      this._data['start'] = _data['start']
+      this._data['end'] = _data['end']
+      this._data['value'] = _data['value']
+      this._data['account'] = _data['account']
+      
+    }
+  }
+  get type() {
+    return this._data['type']
+  }
+  set type(value) {
+    this._data['type'] = value
+  }
+  get start() {
+    return this._data['start']
+  }
+  set start(value) {
+    this._data['start'] = value
+  }
+  get end() {
+    return this._data['end']
+  }
+  set end(value) {
+    this._data['end'] = value
+  }
+  get value() {
+    return this._data['value']
+  }
+  set value(value) {
+    this._data['value'] = value
+  }
+  get account() {
+    return this._data['account']
+  }
+  set account(value) {
+    this._data['account'] = value
+  }
+  
+  toJSON() {
+    return this._data
+  }
+}
+
+export class SearchHit {
+  constructor(_data) {
+    this._data = {}
+    if (_data) {
+      this._data['type'] = _data['type']
+      this._data['rank'] = _data['rank']
+      this._data['user'] = _data['user']
+      this._data['post'] = _data['post']
+      this._data['comment'] = _data['comment']
+      
+    }
+  }
+  get type() {
+    return this._data['type']
+  }
+  set type(value) {
+    this._data['type'] = value
+  }
+  get rank() {
+    return this._data['rank']
+  }
+  set rank(value) {
+    this._data['rank'] = value
+  }
+  get user() {
+    return this._data['user']
+  }
+  set user(value) {
+    this._data['user'] = value
+  }
+  get post() {
+    return this._data['post']
+  }
+  set post(value) {
+    this._data['post'] = value
+  }
+  get comment() {
+    return this._data['comment']
+  }
+  set comment(value) {
+    this._data['comment'] = value
+  }
+  
+  toJSON() {
+    return this._data
+  }
+}
+
+export class Report {
+  constructor(_data) {
+    this._data = {}
+    if (_data) {
+      this._data['id'] = _data['id']
+      this._data['reporter'] = _data['reporter']
+      this._data['targetType'] = _data['targetType']
+      this._data['targetID'] = _data['targetID']
+      this._data['reason'] = _data['reason']
+      this._data['details'] = _data['details']
+      this._data['status'] = _data['status']
+      this._data['action'] = _data['action']
+      this._data['resolvedBy'] = _data['resolvedBy']
+      this._data['resolvedAt'] = _data['resolvedAt']
+      this._data['createdAt'] = _data['createdAt']
+      
+    }
+  }
+  get id() {
+    return this._data['id']
+  }
+  set id(value) {
+    this._data['id'] = value
+  }
+  get reporter() {
+    return this._data['reporter']
+  }
+  set reporter(value) {
+    this._data['reporter'] = value
+  }
+  get targetType() {
+    return this._data['targetType']
+  }
+  set targetType(value) {
+    this._data['targetType'] = value
+  }
+  get targetID() {
+    return this._data['targetID']
+  }
+  set targetID(value) {
+    this._data['targetID'] = value
+  }
+  get reason() {
+    return this._data['reason']
+  }
+  set reason(value) {
+    this._data['reason'] = value
+  }
+  get details() {
+    return this._data['details']
+  }
+  set details(value) {
+    this._data['details'] = value
+  }
+  get status() {
+    return this._data['status']
+  }
+  set status(value) {
+    this._data['status'] = value
+  }
+  get action() {
+    return this._data['action']
+  }
+  set action(value) {
+    this._data['action'] = value
+  }
+  get resolvedBy() {
+    return this._data['resolvedBy']
+  }
+  set resolvedBy(value) {
+    this._data['resolvedBy'] = value
+  }
+  get resolvedAt() {
+    return this._data['resolvedAt']
+  }
+  set resolvedAt(value) {
+    this._data['resolvedAt'] = value
+  }
+  get createdAt() {
+    return this._data['createdAt']
+  }
+  set createdAt(value) {
+    this._data['createdAt'] = value
+  }
+  
+  toJSON() {
+    return this._data
+  }
+}
+
+export class HeldComment {
+  constructor(_data) {
+    this._data = {}
+    if (_data) {
+      this._data['comment'] = _data['comment']
+      this._data['score'] = _data['score']
+      this._data['reasons'] = _data['reasons']
+      this._data['authorReputation'] = _data['authorReputation']
+      this._data['heldAt'] = _data['heldAt']
+      
+    }
+  }
+  get comment() {
+    return this._data['comment']
+  }
+  set comment(value) {
+    this._data['comment'] = value
+  }
+  get score() {
+    return this._data['score']
+  }
+  set score(value) {
+    this._data['score'] = value
+  }
+  get reasons() {
+    return this._data['reasons']
+  }
+  set reasons(value) {
+    this._data['reasons'] = value
+  }
+  get authorReputation() {
+    return this._data['authorReputation']
+  }
+  set authorReputation(value) {
+    this._data['authorReputation'] = value
+  }
+  get heldAt() {
+    return this._data['heldAt']
+  }
+  set heldAt(value) {
+    this._data['heldAt'] = value
+  }
+  
+  toJSON() {
+    return this._data
+  }
+}
+
+export class BlocklistEntry {
+  constructor(_data) {
+    this._data = {}
+    if (_data) {
+      this._data['id'] = _data['id']
+      this._data['kind'] = _data['kind']
+      this._data['value'] = _data['value']
+      this._data['createdBy'] = _data['createdBy']
+      this._data['createdAt'] = _data['createdAt']
+      
+    }
+  }
+  get id() {
+    return this._data['id']
+  }
+  set id(value) {
+    this._data['id'] = value
+  }
+  get kind() {
+    return this._data['kind']
+  }
+  set kind(value) {
+    this._data['kind'] = value
+  }
+  get value() {
+    return this._data['value']
+  }
+  set value(value) {
+    this._data['value'] = value
+  }
+  get createdBy() {
+    return this._data['createdBy']
+  }
+  set createdBy(value) {
+    this._data['createdBy'] = value
+  }
+  get createdAt() {
+    return this._data['createdAt']
+  }
+  set createdAt(value) {
+    this._data['createdAt'] = value
+  }
+  
+  toJSON() {
+    return this._data
+  }
+}
+
+export class AuditEntry {
+  constructor(_data) {
+    this._data = {}
+    if (_data) {
+      this._data['id'] = _data['id']
+      this._data['actor'] = _data['actor']
+      this._data['action'] = _data['action']
+      this._data['targetType'] = _data['targetType']
+      this._data['targetID'] = _data['targetID']
+      this._data['before'] = _data['before']
+      this._data['after'] = _data['after']
+      this._data['requestID'] = _data['requestID']
+      this._data['ip'] = _data['ip']
+      this._data['createdAt'] = _data['createdAt']
+      this._data['prevHash'] = _data['prevHash']
+      this._data['hash'] = _data['hash']
+      
+    }
+  }
+  get id() {
+    return this._data['id']
+  }
+  set id(value) {
+    this._data['id'] = value
+  }
+  get actor() {
+    return this._data['actor']
+  }
+  set actor(value) {
+    this._data['actor'] = value
+  }
+  get action() {
+    return this._data['action']
+  }
+  set action(value) {
+    this._data['action'] = value
+  }
+  get targetType() {
+    return this._data['targetType']
+  }
+  set targetType(value) {
+    this._data['targetType'] = value
+  }
+  get targetID() {
+    return this._data['targetID']
+  }
+  set targetID(value) {
+    this._data['targetID'] = value
+  }
+  get before() {
+    return this._data['before']
+  }
+  set before(value) {
+    this._data['before'] = value
+  }
+  get after() {
+    return this._data['after']
+  }
+  set after(value) {
+    this._data['after'] = value
+  }
+  get requestID() {
+    return this._data['requestID']
+  }
+  set requestID(value) {
+    this._data['requestID'] = value
+  }
+  get ip() {
+    return this._data['ip']
+  }
+  set ip(value) {
+    this._data['ip'] = value
+  }
+  get createdAt() {
+    return this._data['createdAt']
+  }
+  set createdAt(value) {
+    this._data['createdAt'] = value
+  }
+  get prevHash() {
+    return this._data['prevHash']
+  }
+  set prevHash(value) {
+    this._data['prevHash'] = value
+  }
+  get hash() {
+    return this._data['hash']
+  }
+  set hash(value) {
+    this._data['hash'] = value
+  }
+  
+  toJSON() {
+    return this._data
+  }
+}
+
+export class Notification {
+  constructor(_data) {
+    this._data = {}
+    if (_data) {
+      this._data['id'] = _data['id']
+      this._data['kind'] = _data['kind']
+      this._data['postID'] = _data['postID']
+      this._data['commentID'] = _data['commentID']
+      this._data['actors'] = _data['actors']
+      this._data['actorCount'] = _data['actorCount']
+      this._data['read'] = _data['read']
+      this._data['createdAt'] = _data['createdAt']
+      this._data['updatedAt'] = _data['updatedAt']
+      
+    }
+  }
+  get id() {
+    return this._data['id']
+  }
+  set id(value) {
+    this._data['id'] = value
+  }
+  get kind() {
+    return this._data['kind']
+  }
+  set kind(value) {
+    this._data['kind'] = value
+  }
+  get postID() {
+    return this._data['postID']
+  }
+  set postID(value) {
+    this._data['postID'] = value
+  }
+  get commentID() {
+    return this._data['commentID']
+  }
+  set commentID(value) {
+    this._data['commentID'] = value
+  }
+  get actors() {
+    return this._data['actors']
+  }
+  set actors(value) {
+    this._data['actors'] = value
+  }
+  get actorCount() {
+    return this._data['actorCount']
+  }
+  set actorCount(value) {
+    this._data['actorCount'] = value
+  }
+  get read() {
+    return this._data['read']
+  }
+  set read(value) {
+    this._data['read'] = value
+  }
+  get createdAt() {
+    return this._data['createdAt']
+  }
+  set createdAt(value) {
+    this._data['createdAt'] = value
+  }
+  get updatedAt() {
+    return this._data['updatedAt']
+  }
+  set updatedAt(value) {
+    this._data['updatedAt'] = value
+  }
+  
+  toJSON() {
+    return this._data
+  }
+}
+
+export class EmailSettings {
+  constructor(_data) {
+    this._data = {}
+    if (_data) {
+      this._data['email'] = _data['email']
+      this._data['verified'] = _data['verified']
+      this._data['digest'] = _data['digest']
+      
+    }
+  }
+  get email() {
+    return this._data['email']
+  }
+  set email(value) {
+    this._data['email'] = value
+  }
+  get verified() {
+    return this._data['verified']
+  }
+  set verified(value) {
+    this._data['verified'] = value
+  }
+  get digest() {
+    return this._data['digest']
+  }
+  set digest(value) {
+    this._data['digest'] = value
+  }
+  
+  toJSON() {
+    return this._data
+  }
+}
+
+export class PushSubscription {
+  constructor(_data) {
+    this._data = {}
+    if (_data) {
+      this._data['endpoint'] = _data['endpoint']
+      this._data['p256dh'] = _data['p256dh']
+      this._data['auth'] = _data['auth']
+      
+    }
+  }
+  get endpoint() {
+    return this._data['endpoint']
+  }
+  set endpoint(value) {
+    this._data['endpoint'] = value
+  }
+  get p256dh() {
+    return this._data['p256dh']
+  }
+  set p256dh(value) {
+    this._data['p256dh'] = value
+  }
+  get auth() {
+    return this._data['auth']
+  }
+  set auth(value) {
+    this._data['auth'] = value
+  }
+  
+  toJSON() {
+    return this._data
+  }
+}
+
+export class Webhook {
+  constructor(_data) {
+    this._data = {}
+    if (_data) {
+      this._data['id'] = _data['id']
+      this._data['contract'] = _data['contract']
+      this._data['url'] = _data['url']
+      this._data['eventTypes'] = _data['eventTypes']
+      this._data['enabled'] = _data['enabled']
+      this._data['failureCount'] = _data['failureCount']
+      this._data['disabledAt'] = _data['disabledAt']
+      this._data['createdAt'] = _data['createdAt']
+      this._data['updatedAt'] = _data['updatedAt']
+      
+    }
+  }
+  get id() {
+    return this._data['id']
+  }
+  set id(value) {
+    this._data['id'] = value
+  }
+  get contract() {
+    return this._data['contract']
+  }
+  set contract(value) {
+    this._data['contract'] = value
+  }
+  get url() {
+    return this._data['url']
+  }
+  set url(value) {
+    this._data['url'] = value
+  }
+  get eventTypes() {
+    return this._data['eventTypes']
+  }
+  set eventTypes(value) {
+    this._data['eventTypes'] = value
+  }
+  get enabled() {
+    return this._data['enabled']
+  }
+  set enabled(value) {
+    this._data['enabled'] = value
+  }
+  get failureCount() {
+    return this._data['failureCount']
+  }
+  set failureCount(value) {
+    this._data['failureCount'] = value
+  }
+  get disabledAt() {
+    return this._data['disabledAt']
+  }
+  set disabledAt(value) {
+    this._data['disabledAt'] = value
+  }
+  get createdAt() {
+    return this._data['createdAt']
+  }
+  set createdAt(value) {
+    this._data['createdAt'] = value
+  }
+  get updatedAt() {
+    return this._data['updatedAt']
+  }
+  set updatedAt(value) {
+    this._data['updatedAt'] = value
+  }
+  
+  toJSON() {
+    return this._data
+  }
+}
+
+export class WebhookDelivery {
+  constructor(_data) {
+    this._data = {}
+    if (_data) {
+      this._data['id'] = _data['id']
+      this._data['deliveryID'] = _data['deliveryID']
+      this._data['eventType'] = _data['eventType']
+      this._data['payload'] = _data['payload']
+      this._data['statusCode'] = _data['statusCode']
+      this._data['error'] = _data['error']
+      this._data['response'] = _data['response']
+      this._data['durationMs'] = _data['durationMs']
+      this._data['success'] = _data['success']
+      this._data['createdAt'] = _data['createdAt']
+      
+    }
+  }
+  get id() {
+    return this._data['id']
+  }
+  set id(value) {
+    this._data['id'] = value
+  }
+  get deliveryID() {
+    return this._data['deliveryID']
+  }
+  set deliveryID(value) {
+    this._data['deliveryID'] = value
+  }
+  get eventType() {
+    return this._data['eventType']
+  }
+  set eventType(value) {
+    this._data['eventType'] = value
+  }
+  get payload() {
+    return this._data['payload']
+  }
+  set payload(value) {
+    this._data['payload'] = value
+  }
+  get statusCode() {
+    return this._data['statusCode']
+  }
+  set statusCode(value) {
+    this._data['statusCode'] = value
+  }
+  get error() {
+    return this._data['error']
+  }
+  set error(value) {
+    this._data['error'] = value
+  }
+  get response() {
+    return this._data['response']
+  }
+  set response(value) {
+    this._data['response'] = value
+  }
+  get durationMs() {
+    return this._data['durationMs']
+  }
+  set durationMs(value) {
+    this._data['durationMs'] = value
+  }
+  get success() {
+    return this._data['success']
+  }
+  set success(value) {
+    this._data['success'] = value
+  }
+  get createdAt() {
+    return this._data['createdAt']
+  }
+  set createdAt(value) {
+    this._data['createdAt'] = value
+  }
+  
+  toJSON() {
+    return this._data
+  }
+}
+
+export class NotificationPreferences {
+  constructor(_data) {
+    this._data = {}
+    if (_data) {
+      this._data['likes'] = _data['likes']
+      this._data['comments'] = _data['comments']
+      this._data['replies'] = _data['replies']
+      this._data['follows'] = _data['follows']
+      this._data['mentions'] = _data['mentions']
+      
+    }
+  }
+  get likes() {
+    return this._data['likes']
+  }
+  set likes(value) {
+    this._data['likes'] = value
+  }
+  get comments() {
+    return this._data['comments']
+  }
+  set comments(value) {
+    this._data['comments'] = value
+  }
+  get replies() {
+    return this._data['replies']
+  }
+  set replies(value) {
+    this._data['replies'] = value
+  }
+  get follows() {
+    return this._data['follows']
+  }
+  set follows(value) {
+    this._data['follows'] = value
+  }
+  get mentions() {
+    return this._data['mentions']
+  }
+  set mentions(value) {
+    this._data['mentions'] = value
+  }
+  
+  toJSON() {
+    return this._data
+  }
+}
+
+  
+//
+// Client
+//
+
+export class API {
+  constructor(hostname, fetch) {
+    this.path = '/rpc/API/'
+    this.hostname = hostname
+    this.fetch = fetch
+  }
+
+  url(name) {
+    return this.hostname + this.path + name
+  }
+  
+  ping = (headers) => {
+    return this.fetch(
+      this.url('Ping'),
+      createHTTPRequest({}, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          status: (_data.status)
+        }
+      })
+    })
+  }
+  
+  version = (headers) => {
+    return this.fetch(
+      this.url('Version'),
+      createHTTPRequest({}, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          version: new Version(_data.version)
+        }
+      })
+    })
+  }
+  
+  addComment = (args, headers) => {
+    return this.fetch(
+      this.url('AddComment'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          comment: new Comment(_data.comment)
+        }
+      })
+    })
+  }
+  
+  listComments = (args, headers) => {
+    return this.fetch(
+      this.url('ListComments'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          comments: (_data.comments), 
+          nextCursor: (_data.nextCursor)
+        }
+      })
+    })
+  }
+  
+  listPostsByHashtag = (args, headers) => {
+    return this.fetch(
+      this.url('ListPostsByHashtag'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          posts: (_data.posts), 
+          nextCursor: (_data.nextCursor)
+        }
+      })
+    })
+  }
+  
+  likePost = (args, headers) => {
+    return this.fetch(
+      this.url('LikePost'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          liked: (_data.liked)
+        }
+      })
+    })
+  }
+  
+  unlikePost = (args, headers) => {
+    return this.fetch(
+      this.url('UnlikePost'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          unliked: (_data.unliked)
+        }
+      })
+    })
+  }
+  
+  followUser = (args, headers) => {
+    return this.fetch(
+      this.url('FollowUser'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          followed: (_data.followed)
+        }
+      })
+    })
+  }
+  
+  unfollowUser = (args, headers) => {
+    return this.fetch(
+      this.url('UnfollowUser'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          unfollowed: (_data.unfollowed)
+        }
+      })
+    })
+  }
+  
+  blockUser = (args, headers) => {
+    return this.fetch(
+      this.url('BlockUser'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          blocked: (_data.blocked)
+        }
+      })
+    })
+  }
+  
+  unblockUser = (args, headers) => {
+    return this.fetch(
+      this.url('UnblockUser'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          unblocked: (_data.unblocked)
+        }
+      })
+    })
+  }
+  
+  muteUser = (args, headers) => {
+    return this.fetch(
+      this.url('MuteUser'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          muted: (_data.muted)
+        }
+      })
+    })
+  }
+  
+  unmuteUser = (args, headers) => {
+    return this.fetch(
+      this.url('UnmuteUser'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          unmuted: (_data.unmuted)
+        }
+      })
+    })
+  }
   
-//
-// Client
-//
-
-export class API {
-  constructor(hostname, fetch) {
-    this.path = '/rpc/API/'
-    this.hostname = hostname
-    this.fetch = fetch
+  listBlockedUsers = (args, headers) => {
+    return this.fetch(
+      this.url('ListBlockedUsers'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          accounts: (_data.accounts), 
+          nextCursor: (_data.nextCursor)
+        }
+      })
+    })
   }
-
-  url(name) {
-    return this.hostname + this.path + name
+  
+  search = (args, headers) => {
+    return this.fetch(
+      this.url('Search'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          hits: (_data.hits), 
+          nextCursor: (_data.nextCursor)
+        }
+      })
+    })
   }
   
-  ping = (headers) => {
+  reportContent = (args, headers) => {
     return this.fetch(
-      this.url('Ping'),
+      this.url('ReportContent'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          report: new Report(_data.report)
+        }
+      })
+    })
+  }
+  
+  listReports = (args, headers) => {
+    return this.fetch(
+      this.url('ListReports'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          reports: (_data.reports), 
+          nextCursor: (_data.nextCursor)
+        }
+      })
+    })
+  }
+  
+  moderateContent = (args, headers) => {
+    return this.fetch(
+      this.url('ModerateContent'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          resolvedReports: (_data.resolvedReports)
+        }
+      })
+    })
+  }
+  
+  listHeldComments = (args, headers) => {
+    return this.fetch(
+      this.url('ListHeldComments'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          comments: (_data.comments), 
+          nextCursor: (_data.nextCursor)
+        }
+      })
+    })
+  }
+  
+  listBlocklist = (headers) => {
+    return this.fetch(
+      this.url('ListBlocklist'),
+      createHTTPRequest({}, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          entries: (_data.entries)
+        }
+      })
+    })
+  }
+  
+  addBlocklistEntry = (args, headers) => {
+    return this.fetch(
+      this.url('AddBlocklistEntry'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          entry: new BlocklistEntry(_data.entry)
+        }
+      })
+    })
+  }
+  
+  removeBlocklistEntry = (args, headers) => {
+    return this.fetch(
+      this.url('RemoveBlocklistEntry'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          removed: (_data.removed)
+        }
+      })
+    })
+  }
+  
+  listAuditLog = (args, headers) => {
+    return this.fetch(
+      this.url('ListAuditLog'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          entries: (_data.entries), 
+          nextCursor: (_data.nextCursor)
+        }
+      })
+    })
+  }
+  
+  verifyAuditLog = (headers) => {
+    return this.fetch(
+      this.url('VerifyAuditLog'),
+      createHTTPRequest({}, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          valid: (_data.valid), 
+          brokenAt: (_data.brokenAt)
+        }
+      })
+    })
+  }
+  
+  listNotifications = (args, headers) => {
+    return this.fetch(
+      this.url('ListNotifications'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          notifications: (_data.notifications), 
+          nextCursor: (_data.nextCursor)
+        }
+      })
+    })
+  }
+  
+  markNotificationsRead = (args, headers) => {
+    return this.fetch(
+      this.url('MarkNotificationsRead'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          count: (_data.count)
+        }
+      })
+    })
+  }
+  
+  getUnreadCount = (headers) => {
+    return this.fetch(
+      this.url('GetUnreadCount'),
+      createHTTPRequest({}, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          count: (_data.count)
+        }
+      })
+    })
+  }
+  
+  getNotificationPreferences = (headers) => {
+    return this.fetch(
+      this.url('GetNotificationPreferences'),
+      createHTTPRequest({}, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          preferences: new NotificationPreferences(_data.preferences)
+        }
+      })
+    })
+  }
+  
+  updateNotificationPreferences = (args, headers) => {
+    return this.fetch(
+      this.url('UpdateNotificationPreferences'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          preferences: new NotificationPreferences(_data.preferences)
+        }
+      })
+    })
+  }
+  
+  getEmailSettings = (headers) => {
+    return this.fetch(
+      this.url('GetEmailSettings'),
+      createHTTPRequest({}, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          settings: new EmailSettings(_data.settings)
+        }
+      })
+    })
+  }
+  
+  setEmail = (args, headers) => {
+    return this.fetch(
+      this.url('SetEmail'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          settings: new EmailSettings(_data.settings)
+        }
+      })
+    })
+  }
+  
+  setDigest = (args, headers) => {
+    return this.fetch(
+      this.url('SetDigest'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          settings: new EmailSettings(_data.settings)
+        }
+      })
+    })
+  }
+  
+  getPushPublicKey = (headers) => {
+    return this.fetch(
+      this.url('GetPushPublicKey'),
       createHTTPRequest({}, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          key: (_data.key)
+        }
+      })
+    })
+  }
+  
+  registerPushSubscription = (args, headers) => {
+    return this.fetch(
+      this.url('RegisterPushSubscription'),
+      createHTTPRequest(args, headers)
     ).then((res) => {
       return buildResponse(res).then(_data => {
         return {
@@ -87,14 +1461,94 @@ export class API {
     })
   }
   
-  version = (headers) => {
+  removePushSubscription = (args, headers) => {
     return this.fetch(
-      this.url('Version'),
+      this.url('RemovePushSubscription'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          removed: (_data.removed)
+        }
+      })
+    })
+  }
+  
+  createWebhook = (args, headers) => {
+    return this.fetch(
+      this.url('CreateWebhook'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          webhook: new Webhook(_data.webhook), 
+          secret: (_data.secret)
+        }
+      })
+    })
+  }
+  
+  listWebhooks = (headers) => {
+    return this.fetch(
+      this.url('ListWebhooks'),
       createHTTPRequest({}, headers)
     ).then((res) => {
       return buildResponse(res).then(_data => {
         return {
-          version: new Version(_data.version)
+          webhooks: (_data.webhooks)
+        }
+      })
+    })
+  }
+  
+  updateWebhook = (args, headers) => {
+    return this.fetch(
+      this.url('UpdateWebhook'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          webhook: new Webhook(_data.webhook)
+        }
+      })
+    })
+  }
+  
+  deleteWebhook = (args, headers) => {
+    return this.fetch(
+      this.url('DeleteWebhook'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          deleted: (_data.deleted)
+        }
+      })
+    })
+  }
+  
+  listWebhookDeliveries = (args, headers) => {
+    return this.fetch(
+      this.url('ListWebhookDeliveries'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          deliveries: (_data.deliveries), 
+          nextCursor: (_data.nextCursor)
+        }
+      })
+    })
+  }
+  
+  sendTestWebhook = (args, headers) => {
+    return this.fetch(
+      this.url('SendTestWebhook'),
+      createHTTPRequest(args, headers)
+    ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          delivery: new WebhookDelivery(_data.delivery)
         }
       })
     })
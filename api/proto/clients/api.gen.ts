@@ -1,5 +1,5 @@
 /* eslint-disable */
-// nfteseum-api v0.0.1 9e05f01b91f92696612279f042463c2f2b2a7b2a
+// nfteseum-api v0.0.1 386e69bd906f592b6d66b9037e7ae393c79338ba
 // --
 // This file has been generated by https://github.com/webrpc/webrpc using gen/typescript
 // Do not edit by hand. Update your webrpc schema and re-generate.
@@ -11,7 +11,7 @@ export const WebRPCVersion = "v1"
 export const WebRPCSchemaVersion = "v0.0.1"
 
 // Schema hash generated from your RIDL schema
-export const WebRPCSchemaHash = "9e05f01b91f92696612279f042463c2f2b2a7b2a"
+export const WebRPCSchemaHash = "386e69bd906f592b6d66b9037e7ae393c79338ba"
 
 
 //
@@ -24,9 +24,200 @@ export interface Version {
   appVersion: string
 }
 
+export interface Post {
+  id: number
+  contract: string
+  tokenID: number
+  author: string
+  likeCount: number
+  commentCount: number
+  createdAt: string
+  metadata?: TokenMetadata
+}
+
+export interface TokenMetadata {
+  name?: string
+  description?: string
+  image?: string
+}
+
+export interface User {
+  addr: string
+  name: string
+  pfp?: string
+}
+
+export interface Comment {
+  id: number
+  postID: number
+  parentID?: number
+  author: string
+  content: string
+  entities: Array<Entity>
+  createdAt: string
+  held: boolean
+}
+
+export interface Entity {
+  type: string
+  start: number
+  end: number
+  value: string
+  account?: string
+}
+
+export interface SearchHit {
+  type: string
+  rank: number
+  user?: User
+  post?: Post
+  comment?: Comment
+}
+
+export interface Report {
+  id: number
+  reporter: string
+  targetType: string
+  targetID: string
+  reason: string
+  details?: string
+  status: string
+  action?: string
+  resolvedBy?: string
+  resolvedAt?: string
+  createdAt: string
+}
+
+export interface HeldComment {
+  comment: Comment
+  score: number
+  reasons: Array<string>
+  authorReputation: number
+  heldAt: string
+}
+
+export interface BlocklistEntry {
+  id: number
+  kind: string
+  value: string
+  createdBy: string
+  createdAt: string
+}
+
+export interface AuditEntry {
+  id: number
+  actor: string
+  action: string
+  targetType: string
+  targetID: string
+  before?: string
+  after?: string
+  requestID: string
+  ip: string
+  createdAt: string
+  prevHash: string
+  hash: string
+}
+
+export interface Notification {
+  id: number
+  kind: string
+  postID?: number
+  commentID?: number
+  actors: Array<string>
+  actorCount: number
+  read: boolean
+  createdAt: string
+  updatedAt: string
+}
+
+export interface EmailSettings {
+  email?: string
+  verified: boolean
+  digest: string
+}
+
+export interface PushSubscription {
+  endpoint: string
+  p256dh: string
+  auth: string
+}
+
+export interface Webhook {
+  id: number
+  contract: string
+  url: string
+  eventTypes: Array<string>
+  enabled: boolean
+  failureCount: number
+  disabledAt?: string
+  createdAt: string
+  updatedAt: string
+}
+
+export interface WebhookDelivery {
+  id: number
+  deliveryID: string
+  eventType: string
+  payload: string
+  statusCode?: number
+  error?: string
+  response?: string
+  durationMs: number
+  success: boolean
+  createdAt: string
+}
+
+export interface NotificationPreferences {
+  likes: boolean
+  comments: boolean
+  replies: boolean
+  follows: boolean
+  mentions: boolean
+}
+
 export interface API {
   ping(headers?: object): Promise<PingReturn>
   version(headers?: object): Promise<VersionReturn>
+  addComment(args: AddCommentArgs, headers?: object): Promise<AddCommentReturn>
+  listComments(args: ListCommentsArgs, headers?: object): Promise<ListCommentsReturn>
+  listPostsByHashtag(args: ListPostsByHashtagArgs, headers?: object): Promise<ListPostsByHashtagReturn>
+  likePost(args: LikePostArgs, headers?: object): Promise<LikePostReturn>
+  unlikePost(args: UnlikePostArgs, headers?: object): Promise<UnlikePostReturn>
+  followUser(args: FollowUserArgs, headers?: object): Promise<FollowUserReturn>
+  unfollowUser(args: UnfollowUserArgs, headers?: object): Promise<UnfollowUserReturn>
+  blockUser(args: BlockUserArgs, headers?: object): Promise<BlockUserReturn>
+  unblockUser(args: UnblockUserArgs, headers?: object): Promise<UnblockUserReturn>
+  muteUser(args: MuteUserArgs, headers?: object): Promise<MuteUserReturn>
+  unmuteUser(args: UnmuteUserArgs, headers?: object): Promise<UnmuteUserReturn>
+  listBlockedUsers(args: ListBlockedUsersArgs, headers?: object): Promise<ListBlockedUsersReturn>
+  search(args: SearchArgs, headers?: object): Promise<SearchReturn>
+  reportContent(args: ReportContentArgs, headers?: object): Promise<ReportContentReturn>
+  listReports(args: ListReportsArgs, headers?: object): Promise<ListReportsReturn>
+  moderateContent(args: ModerateContentArgs, headers?: object): Promise<ModerateContentReturn>
+  listHeldComments(args: ListHeldCommentsArgs, headers?: object): Promise<ListHeldCommentsReturn>
+  listBlocklist(headers?: object): Promise<ListBlocklistReturn>
+  addBlocklistEntry(args: AddBlocklistEntryArgs, headers?: object): Promise<AddBlocklistEntryReturn>
+  removeBlocklistEntry(args: RemoveBlocklistEntryArgs, headers?: object): Promise<RemoveBlocklistEntryReturn>
+  listAuditLog(args: ListAuditLogArgs, headers?: object): Promise<ListAuditLogReturn>
+  verifyAuditLog(headers?: object): Promise<VerifyAuditLogReturn>
+  listNotifications(args: ListNotificationsArgs, headers?: object): Promise<ListNotificationsReturn>
+  markNotificationsRead(args: MarkNotificationsReadArgs, headers?: object): Promise<MarkNotificationsReadReturn>
+  getUnreadCount(headers?: object): Promise<GetUnreadCountReturn>
+  getNotificationPreferences(headers?: object): Promise<GetNotificationPreferencesReturn>
+  updateNotificationPreferences(args: UpdateNotificationPreferencesArgs, headers?: object): Promise<UpdateNotificationPreferencesReturn>
+  getEmailSettings(headers?: object): Promise<GetEmailSettingsReturn>
+  setEmail(args: SetEmailArgs, headers?: object): Promise<SetEmailReturn>
+  setDigest(args: SetDigestArgs, headers?: object): Promise<SetDigestReturn>
+  getPushPublicKey(headers?: object): Promise<GetPushPublicKeyReturn>
+  registerPushSubscription(args: RegisterPushSubscriptionArgs, headers?: object): Promise<RegisterPushSubscriptionReturn>
+  removePushSubscription(args: RemovePushSubscriptionArgs, headers?: object): Promise<RemovePushSubscriptionReturn>
+  createWebhook(args: CreateWebhookArgs, headers?: object): Promise<CreateWebhookReturn>
+  listWebhooks(headers?: object): Promise<ListWebhooksReturn>
+  updateWebhook(args: UpdateWebhookArgs, headers?: object): Promise<UpdateWebhookReturn>
+  deleteWebhook(args: DeleteWebhookArgs, headers?: object): Promise<DeleteWebhookReturn>
+  listWebhookDeliveries(args: ListWebhookDeliveriesArgs, headers?: object): Promise<ListWebhookDeliveriesReturn>
+  sendTestWebhook(args: SendTestWebhookArgs, headers?: object): Promise<SendTestWebhookReturn>
 }
 
 export interface PingArgs {
@@ -41,6 +232,321 @@ export interface VersionArgs {
 export interface VersionReturn {
   version: Version  
 }
+export interface AddCommentArgs {
+  postID: number
+  content: string
+  parentID?: number
+}
+
+export interface AddCommentReturn {
+  comment: Comment  
+}
+export interface ListCommentsArgs {
+  postID: number
+  cursor?: string
+  limit?: number
+}
+
+export interface ListCommentsReturn {
+  comments: Array<Comment>
+  nextCursor: string  
+}
+export interface ListPostsByHashtagArgs {
+  tag: string
+  cursor?: string
+  limit?: number
+}
+
+export interface ListPostsByHashtagReturn {
+  posts: Array<Post>
+  nextCursor: string  
+}
+export interface LikePostArgs {
+  postID: number
+}
+
+export interface LikePostReturn {
+  liked: boolean  
+}
+export interface UnlikePostArgs {
+  postID: number
+}
+
+export interface UnlikePostReturn {
+  unliked: boolean  
+}
+export interface FollowUserArgs {
+  account: string
+}
+
+export interface FollowUserReturn {
+  followed: boolean  
+}
+export interface UnfollowUserArgs {
+  account: string
+}
+
+export interface UnfollowUserReturn {
+  unfollowed: boolean  
+}
+export interface BlockUserArgs {
+  account: string
+}
+
+export interface BlockUserReturn {
+  blocked: boolean  
+}
+export interface UnblockUserArgs {
+  account: string
+}
+
+export interface UnblockUserReturn {
+  unblocked: boolean  
+}
+export interface MuteUserArgs {
+  account: string
+}
+
+export interface MuteUserReturn {
+  muted: boolean  
+}
+export interface UnmuteUserArgs {
+  account: string
+}
+
+export interface UnmuteUserReturn {
+  unmuted: boolean  
+}
+export interface ListBlockedUsersArgs {
+  kind: string
+  cursor?: string
+  limit?: number
+}
+
+export interface ListBlockedUsersReturn {
+  accounts: Array<string>
+  nextCursor: string  
+}
+export interface SearchArgs {
+  query: string
+  types?: Array<string>
+  cursor?: string
+  limit?: number
+}
+
+export interface SearchReturn {
+  hits: Array<SearchHit>
+  nextCursor: string  
+}
+export interface ReportContentArgs {
+  targetType: string
+  targetID: string
+  reason: string
+  details?: string
+}
+
+export interface ReportContentReturn {
+  report: Report  
+}
+export interface ListReportsArgs {
+  status?: string
+  targetType?: string
+  targetID?: string
+  reason?: string
+  cursor?: string
+  limit?: number
+}
+
+export interface ListReportsReturn {
+  reports: Array<Report>
+  nextCursor: string  
+}
+export interface ModerateContentArgs {
+  targetType: string
+  targetID: string
+  action: string
+  suspendedUntil?: string
+}
+
+export interface ModerateContentReturn {
+  resolvedReports: number  
+}
+export interface ListHeldCommentsArgs {
+  cursor?: string
+  limit?: number
+}
+
+export interface ListHeldCommentsReturn {
+  comments: Array<HeldComment>
+  nextCursor: string  
+}
+export interface ListBlocklistArgs {
+}
+
+export interface ListBlocklistReturn {
+  entries: Array<BlocklistEntry>  
+}
+export interface AddBlocklistEntryArgs {
+  kind: string
+  value: string
+}
+
+export interface AddBlocklistEntryReturn {
+  entry: BlocklistEntry  
+}
+export interface RemoveBlocklistEntryArgs {
+  id: number
+}
+
+export interface RemoveBlocklistEntryReturn {
+  removed: boolean  
+}
+export interface ListAuditLogArgs {
+  actor?: string
+  action?: string
+  targetType?: string
+  targetID?: string
+  cursor?: string
+  limit?: number
+}
+
+export interface ListAuditLogReturn {
+  entries: Array<AuditEntry>
+  nextCursor: string  
+}
+export interface VerifyAuditLogArgs {
+}
+
+export interface VerifyAuditLogReturn {
+  valid: boolean
+  brokenAt: number  
+}
+export interface ListNotificationsArgs {
+  cursor?: string
+  limit?: number
+}
+
+export interface ListNotificationsReturn {
+  notifications: Array<Notification>
+  nextCursor: string  
+}
+export interface MarkNotificationsReadArgs {
+  ids: Array<number>
+  all: boolean
+}
+
+export interface MarkNotificationsReadReturn {
+  count: number  
+}
+export interface GetUnreadCountArgs {
+}
+
+export interface GetUnreadCountReturn {
+  count: number  
+}
+export interface GetNotificationPreferencesArgs {
+}
+
+export interface GetNotificationPreferencesReturn {
+  preferences: NotificationPreferences  
+}
+export interface UpdateNotificationPreferencesArgs {
+  preferences: NotificationPreferences
+}
+
+export interface UpdateNotificationPreferencesReturn {
+  preferences: NotificationPreferences  
+}
+export interface GetEmailSettingsArgs {
+}
+
+export interface GetEmailSettingsReturn {
+  settings: EmailSettings  
+}
+export interface SetEmailArgs {
+  email: string
+}
+
+export interface SetEmailReturn {
+  settings: EmailSettings  
+}
+export interface SetDigestArgs {
+  frequency: string
+}
+
+export interface SetDigestReturn {
+  settings: EmailSettings  
+}
+export interface GetPushPublicKeyArgs {
+}
+
+export interface GetPushPublicKeyReturn {
+  key: string  
+}
+export interface RegisterPushSubscriptionArgs {
+  subscription: PushSubscription
+}
+
+export interface RegisterPushSubscriptionReturn {
+  status: boolean  
+}
+export interface RemovePushSubscriptionArgs {
+  endpoint: string
+}
+
+export interface RemovePushSubscriptionReturn {
+  removed: boolean  
+}
+export interface CreateWebhookArgs {
+  contract: string
+  url: string
+  eventTypes: Array<string>
+}
+
+export interface CreateWebhookReturn {
+  webhook: Webhook
+  secret: string  
+}
+export interface ListWebhooksArgs {
+}
+
+export interface ListWebhooksReturn {
+  webhooks: Array<Webhook>  
+}
+export interface UpdateWebhookArgs {
+  id: number
+  url?: string
+  eventTypes?: Array<string>
+  enabled?: boolean
+}
+
+export interface UpdateWebhookReturn {
+  webhook: Webhook  
+}
+export interface DeleteWebhookArgs {
+  id: number
+}
+
+export interface DeleteWebhookReturn {
+  deleted: boolean  
+}
+export interface ListWebhookDeliveriesArgs {
+  id: number
+  cursor?: string
+  limit?: number
+}
+
+export interface ListWebhookDeliveriesReturn {
+  deliveries: Array<WebhookDelivery>
+  nextCursor: string  
+}
+export interface SendTestWebhookArgs {
+  id: number
+}
+
+export interface SendTestWebhookReturn {
+  delivery: WebhookDelivery  
+}
 
 
   
@@ -87,6 +593,492 @@ export class API implements API {
     })
   }
   
+  addComment = (args: AddCommentArgs, headers?: object): Promise<AddCommentReturn> => {
+    return this.fetch(
+      this.url('AddComment'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          comment: <Comment>(_data.comment)
+        }
+      })
+    })
+  }
+  
+  listComments = (args: ListCommentsArgs, headers?: object): Promise<ListCommentsReturn> => {
+    return this.fetch(
+      this.url('ListComments'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          comments: <Array<Comment>>(_data.comments), 
+          nextCursor: <string>(_data.nextCursor)
+        }
+      })
+    })
+  }
+  
+  listPostsByHashtag = (args: ListPostsByHashtagArgs, headers?: object): Promise<ListPostsByHashtagReturn> => {
+    return this.fetch(
+      this.url('ListPostsByHashtag'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          posts: <Array<Post>>(_data.posts), 
+          nextCursor: <string>(_data.nextCursor)
+        }
+      })
+    })
+  }
+  
+  likePost = (args: LikePostArgs, headers?: object): Promise<LikePostReturn> => {
+    return this.fetch(
+      this.url('LikePost'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          liked: <boolean>(_data.liked)
+        }
+      })
+    })
+  }
+  
+  unlikePost = (args: UnlikePostArgs, headers?: object): Promise<UnlikePostReturn> => {
+    return this.fetch(
+      this.url('UnlikePost'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          unliked: <boolean>(_data.unliked)
+        }
+      })
+    })
+  }
+  
+  followUser = (args: FollowUserArgs, headers?: object): Promise<FollowUserReturn> => {
+    return this.fetch(
+      this.url('FollowUser'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          followed: <boolean>(_data.followed)
+        }
+      })
+    })
+  }
+  
+  unfollowUser = (args: UnfollowUserArgs, headers?: object): Promise<UnfollowUserReturn> => {
+    return this.fetch(
+      this.url('UnfollowUser'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          unfollowed: <boolean>(_data.unfollowed)
+        }
+      })
+    })
+  }
+  
+  blockUser = (args: BlockUserArgs, headers?: object): Promise<BlockUserReturn> => {
+    return this.fetch(
+      this.url('BlockUser'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          blocked: <boolean>(_data.blocked)
+        }
+      })
+    })
+  }
+  
+  unblockUser = (args: UnblockUserArgs, headers?: object): Promise<UnblockUserReturn> => {
+    return this.fetch(
+      this.url('UnblockUser'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          unblocked: <boolean>(_data.unblocked)
+        }
+      })
+    })
+  }
+  
+  muteUser = (args: MuteUserArgs, headers?: object): Promise<MuteUserReturn> => {
+    return this.fetch(
+      this.url('MuteUser'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          muted: <boolean>(_data.muted)
+        }
+      })
+    })
+  }
+  
+  unmuteUser = (args: UnmuteUserArgs, headers?: object): Promise<UnmuteUserReturn> => {
+    return this.fetch(
+      this.url('UnmuteUser'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          unmuted: <boolean>(_data.unmuted)
+        }
+      })
+    })
+  }
+  
+  listBlockedUsers = (args: ListBlockedUsersArgs, headers?: object): Promise<ListBlockedUsersReturn> => {
+    return this.fetch(
+      this.url('ListBlockedUsers'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          accounts: <Array<string>>(_data.accounts), 
+          nextCursor: <string>(_data.nextCursor)
+        }
+      })
+    })
+  }
+  
+  search = (args: SearchArgs, headers?: object): Promise<SearchReturn> => {
+    return this.fetch(
+      this.url('Search'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          hits: <Array<SearchHit>>(_data.hits), 
+          nextCursor: <string>(_data.nextCursor)
+        }
+      })
+    })
+  }
+  
+  reportContent = (args: ReportContentArgs, headers?: object): Promise<ReportContentReturn> => {
+    return this.fetch(
+      this.url('ReportContent'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          report: <Report>(_data.report)
+        }
+      })
+    })
+  }
+  
+  listReports = (args: ListReportsArgs, headers?: object): Promise<ListReportsReturn> => {
+    return this.fetch(
+      this.url('ListReports'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          reports: <Array<Report>>(_data.reports), 
+          nextCursor: <string>(_data.nextCursor)
+        }
+      })
+    })
+  }
+  
+  moderateContent = (args: ModerateContentArgs, headers?: object): Promise<ModerateContentReturn> => {
+    return this.fetch(
+      this.url('ModerateContent'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          resolvedReports: <number>(_data.resolvedReports)
+        }
+      })
+    })
+  }
+  
+  listHeldComments = (args: ListHeldCommentsArgs, headers?: object): Promise<ListHeldCommentsReturn> => {
+    return this.fetch(
+      this.url('ListHeldComments'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          comments: <Array<HeldComment>>(_data.comments), 
+          nextCursor: <string>(_data.nextCursor)
+        }
+      })
+    })
+  }
+  
+  listBlocklist = (headers?: object): Promise<ListBlocklistReturn> => {
+    return this.fetch(
+      this.url('ListBlocklist'),
+      createHTTPRequest({}, headers)
+      ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          entries: <Array<BlocklistEntry>>(_data.entries)
+        }
+      })
+    })
+  }
+  
+  addBlocklistEntry = (args: AddBlocklistEntryArgs, headers?: object): Promise<AddBlocklistEntryReturn> => {
+    return this.fetch(
+      this.url('AddBlocklistEntry'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          entry: <BlocklistEntry>(_data.entry)
+        }
+      })
+    })
+  }
+  
+  removeBlocklistEntry = (args: RemoveBlocklistEntryArgs, headers?: object): Promise<RemoveBlocklistEntryReturn> => {
+    return this.fetch(
+      this.url('RemoveBlocklistEntry'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          removed: <boolean>(_data.removed)
+        }
+      })
+    })
+  }
+  
+  listAuditLog = (args: ListAuditLogArgs, headers?: object): Promise<ListAuditLogReturn> => {
+    return this.fetch(
+      this.url('ListAuditLog'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          entries: <Array<AuditEntry>>(_data.entries), 
+          nextCursor: <string>(_data.nextCursor)
+        }
+      })
+    })
+  }
+  
+  verifyAuditLog = (headers?: object): Promise<VerifyAuditLogReturn> => {
+    return this.fetch(
+      this.url('VerifyAuditLog'),
+      createHTTPRequest({}, headers)
+      ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          valid: <boolean>(_data.valid), 
+          brokenAt: <number>(_data.brokenAt)
+        }
+      })
+    })
+  }
+  
+  listNotifications = (args: ListNotificationsArgs, headers?: object): Promise<ListNotificationsReturn> => {
+    return this.fetch(
+      this.url('ListNotifications'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          notifications: <Array<Notification>>(_data.notifications), 
+          nextCursor: <string>(_data.nextCursor)
+        }
+      })
+    })
+  }
+  
+  markNotificationsRead = (args: MarkNotificationsReadArgs, headers?: object): Promise<MarkNotificationsReadReturn> => {
+    return this.fetch(
+      this.url('MarkNotificationsRead'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          count: <number>(_data.count)
+        }
+      })
+    })
+  }
+  
+  getUnreadCount = (headers?: object): Promise<GetUnreadCountReturn> => {
+    return this.fetch(
+      this.url('GetUnreadCount'),
+      createHTTPRequest({}, headers)
+      ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          count: <number>(_data.count)
+        }
+      })
+    })
+  }
+  
+  getNotificationPreferences = (headers?: object): Promise<GetNotificationPreferencesReturn> => {
+    return this.fetch(
+      this.url('GetNotificationPreferences'),
+      createHTTPRequest({}, headers)
+      ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          preferences: <NotificationPreferences>(_data.preferences)
+        }
+      })
+    })
+  }
+  
+  updateNotificationPreferences = (args: UpdateNotificationPreferencesArgs, headers?: object): Promise<UpdateNotificationPreferencesReturn> => {
+    return this.fetch(
+      this.url('UpdateNotificationPreferences'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          preferences: <NotificationPreferences>(_data.preferences)
+        }
+      })
+    })
+  }
+  
+  getEmailSettings = (headers?: object): Promise<GetEmailSettingsReturn> => {
+    return this.fetch(
+      this.url('GetEmailSettings'),
+      createHTTPRequest({}, headers)
+      ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          settings: <EmailSettings>(_data.settings)
+        }
+      })
+    })
+  }
+  
+  setEmail = (args: SetEmailArgs, headers?: object): Promise<SetEmailReturn> => {
+    return this.fetch(
+      this.url('SetEmail'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          settings: <EmailSettings>(_data.settings)
+        }
+      })
+    })
+  }
+  
+  setDigest = (args: SetDigestArgs, headers?: object): Promise<SetDigestReturn> => {
+    return this.fetch(
+      this.url('SetDigest'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          settings: <EmailSettings>(_data.settings)
+        }
+      })
+    })
+  }
+  
+  getPushPublicKey = (headers?: object): Promise<GetPushPublicKeyReturn> => {
+    return this.fetch(
+      this.url('GetPushPublicKey'),
+      createHTTPRequest({}, headers)
+      ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          key: <string>(_data.key)
+        }
+      })
+    })
+  }
+  
+  registerPushSubscription = (args: RegisterPushSubscriptionArgs, headers?: object): Promise<RegisterPushSubscriptionReturn> => {
+    return this.fetch(
+      this.url('RegisterPushSubscription'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          status: <boolean>(_data.status)
+        }
+      })
+    })
+  }
+  
+  removePushSubscription = (args: RemovePushSubscriptionArgs, headers?: object): Promise<RemovePushSubscriptionReturn> => {
+    return this.fetch(
+      this.url('RemovePushSubscription'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          removed: <boolean>(_data.removed)
+        }
+      })
+    })
+  }
+  
+  createWebhook = (args: CreateWebhookArgs, headers?: object): Promise<CreateWebhookReturn> => {
+    return this.fetch(
+      this.url('CreateWebhook'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          webhook: <Webhook>(_data.webhook), 
+          secret: <string>(_data.secret)
+        }
+      })
+    })
+  }
+  
+  listWebhooks = (headers?: object): Promise<ListWebhooksReturn> => {
+    return this.fetch(
+      this.url('ListWebhooks'),
+      createHTTPRequest({}, headers)
+      ).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          webhooks: <Array<Webhook>>(_data.webhooks)
+        }
+      })
+    })
+  }
+  
+  updateWebhook = (args: UpdateWebhookArgs, headers?: object): Promise<UpdateWebhookReturn> => {
+    return this.fetch(
+      this.url('UpdateWebhook'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          webhook: <Webhook>(_data.webhook)
+        }
+      })
+    })
+  }
+  
+  deleteWebhook = (args: DeleteWebhookArgs, headers?: object): Promise<DeleteWebhookReturn> => {
+    return this.fetch(
+      this.url('DeleteWebhook'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          deleted: <boolean>(_data.deleted)
+        }
+      })
+    })
+  }
+  
+  listWebhookDeliveries = (args: ListWebhookDeliveriesArgs, headers?: object): Promise<ListWebhookDeliveriesReturn> => {
+    return this.fetch(
+      this.url('ListWebhookDeliveries'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          deliveries: <Array<WebhookDelivery>>(_data.deliveries), 
+          nextCursor: <string>(_data.nextCursor)
+        }
+      })
+    })
+  }
+  
+  sendTestWebhook = (args: SendTestWebhookArgs, headers?: object): Promise<SendTestWebhookReturn> => {
+    return this.fetch(
+      this.url('SendTestWebhook'),
+      createHTTPRequest(args, headers)).then((res) => {
+      return buildResponse(res).then(_data => {
+        return {
+          delivery: <WebhookDelivery>(_data.delivery)
+        }
+      })
+    })
+  }
+  
 }
 
   
@@ -0,0 +1,96 @@
+package push
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// recordSize is the record size of the encrypted content, a single record
+// holds the whole message.
+const recordSize = 4096
+
+// MaxPayload is the largest payload fitting a single record: the record
+// minus the 16 bytes authentication tag and the padding delimiter.
+const MaxPayload = recordSize - 16 - 1
+
+// encrypt encrypts payload for the user agent with the given public key and
+// authentication secret, with the aes128gcm content encoding of RFC 8188 as
+// specified for Web Push by RFC 8291.
+func encrypt(payload []byte, uaPublic, authSecret []byte) ([]byte, error) {
+	if len(payload) > MaxPayload {
+		return nil, fmt.Errorf("push: payload of %d bytes exceeds %d bytes", len(payload), MaxPayload)
+	}
+	curve := elliptic.P256()
+	ux, uy := elliptic.Unmarshal(curve, uaPublic)
+	if ux == nil {
+		return nil, errors.New("push: invalid p256dh key")
+	}
+	if len(authSecret) != 16 {
+		return nil, errors.New("push: invalid auth secret")
+	}
+
+	// Ephemeral application server key pair.
+	asPrivate, ax, ay, err := elliptic.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	asPublic := elliptic.Marshal(curve, ax, ay)
+
+	sx, _ := curve.ScalarMult(ux, uy, asPrivate)
+	ecdhSecret := sx.FillBytes(make([]byte, 32))
+
+	// IKM = HKDF(auth_secret, ecdh_secret, "WebPush: info" || 0x00 || ua_public || as_public, 32)
+	keyInfo := append([]byte("WebPush: info\x00"), uaPublic...)
+	keyInfo = append(keyInfo, asPublic...)
+	ikm := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, ecdhSecret, authSecret, keyInfo), ikm); err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	prk := hkdf.Extract(sha256.New, ikm, salt)
+
+	cek := make([]byte, 16)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, []byte("Content-Encoding: aes128gcm\x00")), cek); err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, 12)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, []byte("Content-Encoding: nonce\x00")), nonce); err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// The last and only record ends with the 0x02 padding delimiter.
+	plaintext := append(append([]byte{}, payload...), 0x02)
+
+	// Header: salt || rs || idlen || keyid, with the server public key as
+	// the key id.
+	body := make([]byte, 0, 16+4+1+len(asPublic)+len(plaintext)+gcm.Overhead())
+	body = append(body, salt...)
+	rs := make([]byte, 4)
+	binary.BigEndian.PutUint32(rs, recordSize)
+	body = append(body, rs...)
+	body = append(body, byte(len(asPublic)))
+	body = append(body, asPublic...)
+	return gcm.Seal(body, nonce, plaintext, nil), nil
+}
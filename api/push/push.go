@@ -0,0 +1,205 @@
+// Package push delivers Web Push messages to the browsers subscribed by the
+// users: payloads are encrypted per RFC 8291 and the server authenticates
+// to the push services with VAPID, RFC 8292.
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/config"
+	"github.com/nfteseum/nfteseum-learning-project/api/egress"
+)
+
+// Job kind of the deliveries, handled in the tasks package.
+const SendJob = "send_push"
+
+// SendArgs are the arguments of a SendJob.
+type SendArgs struct {
+	SubscriptionID int64    `json:"subscriptionID"`
+	Message        *Message `json:"message"`
+}
+
+var (
+	// ErrGone is returned when the subscription expired or was removed, and
+	// should be deleted.
+	ErrGone = errors.New("push: subscription is gone")
+
+	// ErrDisabled is returned when the VAPID keys aren't configured.
+	ErrDisabled = errors.New("push: web push is not configured")
+)
+
+// b64 is the base64url encoding without padding of the keys and tokens.
+var b64 = base64.RawURLEncoding
+
+// Subscription is a PushSubscription of a browser.
+type Subscription struct {
+	Endpoint string
+	P256dh   string
+	Auth     string
+}
+
+// Message is the payload shown by the service worker of the app.
+type Message struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	URL   string `json:"url,omitempty"`
+
+	// Tag replaces the notifications of the same tag on the device, ie. the
+	// likes of the same post.
+	Tag string `json:"tag,omitempty"`
+}
+
+// StatusError is returned when the push service rejects a message.
+type StatusError struct {
+	Status int
+	Body   string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("push: push service responded %d: %s", e.Status, e.Body)
+}
+
+// Temporary reports whether the message may be accepted if sent again.
+func (e *StatusError) Temporary() bool {
+	return e.Status == http.StatusTooManyRequests || e.Status >= 500
+}
+
+type Sender struct {
+	key       *ecdsa.PrivateKey
+	publicKey string
+	subject   string
+	ttl       time.Duration
+	client    *http.Client
+}
+
+// Enabled reports whether web push is configured.
+func Enabled(cfg *config.Config) bool {
+	return cfg.Push.VAPIDPublicKey != "" && cfg.Push.VAPIDPrivateKey != ""
+}
+
+// New returns the sender configured by [push], or ErrDisabled if the VAPID
+// keys aren't set.
+func New(cfg *config.Config) (*Sender, error) {
+	if !Enabled(cfg) {
+		return nil, ErrDisabled
+	}
+	key, pub, err := parseVAPIDKeys(cfg.Push.VAPIDPublicKey, cfg.Push.VAPIDPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(cfg.Push.Subject, "mailto:") && !strings.HasPrefix(cfg.Push.Subject, "https:") {
+		return nil, fmt.Errorf("push: config invalid push.subject value: must be a mailto: or https: url")
+	}
+
+	ttl := 24 * time.Hour
+	if cfg.Push.TTL != "" {
+		ttl, err = time.ParseDuration(cfg.Push.TTL)
+		if err != nil {
+			return nil, fmt.Errorf("push: config invalid push.ttl value: %w", err)
+		}
+	}
+
+	timeout := 30 * time.Second
+	return &Sender{
+		key:       key,
+		publicKey: b64.EncodeToString(pub),
+		subject:   cfg.Push.Subject,
+		ttl:       ttl,
+		client: &http.Client{
+			Timeout: timeout,
+			// Endpoints are given by the browsers of the users.
+			Transport: egress.Transport(timeout, cfg.Mode == config.DevelopmentMode),
+		},
+	}, nil
+}
+
+// PublicKey returns the VAPID public key, the applicationServerKey browsers
+// subscribe with.
+func (s *Sender) PublicKey() string {
+	return s.publicKey
+}
+
+// Send encrypts payload for sub and posts it to its push service. Pending
+// messages of the same non-empty topic replace each other on the push
+// service.
+func (s *Sender) Send(ctx context.Context, sub *Subscription, payload []byte, topic string) error {
+	uaPublic, err := decodeKey(sub.P256dh)
+	if err != nil {
+		return fmt.Errorf("push: invalid p256dh key: %w", err)
+	}
+	auth, err := decodeKey(sub.Auth)
+	if err != nil {
+		return fmt.Errorf("push: invalid auth secret: %w", err)
+	}
+	body, err := encrypt(payload, uaPublic, auth)
+	if err != nil {
+		return err
+	}
+	token, err := vapidToken(s.key, sub.Endpoint, s.subject, time.Now())
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", strconv.Itoa(int(s.ttl.Seconds())))
+	req.Header.Set("Urgency", "normal")
+	req.Header.Set("Authorization", "vapid t="+token+", k="+s.publicKey)
+	if topic != "" {
+		// Topics are limited to 32 base64url characters.
+		sum := sha256.Sum256([]byte(topic))
+		req.Header.Set("Topic", b64.EncodeToString(sum[:24]))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("push: failed to reach push service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrGone
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	default:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &StatusError{Status: resp.StatusCode, Body: string(msg)}
+	}
+}
+
+// ValidateKeys checks the keys of a subscription, as given by the browser.
+func ValidateKeys(p256dh, auth string) error {
+	pub, err := decodeKey(p256dh)
+	if err != nil || len(pub) != 65 || pub[0] != 4 {
+		return errors.New("p256dh must be an uncompressed P-256 public key")
+	}
+	secret, err := decodeKey(auth)
+	if err != nil || len(secret) != 16 {
+		return errors.New("auth must be a 16 bytes secret")
+	}
+	return nil
+}
+
+// decodeKey decodes the keys of a subscription, which browsers give in
+// base64url but some clients pad or encode in standard base64.
+func decodeKey(s string) ([]byte, error) {
+	s = strings.TrimRight(s, "=")
+	s = strings.NewReplacer("+", "-", "/", "_").Replace(s)
+	return b64.DecodeString(s)
+}
@@ -0,0 +1,283 @@
+package push
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/config"
+	"github.com/nfteseum/nfteseum-learning-project/api/egress"
+	"golang.org/x/crypto/hkdf"
+)
+
+// userAgent is a browser subscribed to a push service.
+type userAgent struct {
+	key    *ecdsa.PrivateKey
+	public []byte
+	auth   []byte
+}
+
+func newUserAgent(t *testing.T) *userAgent {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := make([]byte, 16)
+	if _, err := rand.Read(auth); err != nil {
+		t.Fatal(err)
+	}
+	return &userAgent{
+		key:    key,
+		public: elliptic.Marshal(elliptic.P256(), key.X, key.Y),
+		auth:   auth,
+	}
+}
+
+func (ua *userAgent) subscription(endpoint string) *Subscription {
+	return &Subscription{
+		Endpoint: endpoint,
+		P256dh:   b64.EncodeToString(ua.public),
+		Auth:     b64.EncodeToString(ua.auth),
+	}
+}
+
+// decrypt decrypts a message encrypted for the user agent, as its browser
+// does per RFC 8291.
+func (ua *userAgent) decrypt(body []byte) ([]byte, error) {
+	if len(body) < 21 || len(body) < 21+int(body[20]) {
+		return nil, errors.New("truncated header")
+	}
+	salt := body[:16]
+	rs := binary.BigEndian.Uint32(body[16:20])
+	asPublic := body[21 : 21+int(body[20])]
+	ciphertext := body[21+int(body[20]):]
+	if len(ciphertext) > int(rs) {
+		return nil, errors.New("record exceeds the record size")
+	}
+
+	curve := elliptic.P256()
+	ax, ay := elliptic.Unmarshal(curve, asPublic)
+	if ax == nil {
+		return nil, errors.New("invalid key id")
+	}
+	sx, _ := curve.ScalarMult(ax, ay, ua.key.D.Bytes())
+	ecdhSecret := sx.FillBytes(make([]byte, 32))
+
+	keyInfo := append([]byte("WebPush: info\x00"), ua.public...)
+	keyInfo = append(keyInfo, asPublic...)
+	ikm := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, ecdhSecret, ua.auth, keyInfo), ikm); err != nil {
+		return nil, err
+	}
+	prk := hkdf.Extract(sha256.New, ikm, salt)
+	cek := make([]byte, 16)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, []byte("Content-Encoding: aes128gcm\x00")), cek); err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, 12)
+	if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, []byte("Content-Encoding: nonce\x00")), nonce); err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, err
+	}
+	i := len(plaintext) - 1
+	for i >= 0 && plaintext[i] == 0 {
+		i--
+	}
+	if i < 0 || plaintext[i] != 0x02 {
+		return nil, errors.New("missing padding delimiter")
+	}
+	return plaintext[:i], nil
+}
+
+func newTestSender(t *testing.T) *Sender {
+	t.Helper()
+	public, private, err := GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{Mode: config.DevelopmentMode}
+	cfg.Push.VAPIDPublicKey = public
+	cfg.Push.VAPIDPrivateKey = private
+	cfg.Push.Subject = "mailto:ops@example.com"
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// verifyVAPID checks the Authorization header of a push request, and returns
+// the claims of its token.
+func verifyVAPID(header, publicKey string) (map[string]interface{}, error) {
+	params := map[string]string{}
+	if !strings.HasPrefix(header, "vapid ") {
+		return nil, errors.New("not a vapid authorization")
+	}
+	for _, p := range strings.Split(strings.TrimPrefix(header, "vapid "), ",") {
+		k, v, _ := strings.Cut(strings.TrimSpace(p), "=")
+		params[k] = v
+	}
+	if params["k"] != publicKey {
+		return nil, errors.New("unexpected public key")
+	}
+	parts := strings.Split(params["t"], ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	pub, err := b64.DecodeString(params["k"])
+	if err != nil {
+		return nil, err
+	}
+	x, y := elliptic.Unmarshal(elliptic.P256(), pub)
+	sig, err := b64.DecodeString(parts[2])
+	if err != nil || len(sig) != 64 {
+		return nil, errors.New("malformed signature")
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	key := &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+	if !ecdsa.Verify(key, digest[:], new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])) {
+		return nil, errors.New("invalid signature")
+	}
+
+	raw, err := b64.DecodeString(parts[1])
+	if err != nil {
+		return nil, err
+	}
+	var claims map[string]interface{}
+	return claims, json.Unmarshal(raw, &claims)
+}
+
+func TestSend(t *testing.T) {
+	s := newTestSender(t)
+	ua := newUserAgent(t)
+
+	var (
+		body   []byte
+		header http.Header
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	payload := []byte(`{"title":"New like","body":"0xabc liked your post"}`)
+	err := s.Send(context.Background(), ua.subscription(srv.URL+"/push/abc"), payload, "post:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := header.Get("Content-Encoding"); got != "aes128gcm" {
+		t.Errorf("Content-Encoding = %q, want aes128gcm", got)
+	}
+	if header.Get("TTL") == "" {
+		t.Error("TTL header is missing")
+	}
+	if got := header.Get("Topic"); len(got) != 32 {
+		t.Errorf("Topic = %q, want 32 characters", got)
+	}
+
+	plaintext, err := ua.decrypt(body)
+	if err != nil {
+		t.Fatalf("decrypt: %v", err)
+	}
+	if string(plaintext) != string(payload) {
+		t.Errorf("payload = %q, want %q", plaintext, payload)
+	}
+
+	claims, err := verifyVAPID(header.Get("Authorization"), s.PublicKey())
+	if err != nil {
+		t.Fatalf("Authorization %q: %v", header.Get("Authorization"), err)
+	}
+	if claims["aud"] != srv.URL {
+		t.Errorf("aud = %v, want %s", claims["aud"], srv.URL)
+	}
+	if claims["sub"] != "mailto:ops@example.com" {
+		t.Errorf("sub = %v, want mailto:ops@example.com", claims["sub"])
+	}
+}
+
+func TestSendStatus(t *testing.T) {
+	s := newTestSender(t)
+	ua := newUserAgent(t)
+
+	for _, tc := range []struct {
+		status    int
+		gone      bool
+		temporary bool
+	}{
+		{status: http.StatusNotFound, gone: true},
+		{status: http.StatusGone, gone: true},
+		{status: http.StatusBadRequest},
+		{status: http.StatusTooManyRequests, temporary: true},
+		{status: http.StatusServiceUnavailable, temporary: true},
+	} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tc.status)
+		}))
+		err := s.Send(context.Background(), ua.subscription(srv.URL), []byte("{}"), "")
+		srv.Close()
+
+		if errors.Is(err, ErrGone) != tc.gone {
+			t.Errorf("%d: err = %v, want ErrGone %v", tc.status, err, tc.gone)
+		}
+		var statusErr *StatusError
+		if !tc.gone && (!errors.As(err, &statusErr) || statusErr.Temporary() != tc.temporary) {
+			t.Errorf("%d: err = %v, want a StatusError with Temporary %v", tc.status, err, tc.temporary)
+		}
+	}
+}
+
+func TestSendPrivateEndpoint(t *testing.T) {
+	public, private, err := GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{Mode: config.ProductionMode}
+	cfg.Push.VAPIDPublicKey = public
+	cfg.Push.VAPIDPrivateKey = private
+	cfg.Push.Subject = "mailto:ops@example.com"
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	err = s.Send(context.Background(), newUserAgent(t).subscription(srv.URL), []byte("{}"), "")
+	if !errors.Is(err, egress.ErrPrivateAddress) || called {
+		t.Errorf("err = %v, want egress.ErrPrivateAddress", err)
+	}
+}
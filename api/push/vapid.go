@@ -0,0 +1,83 @@
+package push
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/url"
+	"time"
+)
+
+// vapidTTL is the lifetime of the VAPID tokens, at most 24h per RFC 8292.
+const vapidTTL = 12 * time.Hour
+
+// GenerateVAPIDKeys returns a new VAPID key pair, base64url encoded as
+// expected by the [push] config and the browsers' applicationServerKey.
+func GenerateVAPIDKeys() (string, string, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	pub := elliptic.Marshal(elliptic.P256(), key.X, key.Y)
+	priv := key.D.FillBytes(make([]byte, 32))
+	return b64.EncodeToString(pub), b64.EncodeToString(priv), nil
+}
+
+func parseVAPIDKeys(public, private string) (*ecdsa.PrivateKey, []byte, error) {
+	pub, err := b64.DecodeString(public)
+	if err != nil {
+		return nil, nil, fmt.Errorf("push: config invalid push.vapid_public_key value: %w", err)
+	}
+	x, y := elliptic.Unmarshal(elliptic.P256(), pub)
+	if x == nil {
+		return nil, nil, fmt.Errorf("push: config invalid push.vapid_public_key value: not an uncompressed P-256 point")
+	}
+
+	d, err := b64.DecodeString(private)
+	if err != nil || len(d) != 32 {
+		return nil, nil, fmt.Errorf("push: config invalid push.vapid_private_key value: expected 32 base64url encoded bytes")
+	}
+	key := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y},
+		D:         new(big.Int).SetBytes(d),
+	}
+	cx, cy := elliptic.P256().ScalarBaseMult(d)
+	if cx.Cmp(x) != 0 || cy.Cmp(y) != 0 {
+		return nil, nil, fmt.Errorf("push: config invalid push VAPID keys: the public key doesn't match the private key")
+	}
+	return key, pub, nil
+}
+
+// vapidToken returns the signed JWT authorizing the server on the push
+// service of endpoint, see RFC 8292.
+func vapidToken(key *ecdsa.PrivateKey, endpoint, subject string, now time.Time) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	header := b64.EncodeToString([]byte(`{"typ":"JWT","alg":"ES256"}`))
+	claims, err := json.Marshal(map[string]interface{}{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": now.Add(vapidTTL).Unix(),
+		"sub": subject,
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := header + "." + b64.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(unsigned))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		return "", err
+	}
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	return unsigned + "." + b64.EncodeToString(sig), nil
+}
@@ -0,0 +1,127 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgtype"
+	"github.com/nfteseum/nfteseum-learning-project/api/audit"
+	"github.com/nfteseum/nfteseum-learning-project/api/chain"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/nfteseum/nfteseum-learning-project/api/proto"
+)
+
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 200
+)
+
+// ListAuditLog returns the entries of the audit log, latest first, filtered
+// by actor, action, and target. The returned cursor fetches the next page,
+// and is empty on the last one. Admins only.
+func (s *RPC) ListAuditLog(ctx context.Context, actor *string, action *string, targetType *string, targetID *string, cursor *string, limit *int32) ([]*proto.AuditEntry, string, error) {
+	if _, err := s.adminAccount(ctx); err != nil {
+		return nil, "", err
+	}
+
+	params := sqlc.ListAuditLogParams{
+		BeforeID:   math.MaxInt64,
+		MaxEntries: defaultAuditLimit,
+	}
+	if actor != nil {
+		if !chain.IsAddress(*actor) {
+			return nil, "", proto.ErrorInvalidArgument("actor", "must be an address")
+		}
+		params.Actor = strings.ToLower(*actor)
+	}
+	if action != nil {
+		params.Action = *action
+	}
+	if targetType != nil {
+		params.TargetType = *targetType
+	}
+	if targetID != nil {
+		if targetType == nil {
+			return nil, "", proto.ErrorRequiredArgument("targetType")
+		}
+		params.TargetID = *targetID
+		if chain.IsAddress(*targetID) {
+			params.TargetID = strings.ToLower(*targetID)
+		}
+	}
+	if limit != nil {
+		if *limit <= 0 || *limit > maxAuditLimit {
+			return nil, "", proto.ErrorInvalidArgument("limit", fmt.Sprintf("must be between 1 and %d", maxAuditLimit))
+		}
+		params.MaxEntries = *limit
+	}
+	if cursor != nil && *cursor != "" {
+		var err error
+		params.BeforeID, err = strconv.ParseInt(*cursor, 10, 64)
+		if err != nil {
+			return nil, "", proto.ErrorInvalidArgument("cursor", "is malformed")
+		}
+	}
+
+	rows, err := data.DB.ListAuditLog(ctx, params)
+	if err != nil {
+		return nil, "", s.dbError(ctx, err)
+	}
+	list := make([]*proto.AuditEntry, len(rows))
+	for i := range rows {
+		list[i] = auditEntryFromRow(&rows[i])
+	}
+
+	next := ""
+	if len(rows) == int(params.MaxEntries) {
+		next = strconv.FormatInt(rows[len(rows)-1].ID, 10)
+	}
+	return list, next, nil
+}
+
+// VerifyAuditLog checks the hash chain of the audit log. When an entry was
+// altered or the one before it deleted, the chain is reported broken at that
+// entry. Admins only.
+func (s *RPC) VerifyAuditLog(ctx context.Context) (bool, int64, error) {
+	account, err := s.adminAccount(ctx)
+	if err != nil {
+		return false, 0, err
+	}
+	brokenAt, err := audit.Verify(ctx)
+	if err != nil {
+		return false, 0, s.dbError(ctx, err)
+	}
+	if brokenAt != 0 {
+		s.GetLogger(ctx).Warn().Str("admin", account).Int64("entry", brokenAt).Msg("audit log chain is broken")
+	}
+	return brokenAt == 0, brokenAt, nil
+}
+
+func auditEntryFromRow(row *sqlc.AuditLog) *proto.AuditEntry {
+	return &proto.AuditEntry{
+		ID:         row.ID,
+		Actor:      strings.TrimSpace(row.Actor),
+		Action:     row.Action,
+		TargetType: row.TargetType,
+		TargetID:   row.TargetID,
+		Before:     jsonString(row.Before),
+		After:      jsonString(row.After),
+		RequestID:  row.RequestID,
+		IP:         row.Ip,
+		CreatedAt:  row.CreatedAt,
+		PrevHash:   row.PrevHash,
+		Hash:       row.Hash,
+	}
+}
+
+func jsonString(j pgtype.JSON) *string {
+	if j.Status != pgtype.Present {
+		return nil
+	}
+	s := string(j.Bytes)
+	return &s
+}
@@ -0,0 +1,106 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/chain"
+	"github.com/nfteseum/nfteseum-learning-project/api/proto"
+	"github.com/nfteseum/nfteseum-learning-project/api/social"
+)
+
+const (
+	defaultBlocksLimit = 50
+	maxBlocksLimit     = 200
+)
+
+// BlockUser blocks a user from commenting on, liking or following the
+// account, and removes the follows between them. Blocked users are also
+// muted. It reports whether the block is new.
+func (s *RPC) BlockUser(ctx context.Context, account string) (bool, error) {
+	return s.blockUser(ctx, account, social.Block)
+}
+
+// UnblockUser lifts the block of a user, and reports whether there was one.
+func (s *RPC) UnblockUser(ctx context.Context, account string) (bool, error) {
+	return s.unblockUser(ctx, account, social.Block)
+}
+
+// MuteUser leaves a user out of the feeds and notifications of the account,
+// and reports whether the mute is new.
+func (s *RPC) MuteUser(ctx context.Context, account string) (bool, error) {
+	return s.blockUser(ctx, account, social.Mute)
+}
+
+// UnmuteUser lifts the mute of a user, and reports whether there was one.
+func (s *RPC) UnmuteUser(ctx context.Context, account string) (bool, error) {
+	return s.unblockUser(ctx, account, social.Mute)
+}
+
+// ListBlockedUsers returns the users blocked or muted by the account, as
+// kind, "block" or "mute", by address. The returned cursor fetches the next
+// page, and is empty on the last one.
+func (s *RPC) ListBlockedUsers(ctx context.Context, kind string, cursor *string, limit *int32) ([]string, string, error) {
+	account, err := sessionAccount(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	if kind != social.Block && kind != social.Mute {
+		return nil, "", proto.ErrorInvalidArgument("kind", "must be block or mute")
+	}
+	n := int32(defaultBlocksLimit)
+	if limit != nil {
+		if *limit <= 0 || *limit > maxBlocksLimit {
+			return nil, "", proto.ErrorInvalidArgument("limit", fmt.Sprintf("must be between 1 and %d", maxBlocksLimit))
+		}
+		n = *limit
+	}
+	after := ""
+	if cursor != nil && *cursor != "" {
+		if !chain.IsAddress(*cursor) {
+			return nil, "", proto.ErrorInvalidArgument("cursor", "is malformed")
+		}
+		after = strings.ToLower(*cursor)
+	}
+
+	accounts, err := social.ListBlockedUsers(ctx, account, kind, after, n)
+	if err != nil {
+		return nil, "", s.dbError(ctx, err)
+	}
+	next := ""
+	if len(accounts) == int(n) {
+		next = accounts[len(accounts)-1]
+	}
+	return accounts, next, nil
+}
+
+func (s *RPC) blockUser(ctx context.Context, target, kind string) (bool, error) {
+	account, err := s.activeAccount(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !chain.IsAddress(target) {
+		return false, proto.ErrorInvalidArgument("account", "must be an address")
+	}
+	created, err := social.BlockUser(ctx, account, strings.ToLower(target), kind)
+	if err != nil {
+		return false, s.dbError(ctx, err)
+	}
+	return created, nil
+}
+
+func (s *RPC) unblockUser(ctx context.Context, target, kind string) (bool, error) {
+	account, err := sessionAccount(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !chain.IsAddress(target) {
+		return false, proto.ErrorInvalidArgument("account", "must be an address")
+	}
+	deleted, err := social.UnblockUser(ctx, account, strings.ToLower(target), kind)
+	if err != nil {
+		return false, s.dbError(ctx, err)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,14 @@
+package rpc
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// handleChat joins the account to the chat room of a collection over a
+// websocket, see the chat package for the protocol.
+func (s *RPC) handleChat(w http.ResponseWriter, r *http.Request) {
+	account, _ := AccountFromContext(r.Context())
+	s.Chat.Serve(w, r, account, chi.URLParam(r, "contract"))
+}
@@ -0,0 +1,195 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/nfteseum/nfteseum-learning-project/api/moderation"
+	"github.com/nfteseum/nfteseum-learning-project/api/proto"
+	"github.com/nfteseum/nfteseum-learning-project/api/social"
+)
+
+const (
+	maxCommentLength = 2000
+
+	defaultCommentsLimit = 20
+	maxCommentsLimit     = 100
+)
+
+// AddComment comments on a post, or replies to one of its comments. Comments
+// flagged by the spam filter are held for review, and only published once a
+// moderator restores them.
+func (s *RPC) AddComment(ctx context.Context, postID int32, content string, parentID *int32) (*proto.Comment, error) {
+	account, err := s.activeAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, proto.ErrorRequiredArgument("content")
+	}
+	if utf8.RuneCountInString(content) > maxCommentLength {
+		return nil, proto.ErrorInvalidArgument("content", fmt.Sprintf("must be at most %d characters long", maxCommentLength))
+	}
+	var parent int32
+	if parentID != nil {
+		parent = *parentID
+	}
+
+	var hold *social.Hold
+	verdict, err := s.Spam.Score(ctx, account, postID, content)
+	if err != nil {
+		return nil, s.dbError(ctx, err)
+	}
+	if verdict.Hold {
+		hold = &social.Hold{Score: verdict.Score, Reasons: verdict.Reasons}
+	}
+
+	comment, err := social.AddComment(ctx, account, postID, parent, content, hold)
+	if errors.Is(err, social.ErrInvalidParent) {
+		return nil, proto.ErrorInvalidArgument("parentID", "must be a comment of the post")
+	}
+	if errors.Is(err, social.ErrSuspended) {
+		return nil, proto.Errorf(proto.ErrPermissionDenied, "account is suspended")
+	}
+	if errors.Is(err, social.ErrBlocked) {
+		return nil, proto.Errorf(proto.ErrPermissionDenied, "you can't reply to this user")
+	}
+	if err != nil {
+		return nil, s.dbError(ctx, err)
+	}
+	if hold != nil {
+		s.GetLogger(ctx).Info().Str("author", account).Int32("commentID", comment.ID).Float64("score", hold.Score).Strs("reasons", hold.Reasons).Msg("held comment for review")
+	}
+	return commentFromSocial(comment), nil
+}
+
+// ListComments returns the comments of a post, latest first, but those of
+// the users the account blocked or muted. The returned cursor fetches the
+// next page, and is empty on the last one.
+func (s *RPC) ListComments(ctx context.Context, postID int32, cursor *string, limit *int32) ([]*proto.Comment, string, error) {
+	n, before, err := pageArgs(cursor, limit, defaultCommentsLimit, maxCommentsLimit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	viewer, _ := AccountFromContext(ctx)
+	comments, err := social.ListComments(ctx, viewer, postID, before, n)
+	if err != nil {
+		return nil, "", s.dbError(ctx, err)
+	}
+	list := make([]*proto.Comment, len(comments))
+	for i, comment := range comments {
+		list[i] = commentFromSocial(comment)
+	}
+
+	next := ""
+	if len(comments) == int(n) {
+		next = strconv.FormatInt(int64(comments[len(comments)-1].ID), 10)
+	}
+	return list, next, nil
+}
+
+// ListPostsByHashtag returns the posts with comments holding a hashtag,
+// latest first, leaving out the users the account blocked or muted. The
+// returned cursor fetches the next page, and is empty on the last one.
+func (s *RPC) ListPostsByHashtag(ctx context.Context, tag string, cursor *string, limit *int32) ([]*proto.Post, string, error) {
+	tag, ok := social.NormalizeHashtag(tag)
+	if !ok {
+		return nil, "", proto.ErrorInvalidArgument("tag", "must be a hashtag")
+	}
+	n, before, err := pageArgs(cursor, limit, defaultCommentsLimit, maxCommentsLimit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	viewer, _ := AccountFromContext(ctx)
+	rows, err := data.DB.ListPostsByHashtag(ctx, sqlc.ListPostsByHashtagParams{
+		Tag:      tag,
+		Viewer:   viewer,
+		BeforeID: before,
+		MaxPosts: n,
+	})
+	if err != nil {
+		return nil, "", s.dbError(ctx, err)
+	}
+	list := make([]*proto.Post, len(rows))
+	for i := range rows {
+		list[i] = postFromRow(&rows[i])
+	}
+
+	next := ""
+	if len(rows) == int(n) {
+		next = strconv.FormatInt(int64(rows[len(rows)-1].ID), 10)
+	}
+	return list, next, nil
+}
+
+// pageArgs validates the arguments of a page keyed by int32 ids, and returns
+// its size and the id to list from.
+func pageArgs(cursor *string, limit *int32, defaultLimit, maxLimit int32) (int32, int32, error) {
+	n := defaultLimit
+	if limit != nil {
+		if *limit <= 0 || *limit > maxLimit {
+			return 0, 0, proto.ErrorInvalidArgument("limit", fmt.Sprintf("must be between 1 and %d", maxLimit))
+		}
+		n = *limit
+	}
+	before := int32(math.MaxInt32)
+	if cursor != nil && *cursor != "" {
+		id, err := strconv.ParseInt(*cursor, 10, 32)
+		if err != nil {
+			return 0, 0, proto.ErrorInvalidArgument("cursor", "is malformed")
+		}
+		before = int32(id)
+	}
+	return n, before, nil
+}
+
+func commentFromSocial(c *social.Comment) *proto.Comment {
+	comment := &proto.Comment{
+		ID:        c.ID,
+		PostID:    c.PostID,
+		Author:    strings.TrimSpace(c.Author),
+		Content:   c.Content,
+		Entities:  make([]*proto.Entity, len(c.Entities)),
+		CreatedAt: c.CreatedAt,
+		Held:      c.Visibility == moderation.Held,
+	}
+	if c.ParentID.Valid {
+		comment.ParentID = &c.ParentID.Int32
+	}
+	for i, e := range c.Entities {
+		comment.Entities[i] = &proto.Entity{
+			Type:  e.Type,
+			Start: int32(e.Start),
+			End:   int32(e.End),
+			Value: e.Value,
+		}
+		if e.Account != "" {
+			account := e.Account
+			comment.Entities[i].Account = &account
+		}
+	}
+	return comment
+}
+
+func postFromRow(row *sqlc.Posts) *proto.Post {
+	return &proto.Post{
+		ID:           row.ID,
+		Contract:     strings.ToLower(strings.TrimSpace(row.ContractAddr)),
+		TokenID:      row.TokenID,
+		Author:       strings.TrimSpace(row.Author),
+		LikeCount:    row.LikeCount.Int32,
+		CommentCount: row.CommentCount.Int32,
+		CreatedAt:    row.CreatedAt,
+	}
+}
@@ -0,0 +1,269 @@
+package rpc
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/nfteseum/nfteseum-learning-project/api/audit"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/nfteseum/nfteseum-learning-project/api/emails"
+	"github.com/nfteseum/nfteseum-learning-project/api/links"
+	"github.com/nfteseum/nfteseum-learning-project/api/mail"
+	"github.com/nfteseum/nfteseum-learning-project/api/proto"
+	"github.com/nfteseum/nfteseum-learning-project/api/tasks"
+)
+
+func (s *RPC) GetEmailSettings(ctx context.Context) (*proto.EmailSettings, error) {
+	account, err := sessionAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.emailSettings(ctx, account)
+}
+
+// SetEmail sets the email address of the account, and mails it a
+// verification link. No mail is sent to the address until it's verified.
+// An empty email removes the address.
+func (s *RPC) SetEmail(ctx context.Context, email string) (*proto.EmailSettings, error) {
+	account, err := s.activeAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	email = strings.TrimSpace(email)
+	if email != "" && (len(email) > 254 || !mail.ValidAddress(email)) {
+		return nil, proto.ErrorInvalidArgument("email", "must be a valid email address")
+	}
+
+	err = data.WithTx(ctx, pgx.TxOptions{}, func(q *sqlc.Queries) error {
+		user, err := q.GetUser(ctx, account)
+		if errors.Is(err, data.ErrNoRows) {
+			return proto.ErrorNotFound("user does not exist")
+		}
+		if err != nil {
+			return err
+		}
+		address := sql.NullString{String: email, Valid: email != ""}
+		if _, err := q.SetUserEmail(ctx, sqlc.SetUserEmailParams{Addr: account, Email: address}); err != nil {
+			return err
+		}
+
+		before := emailStateOf(&user)
+		after := before
+		after.Email, after.Verified = nullString(address), false
+		err = audit.Record(ctx, q, account, audit.Entry{
+			Action:     audit.ActionSetEmail,
+			TargetType: audit.TargetAccount,
+			TargetID:   account,
+			Before:     before,
+			After:      after,
+		})
+		if err != nil || email == "" {
+			return err
+		}
+		_, err = s.Jobs.EnqueueTx(ctx, q, tasks.SendVerificationEmail, tasks.SendVerificationEmailArgs{
+			Account: account,
+			Email:   email,
+		}, nil)
+		return err
+	})
+	if err != nil {
+		return nil, s.dbError(ctx, err)
+	}
+	return s.emailSettings(ctx, account)
+}
+
+// SetDigest sets how often the account is mailed a digest of its activity:
+// "daily", "weekly" or "off". Suspended accounts can only turn it off, as
+// they can always opt out of mails.
+func (s *RPC) SetDigest(ctx context.Context, frequency string) (*proto.EmailSettings, error) {
+	account, err := sessionAccount(ctx)
+	if frequency != emails.DigestOff {
+		account, err = s.activeAccount(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	switch frequency {
+	case emails.DigestOff, emails.DigestDaily, emails.DigestWeekly:
+	default:
+		return nil, proto.ErrorInvalidArgument("frequency", "must be one of off, daily or weekly")
+	}
+
+	err = data.WithTx(ctx, pgx.TxOptions{}, func(q *sqlc.Queries) error {
+		user, err := q.GetUser(ctx, account)
+		if errors.Is(err, data.ErrNoRows) {
+			return proto.ErrorNotFound("user does not exist")
+		}
+		if err != nil {
+			return err
+		}
+		return setDigest(ctx, q, &user, frequency, audit.ActionSetDigest)
+	})
+	if err != nil {
+		return nil, s.dbError(ctx, err)
+	}
+	return s.emailSettings(ctx, account)
+}
+
+// setDigest sets the digest frequency of user, and records the change in the
+// audit log as action.
+func setDigest(ctx context.Context, q *sqlc.Queries, user *sqlc.Users, frequency, action string) error {
+	account := strings.TrimSpace(user.Addr)
+	if _, err := q.SetUserDigest(ctx, sqlc.SetUserDigestParams{Addr: account, Digest: frequency}); err != nil {
+		return err
+	}
+	before := emailStateOf(user)
+	after := before
+	after.Digest = frequency
+	return audit.Record(ctx, q, account, audit.Entry{
+		Action:     action,
+		TargetType: audit.TargetAccount,
+		TargetID:   account,
+		Before:     before,
+		After:      after,
+	})
+}
+
+// emailState is the snapshot of the email settings of an account recorded in
+// the audit log.
+type emailState struct {
+	Email    *string `json:"email"`
+	Verified bool    `json:"verified"`
+	Digest   string  `json:"digest"`
+}
+
+func emailStateOf(user *sqlc.Users) emailState {
+	return emailState{
+		Email:    nullString(user.Email),
+		Verified: user.EmailVerifiedAt.Valid,
+		Digest:   user.Digest,
+	}
+}
+
+func nullString(s sql.NullString) *string {
+	if !s.Valid {
+		return nil
+	}
+	return &s.String
+}
+
+func (s *RPC) emailSettings(ctx context.Context, account string) (*proto.EmailSettings, error) {
+	user, err := data.DB.GetUser(ctx, account)
+	if err != nil {
+		return nil, s.dbError(ctx, err)
+	}
+	settings := &proto.EmailSettings{
+		Verified: user.EmailVerifiedAt.Valid,
+		Digest:   user.Digest,
+	}
+	if user.Email.Valid {
+		settings.Email = &user.Email.String
+	}
+	return settings, nil
+}
+
+var linkPage = template.Must(template.New("link").Parse(`<!DOCTYPE html>
+<html><body style="font-family: sans-serif;">
+<p>{{.Message}}</p>
+{{if .Action}}<form method="post" action="{{.Action}}"><button type="submit">Unsubscribe</button></form>{{end}}
+</body></html>`))
+
+func writeLinkPage(w http.ResponseWriter, status int, message, action string) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	linkPage.Execute(w, map[string]string{"Message": message, "Action": action})
+}
+
+// handleVerifyEmail verifies an email address from the signed link mailed to
+// it.
+func (s *RPC) handleVerifyEmail(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if err := s.Links.Verify(emails.VerifyPath, q); err != nil {
+		if errors.Is(err, links.ErrExpired) {
+			writeLinkPage(w, http.StatusGone, "This link has expired, please set your email address again to get a new one.", "")
+			return
+		}
+		writeLinkPage(w, http.StatusBadRequest, "This link is invalid.", "")
+		return
+	}
+
+	ctx := r.Context()
+	account := q.Get("account")
+	err := data.WithTx(ctx, pgx.TxOptions{}, func(tx *sqlc.Queries) error {
+		user, err := tx.GetUser(ctx, account)
+		if errors.Is(err, data.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		n, err := tx.VerifyUserEmail(ctx, sqlc.VerifyUserEmailParams{
+			Addr:  account,
+			Email: sql.NullString{String: q.Get("email"), Valid: true},
+		})
+		if err != nil || n == 0 {
+			return err
+		}
+
+		before := emailStateOf(&user)
+		after := before
+		after.Verified = true
+		return audit.Record(ctx, tx, account, audit.Entry{
+			Action:     audit.ActionVerifyEmail,
+			TargetType: audit.TargetAccount,
+			TargetID:   account,
+			Before:     before,
+			After:      after,
+		})
+	})
+	if err != nil {
+		s.GetLogger(r.Context()).Error().Err(err).Msg("failed to verify email")
+		writeLinkPage(w, http.StatusInternalServerError, "Something went wrong, please try again later.", "")
+		return
+	}
+	writeLinkPage(w, http.StatusOK, "Your email address is verified.", "")
+}
+
+// handleUnsubscribePage asks to confirm unsubscribing, as mail scanners open
+// the links of emails.
+func (s *RPC) handleUnsubscribePage(w http.ResponseWriter, r *http.Request) {
+	if err := s.Links.Verify(emails.UnsubscribePath, r.URL.Query()); err != nil {
+		writeLinkPage(w, http.StatusBadRequest, "This link is invalid.", "")
+		return
+	}
+	writeLinkPage(w, http.StatusOK, "Unsubscribe from the nfteseum digest?", r.URL.RequestURI())
+}
+
+// handleUnsubscribe turns off the digest of the account of a signed link,
+// either from the page above or from the mail client, see RFC 8058.
+func (s *RPC) handleUnsubscribe(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if err := s.Links.Verify(emails.UnsubscribePath, q); err != nil || q.Get("list") != "digest" {
+		writeLinkPage(w, http.StatusBadRequest, "This link is invalid.", "")
+		return
+	}
+
+	ctx := r.Context()
+	err := data.WithTx(ctx, pgx.TxOptions{}, func(tx *sqlc.Queries) error {
+		user, err := tx.GetUser(ctx, q.Get("account"))
+		if errors.Is(err, data.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return setDigest(ctx, tx, &user, emails.DigestOff, audit.ActionUnsubscribe)
+	})
+	if err != nil {
+		s.GetLogger(r.Context()).Error().Err(err).Msg("failed to unsubscribe")
+		writeLinkPage(w, http.StatusInternalServerError, "Something went wrong, please try again later.", "")
+		return
+	}
+	writeLinkPage(w, http.StatusOK, "You're unsubscribed from the nfteseum digest.", "")
+}
@@ -0,0 +1,103 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/proto"
+)
+
+// constraintError describes how a violation of a named database constraint
+// is reported back to the client.
+type constraintError struct {
+	code  proto.ErrorCode
+	field string
+	msg   string
+}
+
+// constraintErrors maps constraint names from data/migrations to rpc errors.
+// Postgres names constraints "<table>_<column>_<suffix>" unless specified.
+var constraintErrors = map[string]constraintError{
+	"users_pkey":              {proto.ErrAlreadyExists, "addr", "is already registered"},
+	"users_addr_key":          {proto.ErrAlreadyExists, "addr", "is already registered"},
+	"url_check":               {proto.ErrInvalidArgument, "pfp", "must be a valid http(s) url"},
+	"posts_author_fkey":       {proto.ErrNotFound, "author", "user does not exist"},
+	"comments_post_id_fkey":   {proto.ErrNotFound, "postID", "post does not exist"},
+	"comments_author_fkey":    {proto.ErrNotFound, "author", "user does not exist"},
+	"likes_post_id_fkey":      {proto.ErrNotFound, "postID", "post does not exist"},
+	"likes_liked_by_fkey":     {proto.ErrNotFound, "likedBy", "user does not exist"},
+	"comments_parent_id_fkey": {proto.ErrNotFound, "parentID", "comment does not exist"},
+	"follows_follower_fkey":   {proto.ErrNotFound, "follower", "user does not exist"},
+	"follows_followee_fkey":   {proto.ErrNotFound, "followee", "user does not exist"},
+	"follows_self_check":      {proto.ErrInvalidArgument, "followee", "cannot be yourself"},
+
+	"notification_preferences_account_fkey": {proto.ErrNotFound, "account", "user does not exist"},
+	"push_subscriptions_account_fkey":       {proto.ErrNotFound, "account", "user does not exist"},
+	"webhooks_owner_fkey":                   {proto.ErrNotFound, "owner", "user does not exist"},
+	"reports_reporter_fkey":                 {proto.ErrNotFound, "reporter", "user does not exist"},
+	"user_blocks_blocker_fkey":              {proto.ErrNotFound, "account", "user does not exist"},
+	"user_blocks_blocked_fkey":              {proto.ErrNotFound, "account", "user does not exist"},
+	"user_blocks_self_check":                {proto.ErrInvalidArgument, "account", "cannot be yourself"},
+}
+
+// dbError translates an error returned by the data layer into a webrpc error,
+// so constraint failures surface as client errors instead of a generic 500.
+// Errors which are not understood are logged and reported as internal.
+func (s *RPC) dbError(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var rpcErr proto.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+
+	switch {
+	case errors.Is(err, data.ErrNoRows):
+		return proto.ErrorNotFound("not found")
+	case errors.Is(err, data.ErrNotConnected):
+		return proto.Errorf(proto.ErrUnavailable, "database is unavailable")
+	case errors.Is(err, context.Canceled):
+		return proto.Errorf(proto.ErrCanceled, "request canceled")
+	case errors.Is(err, context.DeadlineExceeded):
+		return proto.Errorf(proto.ErrDeadlineExceeded, "request timed out")
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		if e, ok := constraintErrors[pgErr.ConstraintName]; ok {
+			return fieldError(e.code, e.field, e.msg)
+		}
+
+		switch pgErr.Code {
+		case pgerrcode.UniqueViolation:
+			return fieldError(proto.ErrAlreadyExists, pgErr.ColumnName, "already exists")
+		case pgerrcode.ForeignKeyViolation:
+			return fieldError(proto.ErrNotFound, pgErr.ColumnName, "references a record which does not exist")
+		case pgerrcode.NotNullViolation:
+			return proto.ErrorRequiredArgument(pgErr.ColumnName)
+		case pgerrcode.CheckViolation:
+			return fieldError(proto.ErrInvalidArgument, pgErr.ColumnName, "is invalid")
+		case pgerrcode.StringDataRightTruncationDataException:
+			return fieldError(proto.ErrInvalidArgument, pgErr.ColumnName, "is too long")
+		case pgerrcode.InvalidTextRepresentation, pgerrcode.NumericValueOutOfRange:
+			return fieldError(proto.ErrInvalidArgument, pgErr.ColumnName, "is malformed")
+		case pgerrcode.SerializationFailure, pgerrcode.DeadlockDetected:
+			return proto.Errorf(proto.ErrAborted, "conflicting concurrent update, please retry")
+		}
+	}
+
+	s.GetLogger(ctx).Error().Err(err).Msg("rpc: unhandled database error")
+	return proto.ErrorInternal("database error")
+}
+
+func fieldError(code proto.ErrorCode, field, msg string) error {
+	if field == "" {
+		return proto.Errorf(code, msg)
+	}
+	return proto.Errorf(code, "%s %s", field, msg)
+}
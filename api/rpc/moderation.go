@@ -0,0 +1,286 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/chain"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/nfteseum/nfteseum-learning-project/api/moderation"
+	"github.com/nfteseum/nfteseum-learning-project/api/proto"
+)
+
+const (
+	maxReportDetailsLength = 1000
+
+	defaultReportsLimit = 50
+	maxReportsLimit     = 200
+)
+
+// ReportContent reports a post, comment or user to the moderators for
+// reason, one of "spam", "harassment", "hate", "violence", "sexual",
+// "impersonation" or "other".
+func (s *RPC) ReportContent(ctx context.Context, targetType string, targetID string, reason string, details *string) (*proto.Report, error) {
+	account, err := s.activeAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if targetID == "" {
+		return nil, proto.ErrorRequiredArgument("targetID")
+	}
+	target, err := reportTarget(targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if !moderation.ValidReason(reason) {
+		return nil, proto.ErrorInvalidArgument("reason", fmt.Sprintf("must be one of %s", strings.Join(moderation.Reasons, ", ")))
+	}
+	var text string
+	if details != nil {
+		text = strings.TrimSpace(*details)
+		if utf8.RuneCountInString(text) > maxReportDetailsLength {
+			return nil, proto.ErrorInvalidArgument("details", fmt.Sprintf("must be at most %d characters long", maxReportDetailsLength))
+		}
+	}
+
+	report, err := moderation.Report(ctx, account, target, reason, text)
+	switch {
+	case errors.Is(err, moderation.ErrAlreadyReported):
+		return nil, proto.Errorf(proto.ErrAlreadyExists, "already reported, the report is being reviewed")
+	case errors.Is(err, moderation.ErrSelfReport):
+		return nil, proto.ErrorInvalidArgument("targetID", "cannot be yourself")
+	case errors.Is(err, moderation.ErrInvalidTarget):
+		return nil, proto.ErrorInvalidArgument("targetID", "is malformed")
+	case errors.Is(err, data.ErrNoRows):
+		return nil, proto.ErrorNotFound(target.Type + " not found")
+	case err != nil:
+		return nil, s.dbError(ctx, err)
+	}
+	return reportFromRow(report), nil
+}
+
+// ListReports is the moderation queue: the reports of a status, "open" by
+// default, latest first. The returned cursor fetches the next page, and is
+// empty on the last one. Admins only.
+func (s *RPC) ListReports(ctx context.Context, status *string, targetType *string, targetID *string, reason *string, cursor *string, limit *int32) ([]*proto.Report, string, error) {
+	if _, err := s.adminAccount(ctx); err != nil {
+		return nil, "", err
+	}
+
+	params := sqlc.ListReportsParams{
+		Status:     moderation.StatusOpen,
+		BeforeID:   math.MaxInt64,
+		MaxReports: defaultReportsLimit,
+	}
+	if status != nil {
+		switch *status {
+		case moderation.StatusOpen, moderation.StatusActioned, moderation.StatusDismissed:
+			params.Status = *status
+		default:
+			return nil, "", proto.ErrorInvalidArgument("status", "must be open, actioned or dismissed")
+		}
+	}
+	if targetType != nil {
+		id := ""
+		if targetID != nil {
+			id = *targetID
+		}
+		target, err := reportTarget(*targetType, id)
+		if err != nil {
+			return nil, "", err
+		}
+		params.TargetType, params.TargetID = target.Type, target.ID
+	} else if targetID != nil {
+		return nil, "", proto.ErrorRequiredArgument("targetType")
+	}
+	if reason != nil {
+		if !moderation.ValidReason(*reason) {
+			return nil, "", proto.ErrorInvalidArgument("reason", fmt.Sprintf("must be one of %s", strings.Join(moderation.Reasons, ", ")))
+		}
+		params.Reason = *reason
+	}
+	if limit != nil {
+		if *limit <= 0 || *limit > maxReportsLimit {
+			return nil, "", proto.ErrorInvalidArgument("limit", fmt.Sprintf("must be between 1 and %d", maxReportsLimit))
+		}
+		params.MaxReports = *limit
+	}
+	if cursor != nil && *cursor != "" {
+		var err error
+		params.BeforeID, err = strconv.ParseInt(*cursor, 10, 64)
+		if err != nil {
+			return nil, "", proto.ErrorInvalidArgument("cursor", "is malformed")
+		}
+	}
+
+	rows, err := data.DB.ListReports(ctx, params)
+	if err != nil {
+		return nil, "", s.dbError(ctx, err)
+	}
+	list := make([]*proto.Report, len(rows))
+	for i := range rows {
+		list[i] = reportFromRow(&rows[i])
+	}
+
+	next := ""
+	if len(rows) == int(params.MaxReports) {
+		next = strconv.FormatInt(rows[len(rows)-1].ID, 10)
+	}
+	return list, next, nil
+}
+
+// ModerateContent takes action on a post, comment or user, and resolves its
+// open reports. Posts and comments can be hidden, removed or restored, and
+// users suspended, until suspendedUntil if set, or restored. Any target can
+// be dismissed, resolving its reports without change. The reporters are
+// notified when action is taken. Admins only.
+func (s *RPC) ModerateContent(ctx context.Context, targetType string, targetID string, action string, suspendedUntil *time.Time) (int32, error) {
+	account, err := s.activeAdminAccount(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if targetID == "" {
+		return 0, proto.ErrorRequiredArgument("targetID")
+	}
+	target, err := reportTarget(targetType, targetID)
+	if err != nil {
+		return 0, err
+	}
+	if suspendedUntil != nil {
+		if action != moderation.ActionSuspend {
+			return 0, proto.ErrorInvalidArgument("suspendedUntil", "is only valid when suspending")
+		}
+		if !suspendedUntil.After(time.Now()) {
+			return 0, proto.ErrorInvalidArgument("suspendedUntil", "must be in the future")
+		}
+	}
+
+	n, err := moderation.Moderate(ctx, account, target, action, suspendedUntil)
+	switch {
+	case errors.Is(err, moderation.ErrInvalidAction):
+		return 0, proto.ErrorInvalidArgument("action", fmt.Sprintf("%q does not apply to a %s", action, target.Type))
+	case errors.Is(err, moderation.ErrInvalidTarget):
+		return 0, proto.ErrorInvalidArgument("targetID", "is malformed")
+	case errors.Is(err, data.ErrNoRows):
+		return 0, proto.ErrorNotFound(target.Type + " not found")
+	case err != nil:
+		return 0, s.dbError(ctx, err)
+	}
+
+	s.GetLogger(ctx).Info().Str("moderator", account).Str("target", target.Type+":"+target.ID).Str("action", action).Int("reports", n).Msg("moderated content")
+	return int32(n), nil
+}
+
+// activeAccount returns the account of an authenticated rpc request, or a
+// permission denied error while the account is suspended, which makes it
+// read-only. Every rpc writing on behalf of the account goes through it, but
+// those opting out of what the api sends to the account: turning the digest
+// off, removing push subscriptions and webhooks, marking notifications read,
+// and unblocking and unmuting users.
+func (s *RPC) activeAccount(ctx context.Context) (string, error) {
+	account, err := sessionAccount(ctx)
+	if err != nil {
+		return "", err
+	}
+	user, err := data.DB.GetUser(ctx, account)
+	if errors.Is(err, data.ErrNoRows) {
+		// Left to the foreign keys.
+		return account, nil
+	}
+	if err != nil {
+		return "", s.dbError(ctx, err)
+	}
+	if moderation.Suspended(&user, time.Now().UTC()) {
+		return "", proto.Errorf(proto.ErrPermissionDenied, "account is suspended")
+	}
+	return account, nil
+}
+
+// adminAccount returns the account of an authenticated rpc request made by
+// an admin, or a permission denied error.
+func (s *RPC) adminAccount(ctx context.Context) (string, error) {
+	return s.admin(ctx, false)
+}
+
+// activeAdminAccount is adminAccount for the rpcs acting as the admin, which
+// are also denied to suspended admins, as with activeAccount.
+func (s *RPC) activeAdminAccount(ctx context.Context) (string, error) {
+	return s.admin(ctx, true)
+}
+
+func (s *RPC) admin(ctx context.Context, active bool) (string, error) {
+	account, err := sessionAccount(ctx)
+	if err != nil {
+		return "", err
+	}
+	user, err := data.DB.GetUser(ctx, account)
+	if errors.Is(err, data.ErrNoRows) {
+		return "", proto.Errorf(proto.ErrPermissionDenied, "admins only")
+	}
+	if err != nil {
+		return "", s.dbError(ctx, err)
+	}
+	if !user.Admin.Bool {
+		return "", proto.Errorf(proto.ErrPermissionDenied, "admins only")
+	}
+	if active && moderation.Suspended(&user, time.Now().UTC()) {
+		return "", proto.Errorf(proto.ErrPermissionDenied, "account is suspended")
+	}
+	return account, nil
+}
+
+// reportTarget validates the type and id of a moderation target. Addresses
+// are lowercased, and an empty id only validates the type.
+func reportTarget(targetType, targetID string) (moderation.Target, error) {
+	target := moderation.Target{Type: targetType, ID: targetID}
+	switch targetType {
+	case moderation.TargetPost, moderation.TargetComment:
+		if targetID == "" {
+			return target, nil
+		}
+		if id, err := strconv.ParseInt(targetID, 10, 32); err != nil || id <= 0 {
+			return target, proto.ErrorInvalidArgument("targetID", "must be the id of the "+targetType)
+		}
+	case moderation.TargetUser:
+		target.ID = strings.ToLower(targetID)
+		if targetID != "" && !chain.IsAddress(targetID) {
+			return target, proto.ErrorInvalidArgument("targetID", "must be the address of the user")
+		}
+	default:
+		return target, proto.ErrorInvalidArgument("targetType", "must be post, comment or user")
+	}
+	return target, nil
+}
+
+func reportFromRow(row *sqlc.Reports) *proto.Report {
+	report := &proto.Report{
+		ID:         row.ID,
+		Reporter:   strings.TrimSpace(row.Reporter),
+		TargetType: row.TargetType,
+		TargetID:   row.TargetID,
+		Reason:     row.Reason,
+		Status:     row.Status,
+		CreatedAt:  row.CreatedAt,
+	}
+	if row.Details.Valid {
+		report.Details = &row.Details.String
+	}
+	if row.Action.Valid {
+		report.Action = &row.Action.String
+	}
+	if row.ResolvedBy.Valid {
+		resolvedBy := strings.TrimSpace(row.ResolvedBy.String)
+		report.ResolvedBy = &resolvedBy
+	}
+	if row.ResolvedAt.Valid {
+		report.ResolvedAt = &row.ResolvedAt.Time
+	}
+	return report
+}
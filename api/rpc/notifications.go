@@ -0,0 +1,160 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/nfteseum/nfteseum-learning-project/api/notifications"
+	"github.com/nfteseum/nfteseum-learning-project/api/proto"
+)
+
+const (
+	defaultNotificationsLimit = 20
+	maxNotificationsLimit     = 100
+	maxMarkedNotifications    = 500
+)
+
+// ListNotifications returns the notifications of the account, most recently
+// active first. The returned cursor fetches the next page, and is empty on
+// the last one.
+func (s *RPC) ListNotifications(ctx context.Context, cursor *string, limit *int32) ([]*proto.Notification, string, error) {
+	account, err := sessionAccount(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	n := int32(defaultNotificationsLimit)
+	if limit != nil {
+		if *limit <= 0 || *limit > maxNotificationsLimit {
+			return nil, "", proto.ErrorInvalidArgument("limit", fmt.Sprintf("must be between 1 and %d", maxNotificationsLimit))
+		}
+		n = *limit
+	}
+
+	params := sqlc.ListNotificationsParams{
+		Recipient:        account,
+		BeforeUpdatedAt:  time.Date(9999, 1, 1, 0, 0, 0, 0, time.UTC),
+		BeforeID:         math.MaxInt64,
+		MaxNotifications: n,
+	}
+	if cursor != nil && *cursor != "" {
+		var micros int64
+		if _, err := fmt.Sscanf(*cursor, "%d-%d", &micros, &params.BeforeID); err != nil {
+			return nil, "", proto.ErrorInvalidArgument("cursor", "is malformed")
+		}
+		params.BeforeUpdatedAt = time.UnixMicro(micros).UTC()
+	}
+
+	rows, err := data.DB.ListNotifications(ctx, params)
+	if err != nil {
+		return nil, "", s.dbError(ctx, err)
+	}
+
+	list := make([]*proto.Notification, len(rows))
+	for i, row := range rows {
+		list[i] = &proto.Notification{
+			ID:         row.ID,
+			Kind:       row.Kind,
+			Actors:     row.Actors,
+			ActorCount: row.ActorCount,
+			Read:       row.ReadAt.Valid,
+			CreatedAt:  row.CreatedAt,
+			UpdatedAt:  row.UpdatedAt,
+		}
+		if row.PostID.Valid {
+			list[i].PostID = &rows[i].PostID.Int32
+		}
+		if row.CommentID.Valid {
+			list[i].CommentID = &rows[i].CommentID.Int32
+		}
+	}
+
+	next := ""
+	if len(rows) == int(n) {
+		last := rows[len(rows)-1]
+		next = fmt.Sprintf("%d-%d", last.UpdatedAt.UnixMicro(), last.ID)
+	}
+	return list, next, nil
+}
+
+// MarkNotificationsRead marks the given notifications of the account as
+// read, or all of them, and returns how many were unread. Suspended accounts
+// can too, as it only changes what they read.
+func (s *RPC) MarkNotificationsRead(ctx context.Context, ids []int64, all bool) (int64, error) {
+	account, err := sessionAccount(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int64
+	switch {
+	case all:
+		n, err = data.DB.MarkAllNotificationsRead(ctx, account)
+	case len(ids) == 0:
+		return 0, proto.ErrorRequiredArgument("ids")
+	case len(ids) > maxMarkedNotifications:
+		return 0, proto.ErrorInvalidArgument("ids", fmt.Sprintf("must hold at most %d ids", maxMarkedNotifications))
+	default:
+		n, err = data.DB.MarkNotificationsRead(ctx, sqlc.MarkNotificationsReadParams{Recipient: account, Ids: ids})
+	}
+	if err != nil {
+		return 0, s.dbError(ctx, err)
+	}
+	return n, nil
+}
+
+// GetUnreadCount returns the number of unread notifications of the account.
+func (s *RPC) GetUnreadCount(ctx context.Context) (int64, error) {
+	account, err := sessionAccount(ctx)
+	if err != nil {
+		return 0, err
+	}
+	n, err := data.DB.CountUnreadNotifications(ctx, account)
+	if err != nil {
+		return 0, s.dbError(ctx, err)
+	}
+	return n, nil
+}
+
+func (s *RPC) GetNotificationPreferences(ctx context.Context) (*proto.NotificationPreferences, error) {
+	account, err := sessionAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	prefs, err := notifications.GetPreferences(ctx, account)
+	if err != nil {
+		return nil, s.dbError(ctx, err)
+	}
+	return &proto.NotificationPreferences{
+		Likes:    prefs.Likes,
+		Comments: prefs.Comments,
+		Replies:  prefs.Replies,
+		Follows:  prefs.Follows,
+		Mentions: prefs.Mentions,
+	}, nil
+}
+
+func (s *RPC) UpdateNotificationPreferences(ctx context.Context, preferences *proto.NotificationPreferences) (*proto.NotificationPreferences, error) {
+	account, err := s.activeAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if preferences == nil {
+		return nil, proto.ErrorRequiredArgument("preferences")
+	}
+	err = notifications.SavePreferences(ctx, account, &notifications.Preferences{
+		Likes:    preferences.Likes,
+		Comments: preferences.Comments,
+		Replies:  preferences.Replies,
+		Follows:  preferences.Follows,
+		Mentions: preferences.Mentions,
+	})
+	if err != nil {
+		return nil, s.dbError(ctx, err)
+	}
+	return preferences, nil
+}
@@ -0,0 +1,97 @@
+package rpc
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/config"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/nfteseum/nfteseum-learning-project/api/proto"
+	"github.com/nfteseum/nfteseum-learning-project/api/push"
+)
+
+// maxPushSubscriptionsPerAccount caps the browsers subscribed by an account.
+const maxPushSubscriptionsPerAccount = 10
+
+// GetPushPublicKey returns the VAPID public key browsers subscribe with, as
+// their applicationServerKey.
+func (s *RPC) GetPushPublicKey(ctx context.Context) (string, error) {
+	if !push.Enabled(s.Config) {
+		return "", proto.Errorf(proto.ErrUnimplemented, "web push is not enabled")
+	}
+	return s.Config.Push.VAPIDPublicKey, nil
+}
+
+// RegisterPushSubscription saves the push subscription of a browser, to
+// receive the notifications of the account.
+func (s *RPC) RegisterPushSubscription(ctx context.Context, subscription *proto.PushSubscription) (bool, error) {
+	account, err := s.activeAccount(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !push.Enabled(s.Config) {
+		return false, proto.Errorf(proto.ErrUnimplemented, "web push is not enabled")
+	}
+	if subscription == nil {
+		return false, proto.ErrorRequiredArgument("subscription")
+	}
+	if !s.validPushEndpoint(subscription.Endpoint) {
+		return false, proto.ErrorInvalidArgument("endpoint", "must be an https url")
+	}
+	if err := push.ValidateKeys(subscription.P256dh, subscription.Auth); err != nil {
+		return false, proto.ErrorInvalidArgument("keys", err.Error())
+	}
+
+	subs, err := data.DB.ListPushSubscriptions(ctx, account)
+	if err != nil {
+		return false, s.dbError(ctx, err)
+	}
+	if len(subs) >= maxPushSubscriptionsPerAccount {
+		known := false
+		for _, sub := range subs {
+			known = known || sub.Endpoint == subscription.Endpoint
+		}
+		if !known {
+			return false, proto.Errorf(proto.ErrResourceExhausted, "too many push subscriptions, remove one first")
+		}
+	}
+
+	_, err = data.DB.SavePushSubscription(ctx, sqlc.SavePushSubscriptionParams{
+		Account:  account,
+		Endpoint: subscription.Endpoint,
+		P256dh:   subscription.P256dh,
+		Auth:     subscription.Auth,
+	})
+	if err != nil {
+		return false, s.dbError(ctx, err)
+	}
+	return true, nil
+}
+
+// RemovePushSubscription deletes the push subscription of a browser, ie.
+// when the user turns notifications off. Suspended accounts can too.
+func (s *RPC) RemovePushSubscription(ctx context.Context, endpoint string) (bool, error) {
+	account, err := sessionAccount(ctx)
+	if err != nil {
+		return false, err
+	}
+	n, err := data.DB.DeletePushSubscription(ctx, sqlc.DeletePushSubscriptionParams{
+		Account:  account,
+		Endpoint: endpoint,
+	})
+	if err != nil {
+		return false, s.dbError(ctx, err)
+	}
+	return n > 0, nil
+}
+
+// validPushEndpoint only accepts https push services, or any local stand-in
+// in development mode.
+func (s *RPC) validPushEndpoint(endpoint string) bool {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" || len(endpoint) > 1024 {
+		return false
+	}
+	return u.Scheme == "https" || (u.Scheme == "http" && s.Config.Mode == config.DevelopmentMode)
+}
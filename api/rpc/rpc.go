@@ -3,6 +3,7 @@ package rpc
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"sync/atomic"
 	"time"
@@ -12,22 +13,47 @@ import (
 	"github.com/go-chi/cors"
 	"github.com/go-chi/httplog"
 	"github.com/go-chi/httprate"
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/nfteseum/nfteseum-learning-project/api/audit"
+	"github.com/nfteseum/nfteseum-learning-project/api/bus"
+	"github.com/nfteseum/nfteseum-learning-project/api/chain"
+	"github.com/nfteseum/nfteseum-learning-project/api/chat"
 	"github.com/nfteseum/nfteseum-learning-project/api/config"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/emails"
+	"github.com/nfteseum/nfteseum-learning-project/api/health"
+	"github.com/nfteseum/nfteseum-learning-project/api/jobs"
+	"github.com/nfteseum/nfteseum-learning-project/api/links"
+	"github.com/nfteseum/nfteseum-learning-project/api/metrics"
 	"github.com/nfteseum/nfteseum-learning-project/api/proto"
+	"github.com/nfteseum/nfteseum-learning-project/api/spam"
+	"github.com/nfteseum/nfteseum-learning-project/api/tracing"
+	"github.com/nfteseum/nfteseum-learning-project/api/webhooks"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog"
 )
 
 type RPC struct {
-	Config *config.Config
-	Log    zerolog.Logger
+	Config   *config.Config
+	Log      zerolog.Logger
+	Health   *health.Health
+	Metrics  *prometheus.Registry
+	Bus      bus.Bus
+	Chat     *chat.Hub
+	Jobs     *jobs.Queue
+	Links    *links.Signer
+	Webhooks *webhooks.Client
+	Spam     *spam.Filter
+	JWTAuth  *jwtauth.JWTAuth
 
 	HTTP *http.Server
 
+	streams   *streams
 	running   int32
 	startTime time.Time
 }
 
-func NewRPC(cfg *config.Config, logger zerolog.Logger) (*RPC, error) {
+func NewRPC(cfg *config.Config, logger zerolog.Logger, hc *health.Health, reg *prometheus.Registry, b bus.Bus, hub *chat.Hub, queue *jobs.Queue) (*RPC, error) {
 	httpServer := &http.Server{
 		Addr:              cfg.Service.Listen,
 		ReadTimeout:       45 * time.Second,
@@ -35,10 +61,29 @@ func NewRPC(cfg *config.Config, logger zerolog.Logger) (*RPC, error) {
 		IdleTimeout:       45 * time.Second,
 		ReadHeaderTimeout: 5 * time.Second,
 	}
+	hooks, err := webhooks.NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := spam.NewFilter(cfg, logger, chain.NewReader(cfg))
+	if err != nil {
+		return nil, err
+	}
+
 	s := &RPC{
-		Config: cfg,
-		Log:    logger.With().Str("ps", "rpc").Logger(),
-		HTTP:   httpServer,
+		Config:   cfg,
+		Log:      logger.With().Str("ps", "rpc").Logger(),
+		Health:   hc,
+		Metrics:  reg,
+		Bus:      b,
+		Chat:     hub,
+		Jobs:     queue,
+		Links:    links.New(cfg),
+		Webhooks: hooks,
+		Spam:     filter,
+		JWTAuth:  jwtauth.New("HS256", []byte(cfg.Auth.JWTSecret), nil),
+		HTTP:     httpServer,
+		streams:  newStreams(),
 	}
 	return s, nil
 }
@@ -82,6 +127,10 @@ func (s *RPC) Stop(timeoutCtx context.Context) {
 	atomic.StoreInt32(&s.running, 2)
 
 	s.Log.Info().Str("op", "stop").Msg("-> rpc: stopping..")
+	// Event streams and chat sockets never go idle, and Shutdown doesn't
+	// track hijacked connections, so close them first.
+	s.streams.close()
+	s.Chat.Close(timeoutCtx)
 	s.HTTP.Shutdown(timeoutCtx)
 	s.Log.Info().Str("op", "stop").Msg("-> rpc: stopped.")
 }
@@ -97,17 +146,30 @@ func (s *RPC) IsStopping() bool {
 func (s *RPC) handler() http.Handler {
 	r := chi.NewRouter()
 
+	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
+	r.Use(audit.Middleware)
+	r.Use(metrics.HTTP)
+	r.Use(dbSession)
 	r.Use(middleware.NoCache)
 	// r.Use(honeybadger.Handler)
 	r.Use(middleware.Heartbeat("/ping"))
+	// Liveness and readiness probes
+	r.Use(middleware.PageRoute("/healthz", s.Health.LiveHandler()))
+	r.Use(middleware.PageRoute("/readyz", s.Health.ReadyHandler()))
+	// Metrics, unless served from the internal listener
+	if s.Metrics != nil && s.Config.Metrics.Listen == "" {
+		r.Use(middleware.PageRoute("/metrics", metrics.Handler(s.Metrics)))
+	}
 	// HTTP request logger
 	r.Use(httplog.RequestLogger(s.Log))
-	// Timeout any request after 28 seconds as Cloudflare has a 30 second limit anyways.
-	r.Use(middleware.Timeout(28 * time.Second))
+	r.Use(tracing.LogTraceID)
 
 	// Rate limiting
-	r.Use(httprate.LimitByIP(200, 1*time.Minute))
+	r.Use(httprate.Limit(200, 1*time.Minute,
+		httprate.WithKeyFuncs(httprate.KeyByIP),
+		httprate.WithLimitHandler(metrics.RateLimited),
+	))
 	// CORS
 	r.Use(s.corsHandler())
 
@@ -115,20 +177,31 @@ func (s *RPC) handler() http.Handler {
 	r.Use(middleware.PageRoute("/", http.HandlerFunc(indexHandler)))
 	r.Use(middleware.PageRoute("/favicon.ico", http.HandlerFunc(stubHandler(""))))
 
-	// // Seek and verify JWT tokens, and put on request context
-	// r.Use(jwtauth.Verifier(s.API.JWTAuth))
+	// Seek and verify JWT tokens, and put on request context
+	r.Use(jwtauth.Verify(s.JWTAuth, jwtauth.TokenFromHeader, jwtauth.TokenFromCookie))
 
-	// // Session middleware
-	// r.Use(rpcmw.Session)
+	// Session middleware
+	r.Use(session)
 
 	// // Access control
 	// r.Use(rpcmw.AccessControl)
 	// Mount rpc endpoints
 	rpcHandler := proto.NewAPIServer(s)
 	// r.Handle("/rpc/ArcadeumAPI/*", chi.Chain(middleware.PathRewrite("/rpc/ArcadeumAPI/", "/rpc/API/")).Handler(rpcHandler))
-	r.Post("/rpc/*", rpcHandler.ServeHTTP)
+	// Timeout any request after 28 seconds as Cloudflare has a 30 second limit anyways.
+	r.With(middleware.Timeout(28*time.Second), metrics.RPC, tracing.RPC, requireDB).Post("/rpc/*", rpcHandler.ServeHTTP)
+
+	// Live updates, streamed for longer than the timeout above
+	r.With(requireAccount, requireConnected).Get("/events", s.handleEvents)
+	r.With(requireAccount, requireConnected).Get("/chat/{contract}", s.handleChat)
 
-	return r
+	// Signed links sent by email
+	r.With(requireConnected).Get(emails.VerifyPath, s.handleVerifyEmail)
+	r.With(requireConnected).Get(emails.UnsubscribePath, s.handleUnsubscribePage)
+	r.With(requireConnected).Post(emails.UnsubscribePath, s.handleUnsubscribe)
+
+	// Trace every request, continuing the caller's trace if there's one
+	return tracing.HTTP(r)
 }
 
 func (s *RPC) corsHandler() func(next http.Handler) http.Handler {
@@ -180,6 +253,52 @@ func (s *RPC) corsHandler() func(next http.Handler) http.Handler {
 		Handler
 }
 
+// dbIndependentMethods are the rpc methods which keep working while the
+// database is unreachable.
+var dbIndependentMethods = map[string]bool{
+	"/rpc/API/Ping":    true,
+	"/rpc/API/Version": true,
+}
+
+// requireDB fails rpc requests fast with ErrUnavailable while the database is
+// disconnected, instead of letting them wait on the connection pool.
+func requireDB(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !data.IsConnected() && !dbIndependentMethods[r.URL.Path] {
+			proto.RespondWithError(w, proto.Errorf(proto.ErrUnavailable, "database is unavailable, please try again later"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireConnected is requireDB for plain http endpoints.
+func requireConnected(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !data.IsConnected() {
+			http.Error(w, "database is unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// dbSession tags the request context with the client ip, so reads made
+// shortly after the client wrote to the database are served by the primary
+// rather than a possibly lagging replica. The session of authenticated
+// requests is then keyed by their account instead, see session.
+func dbSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Keyed by ip alone, as the port changes with every connection.
+		ip := r.RemoteAddr
+		if host, _, err := net.SplitHostPort(ip); err == nil {
+			ip = host
+		}
+		ctx := data.WithSession(r.Context(), ip)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 func indexHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte("."))
 }
@@ -0,0 +1,231 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/nfteseum/nfteseum-learning-project/api/proto"
+	"github.com/nfteseum/nfteseum-learning-project/api/social"
+)
+
+// Types of search hits.
+const (
+	hitUser    = "user"
+	hitPost    = "post"
+	hitComment = "comment"
+)
+
+const (
+	maxSearchQueryLength = 100
+
+	// maxSearchTerms caps the words of a query, which must all match.
+	maxSearchTerms = 8
+
+	// maxSearchOffset caps how deep results can be paged, as pages are
+	// ranked again each time.
+	maxSearchOffset = 1000
+
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+)
+
+// Search returns the users, posts and comments matching query, best first.
+// Every word of the query must match, as a prefix so results can be shown
+// while typing. Types restricts the hits to some of "user", "post" and
+// "comment". The returned cursor fetches the next page, and is empty on the
+// last one.
+func (s *RPC) Search(ctx context.Context, query string, types []string, cursor *string, limit *int32) ([]*proto.SearchHit, string, error) {
+	if utf8.RuneCountInString(query) > maxSearchQueryLength {
+		return nil, "", proto.ErrorInvalidArgument("query", fmt.Sprintf("must be at most %d characters long", maxSearchQueryLength))
+	}
+	tsquery := searchQuery(query)
+	if tsquery == "" {
+		return nil, "", proto.ErrorRequiredArgument("query")
+	}
+	kinds, err := searchKinds(types)
+	if err != nil {
+		return nil, "", err
+	}
+
+	n := int32(defaultSearchLimit)
+	if limit != nil {
+		if *limit <= 0 || *limit > maxSearchLimit {
+			return nil, "", proto.ErrorInvalidArgument("limit", fmt.Sprintf("must be between 1 and %d", maxSearchLimit))
+		}
+		n = *limit
+	}
+	offset := int32(0)
+	if cursor != nil && *cursor != "" {
+		o, err := strconv.ParseInt(*cursor, 10, 32)
+		if err != nil || o < 0 || o > maxSearchOffset {
+			return nil, "", proto.ErrorInvalidArgument("cursor", "is malformed")
+		}
+		offset = int32(o)
+	}
+
+	rows, err := data.DB.Search(ctx, sqlc.SearchParams{
+		Query:    tsquery,
+		Kinds:    kinds,
+		MaxHits:  n,
+		SkipHits: offset,
+	})
+	if err != nil {
+		return nil, "", s.dbError(ctx, err)
+	}
+	hits, err := searchHits(ctx, rows)
+	if err != nil {
+		return nil, "", s.dbError(ctx, err)
+	}
+
+	next := ""
+	if len(rows) == int(n) && offset+n <= maxSearchOffset {
+		next = strconv.FormatInt(int64(offset+n), 10)
+	}
+	return hits, next, nil
+}
+
+// searchQuery returns the to_tsquery expression of a user query: its words,
+// all required, matching as prefixes. Operators and punctuation are dropped,
+// so the expression is always valid.
+func searchQuery(query string) string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(words) > maxSearchTerms {
+		words = words[:maxSearchTerms]
+	}
+	for i, w := range words {
+		words[i] = w + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
+// searchKinds validates the types of hits searched, and dedupes them.
+// Searches return all types by default.
+func searchKinds(types []string) ([]string, error) {
+	if len(types) == 0 {
+		return []string{hitUser, hitPost, hitComment}, nil
+	}
+	kinds := make([]string, 0, len(types))
+	seen := map[string]bool{}
+	for _, t := range types {
+		if t != hitUser && t != hitPost && t != hitComment {
+			return nil, proto.ErrorInvalidArgument("types", fmt.Sprintf("unknown type %q", t))
+		}
+		if !seen[t] {
+			seen[t] = true
+			kinds = append(kinds, t)
+		}
+	}
+	return kinds, nil
+}
+
+// searchHits loads the users, posts and comments of the ranked rows, in
+// order. Rows deleted since they were ranked are skipped.
+func searchHits(ctx context.Context, rows []sqlc.SearchRow) ([]*proto.SearchHit, error) {
+	var addrs []string
+	var postIDs, commentIDs []int32
+	for _, row := range rows {
+		switch row.Kind {
+		case hitUser:
+			addrs = append(addrs, row.Key)
+		case hitPost, hitComment:
+			id, err := strconv.ParseInt(row.Key, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("rpc: invalid %s search key %q", row.Kind, row.Key)
+			}
+			if row.Kind == hitPost {
+				postIDs = append(postIDs, int32(id))
+			} else {
+				commentIDs = append(commentIDs, int32(id))
+			}
+		}
+	}
+
+	users := map[string]*proto.User{}
+	if len(addrs) > 0 {
+		list, err := data.DB.ListUsersByAddr(ctx, addrs)
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range list {
+			user := &proto.User{Addr: strings.TrimSpace(u.Addr), Name: u.Name}
+			if u.Pfp != "" {
+				pfp := u.Pfp
+				user.Pfp = &pfp
+			}
+			users[user.Addr] = user
+		}
+	}
+
+	posts := map[string]*proto.Post{}
+	if len(postIDs) > 0 {
+		list, err := data.DB.ListPostsByID(ctx, postIDs)
+		if err != nil {
+			return nil, err
+		}
+		for i := range list {
+			posts[strconv.FormatInt(int64(list[i].ID), 10)] = postWithMetadata(&list[i])
+		}
+	}
+
+	comments := map[string]*proto.Comment{}
+	if len(commentIDs) > 0 {
+		list, err := social.GetComments(ctx, commentIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range list {
+			comments[strconv.FormatInt(int64(c.ID), 10)] = commentFromSocial(c)
+		}
+	}
+
+	hits := make([]*proto.SearchHit, 0, len(rows))
+	for _, row := range rows {
+		hit := &proto.SearchHit{Type: row.Kind, Rank: row.Rank}
+		switch row.Kind {
+		case hitUser:
+			hit.User = users[row.Key]
+		case hitPost:
+			hit.Post = posts[row.Key]
+		case hitComment:
+			hit.Comment = comments[row.Key]
+		}
+		if hit.User == nil && hit.Post == nil && hit.Comment == nil {
+			continue
+		}
+		hits = append(hits, hit)
+	}
+	return hits, nil
+}
+
+func postWithMetadata(row *sqlc.ListPostsByIDRow) *proto.Post {
+	post := postFromRow(&sqlc.Posts{
+		ID:           row.ID,
+		ContractAddr: row.ContractAddr,
+		TokenID:      row.TokenID,
+		LikeCount:    row.LikeCount,
+		CommentCount: row.CommentCount,
+		Author:       row.Author,
+		CreatedAt:    row.CreatedAt,
+	})
+	if row.Name.Valid || row.Description.Valid || row.Image.Valid {
+		post.Metadata = &proto.TokenMetadata{}
+		if row.Name.Valid {
+			post.Metadata.Name = &row.Name.String
+		}
+		if row.Description.Valid {
+			post.Metadata.Description = &row.Description.String
+		}
+		if row.Image.Valid {
+			post.Metadata.Image = &row.Image.String
+		}
+	}
+	return post
+}
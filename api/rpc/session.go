@@ -0,0 +1,77 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/jwtauth/v5"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/proto"
+)
+
+type contextKey struct {
+	name string
+}
+
+func (k *contextKey) String() string {
+	return "rpc context value " + k.name
+}
+
+var (
+	accountCtxKey = &contextKey{"Account"}
+)
+
+// session puts the account of the verified jwt, as issued by util-jwt, on the
+// request context. Requests without a token are anonymous, while requests
+// with an invalid or expired token are rejected.
+func session(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, claims, err := jwtauth.FromContext(r.Context())
+		if err != nil {
+			if errors.Is(err, jwtauth.ErrNoTokenFound) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		ctx := r.Context()
+		if account, _ := claims["account"].(string); token != nil && account != "" {
+			account = strings.ToLower(account)
+			ctx = context.WithValue(ctx, accountCtxKey, account)
+			ctx = data.WithSession(ctx, account)
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// requireAccount rejects anonymous requests to plain http endpoints.
+func requireAccount(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := AccountFromContext(r.Context()); !ok {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AccountFromContext returns the lowercase wallet address of the
+// authenticated account making the request.
+func AccountFromContext(ctx context.Context) (string, bool) {
+	account, ok := ctx.Value(accountCtxKey).(string)
+	return account, ok
+}
+
+// sessionAccount returns the account of an authenticated rpc request, or an
+// unauthenticated error for anonymous ones.
+func sessionAccount(ctx context.Context) (string, error) {
+	account, ok := AccountFromContext(ctx)
+	if !ok {
+		return "", proto.Errorf(proto.ErrUnauthenticated, "authentication required")
+	}
+	return account, nil
+}
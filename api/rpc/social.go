@@ -0,0 +1,86 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/chain"
+	"github.com/nfteseum/nfteseum-learning-project/api/proto"
+	"github.com/nfteseum/nfteseum-learning-project/api/social"
+)
+
+// LikePost likes a post, and reports whether the like is new.
+func (s *RPC) LikePost(ctx context.Context, postID int32) (bool, error) {
+	account, err := s.activeAccount(ctx)
+	if err != nil {
+		return false, err
+	}
+	liked, err := social.LikePost(ctx, account, postID)
+	if errors.Is(err, social.ErrBlocked) {
+		return false, proto.Errorf(proto.ErrPermissionDenied, "you can't like this post")
+	}
+	if err != nil {
+		return false, s.socialError(ctx, err)
+	}
+	return liked, nil
+}
+
+// UnlikePost removes the like of the account from a post, and reports
+// whether there was one.
+func (s *RPC) UnlikePost(ctx context.Context, postID int32) (bool, error) {
+	account, err := s.activeAccount(ctx)
+	if err != nil {
+		return false, err
+	}
+	unliked, err := social.UnlikePost(ctx, account, postID)
+	if err != nil {
+		return false, s.socialError(ctx, err)
+	}
+	return unliked, nil
+}
+
+// FollowUser follows a user, and reports whether the follow is new.
+func (s *RPC) FollowUser(ctx context.Context, account string) (bool, error) {
+	follower, err := s.activeAccount(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !chain.IsAddress(account) {
+		return false, proto.ErrorInvalidArgument("account", "must be an address")
+	}
+	followed, err := social.FollowUser(ctx, follower, strings.ToLower(account))
+	if errors.Is(err, social.ErrBlocked) {
+		return false, proto.Errorf(proto.ErrPermissionDenied, "you can't follow this user")
+	}
+	if err != nil {
+		return false, s.socialError(ctx, err)
+	}
+	return followed, nil
+}
+
+// UnfollowUser stops following a user, and reports whether the account was
+// following them.
+func (s *RPC) UnfollowUser(ctx context.Context, account string) (bool, error) {
+	follower, err := s.activeAccount(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !chain.IsAddress(account) {
+		return false, proto.ErrorInvalidArgument("account", "must be an address")
+	}
+	unfollowed, err := social.UnfollowUser(ctx, follower, strings.ToLower(account))
+	if err != nil {
+		return false, s.socialError(ctx, err)
+	}
+	return unfollowed, nil
+}
+
+// socialError translates the errors of the social package, and leaves the
+// others to dbError.
+func (s *RPC) socialError(ctx context.Context, err error) error {
+	if errors.Is(err, social.ErrSuspended) {
+		return proto.Errorf(proto.ErrPermissionDenied, "account is suspended")
+	}
+	return s.dbError(ctx, err)
+}
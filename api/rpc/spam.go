@@ -0,0 +1,160 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/nfteseum/nfteseum-learning-project/api/proto"
+	"github.com/nfteseum/nfteseum-learning-project/api/social"
+	"github.com/nfteseum/nfteseum-learning-project/api/spam"
+)
+
+const (
+	maxBlocklistValueLength = 100
+
+	defaultHeldCommentsLimit = 50
+	maxHeldCommentsLimit     = 200
+)
+
+// ListHeldComments returns the comments held by the spam filter, latest
+// first. They're published with ModerateContent restoring them, and taken
+// down by removing them. The returned cursor fetches the next page, and is
+// empty on the last one. Admins only.
+func (s *RPC) ListHeldComments(ctx context.Context, cursor *string, limit *int32) ([]*proto.HeldComment, string, error) {
+	if _, err := s.adminAccount(ctx); err != nil {
+		return nil, "", err
+	}
+	n, before, err := pageArgs(cursor, limit, defaultHeldCommentsLimit, maxHeldCommentsLimit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	rows, err := data.DB.ListHeldComments(ctx, sqlc.ListHeldCommentsParams{BeforeID: before, MaxComments: n})
+	if err != nil {
+		return nil, "", s.dbError(ctx, err)
+	}
+	ids := make([]int32, len(rows))
+	for i, row := range rows {
+		ids[i] = row.CommentID
+	}
+	comments := map[int32]*social.Comment{}
+	if len(ids) > 0 {
+		list, err := social.GetComments(ctx, ids)
+		if err != nil {
+			return nil, "", s.dbError(ctx, err)
+		}
+		for _, c := range list {
+			comments[c.ID] = c
+		}
+	}
+
+	reputations := map[string]int32{}
+	list := make([]*proto.HeldComment, 0, len(rows))
+	for _, row := range rows {
+		c, ok := comments[row.CommentID]
+		if !ok {
+			continue
+		}
+		author := strings.TrimSpace(c.Author)
+		rep, ok := reputations[author]
+		if !ok {
+			rep, err = data.DB.GetReputation(ctx, author)
+			if err != nil && !errors.Is(err, data.ErrNoRows) {
+				return nil, "", s.dbError(ctx, err)
+			}
+			reputations[author] = rep
+		}
+		list = append(list, &proto.HeldComment{
+			Comment:          commentFromSocial(c),
+			Score:            row.Score,
+			Reasons:          row.Reasons,
+			AuthorReputation: rep,
+			HeldAt:           row.CreatedAt,
+		})
+	}
+
+	next := ""
+	if len(rows) == int(n) {
+		next = strconv.FormatInt(int64(rows[len(rows)-1].CommentID), 10)
+	}
+	return list, next, nil
+}
+
+// ListBlocklist returns the words and domains whose comments are held for
+// review. Admins only.
+func (s *RPC) ListBlocklist(ctx context.Context) ([]*proto.BlocklistEntry, error) {
+	if _, err := s.adminAccount(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := data.DB.ListBlocklist(ctx)
+	if err != nil {
+		return nil, s.dbError(ctx, err)
+	}
+	list := make([]*proto.BlocklistEntry, len(rows))
+	for i := range rows {
+		list[i] = blocklistEntryFromRow(&rows[i])
+	}
+	return list, nil
+}
+
+// AddBlocklistEntry adds a word or phrase, as kind "word", or a domain, as
+// kind "url", to the blocklist. Words match whole words, case insensitively,
+// and domains match their subdomains too. Admins only.
+func (s *RPC) AddBlocklistEntry(ctx context.Context, kind string, value string) (*proto.BlocklistEntry, error) {
+	account, err := s.activeAdminAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if kind != spam.BlockWord && kind != spam.BlockURL {
+		return nil, proto.ErrorInvalidArgument("kind", "must be word or url")
+	}
+	if len(value) > maxBlocklistValueLength {
+		return nil, proto.ErrorInvalidArgument("value", fmt.Sprintf("must be at most %d characters long", maxBlocklistValueLength))
+	}
+
+	entry, err := s.Spam.AddEntry(ctx, account, kind, value)
+	switch {
+	case errors.Is(err, spam.ErrInvalidEntry):
+		if kind == spam.BlockURL {
+			return nil, proto.ErrorInvalidArgument("value", "must be a domain or url")
+		}
+		return nil, proto.ErrorInvalidArgument("value", "must hold a word")
+	case errors.Is(err, spam.ErrAlreadyListed):
+		return nil, proto.Errorf(proto.ErrAlreadyExists, "already in the blocklist")
+	case err != nil:
+		return nil, s.dbError(ctx, err)
+	}
+	return blocklistEntryFromRow(entry), nil
+}
+
+// RemoveBlocklistEntry removes an entry from the blocklist, and reports
+// whether it existed. Admins only.
+func (s *RPC) RemoveBlocklistEntry(ctx context.Context, id int32) (bool, error) {
+	account, err := s.activeAdminAccount(ctx)
+	if err != nil {
+		return false, err
+	}
+	if id <= 0 {
+		return false, proto.ErrorInvalidArgument("id", "is malformed")
+	}
+	removed, err := s.Spam.RemoveEntry(ctx, account, id)
+	if err != nil {
+		return false, s.dbError(ctx, err)
+	}
+	return removed, nil
+}
+
+func blocklistEntryFromRow(row *sqlc.Blocklist) *proto.BlocklistEntry {
+	return &proto.BlocklistEntry{
+		ID:        row.ID,
+		Kind:      row.Kind,
+		Value:     row.Value,
+		CreatedBy: strings.TrimSpace(row.CreatedBy),
+		CreatedAt: row.CreatedAt,
+	}
+}
@@ -0,0 +1,262 @@
+package rpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/events"
+)
+
+const (
+	// streamHeartbeat is how often a comment is sent on idle streams, so
+	// proxies don't close them.
+	streamHeartbeat = 15 * time.Second
+
+	// streamRetry is the reconnection delay advertised to clients, in ms.
+	streamRetry = 1000
+
+	// streamReplayLimit caps the events replayed on resume. Clients further
+	// behind receive a "reset" event and should refetch instead.
+	streamReplayLimit = 500
+
+	// maxStreamTopics caps the topics of a single stream.
+	maxStreamTopics = 20
+
+	// maxStreamsPerAccount caps the concurrent streams of an account.
+	maxStreamsPerAccount = 5
+)
+
+// streams tracks the open event streams, to limit them per account and to
+// close them all on shutdown.
+type streams struct {
+	mu        sync.Mutex
+	accounts  map[string]int
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newStreams() *streams {
+	return &streams{accounts: map[string]int{}, done: make(chan struct{})}
+}
+
+func (st *streams) acquire(account string) bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.accounts[account] >= maxStreamsPerAccount {
+		return false
+	}
+	st.accounts[account]++
+	return true
+}
+
+func (st *streams) release(account string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.accounts[account]--
+	if st.accounts[account] <= 0 {
+		delete(st.accounts, account)
+	}
+}
+
+func (st *streams) close() {
+	st.closeOnce.Do(func() { close(st.done) })
+}
+
+// handleEvents streams the events of the requested topics as Server-Sent
+// Events. Topics are given as repeated "topic" query params, either
+// "post:<id>" for the likes and comments of a post, or "notifications" for
+// the activity directed at the account.
+//
+// Events of the users the account blocked or muted are left out.
+//
+// Every event carries its position as id, so clients reconnecting with a
+// Last-Event-ID header get the events they missed replayed. Streams end
+// before the server write timeout, and clients are expected to reconnect.
+func (s *RPC) handleEvents(w http.ResponseWriter, r *http.Request) {
+	account, _ := AccountFromContext(r.Context())
+
+	topics, err := streamTopics(account, r.URL.Query()["topic"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	if !s.streams.acquire(account) {
+		http.Error(w, "too many open streams", http.StatusTooManyRequests)
+		return
+	}
+	defer s.streams.release(account)
+
+	muted, err := data.DB.ListMutedAccounts(r.Context(), account)
+	if err != nil {
+		s.GetLogger(r.Context()).Error().Err(err).Msg("failed to list muted accounts")
+		http.Error(w, "failed to open the stream", http.StatusInternalServerError)
+		return
+	}
+	skip := make(map[string]bool, len(muted))
+	for _, m := range muted {
+		skip[strings.TrimSpace(m)] = true
+	}
+
+	// Subscribe before replaying, so no event falls in between.
+	sub := s.Bus.Subscribe(topics...)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry)
+	flusher.Flush()
+
+	ctx := r.Context()
+	lastTxid, lastID, resume := parseEventID(r.Header.Get("Last-Event-ID"))
+	if resume {
+		evs, err := events.List(ctx, lastTxid, lastID, streamReplayLimit)
+		if err != nil {
+			s.Log.Warn().Err(err).Msg("failed to replay stream events")
+			fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+		} else {
+			for _, ev := range evs {
+				if streamMatch(ev, topics) && !skip[ev.Actor] {
+					if err := writeEvent(w, ev); err != nil {
+						return
+					}
+				}
+				lastTxid, lastID = ev.Txid, ev.ID
+			}
+			if len(evs) == streamReplayLimit {
+				fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+			}
+		}
+		flusher.Flush()
+	}
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	deadline := time.NewTimer(s.streamDuration())
+	defer deadline.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.streams.done:
+			return
+		case <-deadline.C:
+			return
+
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+
+		case msg, ok := <-sub.C():
+			if !ok {
+				// The subscription fell behind, the client resumes from
+				// its last event once it reconnects.
+				return
+			}
+			var ev events.Event
+			if err := json.Unmarshal(msg.Data, &ev); err != nil {
+				continue
+			}
+			if ev.Txid < lastTxid || (ev.Txid == lastTxid && ev.ID <= lastID) {
+				continue
+			}
+			if skip[ev.Actor] {
+				lastTxid, lastID = ev.Txid, ev.ID
+				continue
+			}
+			if err := writeEvent(w, &ev); err != nil {
+				return
+			}
+			lastTxid, lastID = ev.Txid, ev.ID
+			flusher.Flush()
+		}
+	}
+}
+
+// streamDuration keeps streams shorter than the write timeout of the server.
+func (s *RPC) streamDuration() time.Duration {
+	if s.HTTP.WriteTimeout <= 0 {
+		return time.Hour
+	}
+	return s.HTTP.WriteTimeout - 5*time.Second
+}
+
+func streamTopics(account string, requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return nil, fmt.Errorf("topic is required")
+	}
+	if len(requested) > maxStreamTopics {
+		return nil, fmt.Errorf("too many topics, at most %d", maxStreamTopics)
+	}
+
+	topics := make([]string, 0, len(requested))
+	for _, topic := range requested {
+		switch {
+		case topic == "notifications":
+			topics = append(topics, events.UserTopic(account))
+		case strings.HasPrefix(topic, "post:"):
+			id, err := strconv.ParseInt(strings.TrimPrefix(topic, "post:"), 10, 32)
+			if err != nil || id <= 0 {
+				return nil, fmt.Errorf("invalid topic %q", topic)
+			}
+			topics = append(topics, events.PostTopic(int32(id)))
+		default:
+			return nil, fmt.Errorf("invalid topic %q", topic)
+		}
+	}
+	return topics, nil
+}
+
+// streamMatch reports whether ev is published on one of topics.
+func streamMatch(ev *events.Event, topics []string) bool {
+	evTopics, err := events.Topics(ev)
+	if err != nil {
+		return false
+	}
+	for _, t := range evTopics {
+		for _, topic := range topics {
+			if t == topic {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func writeEvent(w http.ResponseWriter, ev *events.Event) error {
+	payload, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d-%d\nevent: %s\ndata: %s\n\n", ev.Txid, ev.ID, ev.Type, payload)
+	return err
+}
+
+// parseEventID parses a "<txid>-<id>" event id.
+func parseEventID(s string) (int64, int64, bool) {
+	i := strings.IndexByte(s, '-')
+	if i == -1 {
+		return 0, 0, false
+	}
+	txid, err1 := strconv.ParseInt(s[:i], 10, 64)
+	id, err2 := strconv.ParseInt(s[i+1:], 10, 64)
+	if err1 != nil || err2 != nil {
+		return 0, 0, false
+	}
+	return txid, id, true
+}
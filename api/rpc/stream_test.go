@@ -0,0 +1,93 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/bus"
+	"github.com/nfteseum/nfteseum-learning-project/api/events"
+)
+
+const (
+	alice = "0x1111111111111111111111111111111111111111"
+	bob   = "0x2222222222222222222222222222222222222222"
+	carol = "0x3333333333333333333333333333333333333333"
+)
+
+func newEvent(t *testing.T, id int64, typ events.Type, actor string, payload interface{}) *events.Event {
+	t.Helper()
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &events.Event{ID: id, Txid: id, Type: typ, Actor: actor, Data: raw}
+}
+
+func TestStreamNotifications(t *testing.T) {
+	topics, err := streamTopics(alice, []string{"notifications"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := bus.NewMemory()
+	sub := b.Subscribe(topics...)
+	defer sub.Close()
+
+	forward := events.Forward(b)
+	evs := []*events.Event{
+		newEvent(t, 1, events.PostLiked, bob, events.PostLike{PostID: 1, PostAuthor: alice}),
+		newEvent(t, 2, events.PostLiked, alice, events.PostLike{PostID: 1, PostAuthor: alice}),
+		newEvent(t, 3, events.PostUnliked, bob, events.PostLike{PostID: 1, PostAuthor: alice}),
+		newEvent(t, 4, events.CommentCreated, bob, events.Comment{PostID: 2, PostAuthor: carol, ParentAuthor: alice}),
+		newEvent(t, 5, events.CommentCreated, bob, events.Comment{PostID: 3, PostAuthor: carol, Mentions: []string{alice}}),
+		newEvent(t, 6, events.CommentCreated, alice, events.Comment{PostID: 4, PostAuthor: alice, Mentions: []string{alice}}),
+		newEvent(t, 7, events.ContentModerated, carol, events.Moderation{TargetType: "post", Reporters: []string{alice, bob}}),
+	}
+	for _, ev := range evs {
+		if err := forward(context.Background(), ev); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Likes, replies, mentions and resolved reports reach the stream,
+	// except for the activity of the account itself.
+	want := []int64{1, 4, 5, 7}
+	for _, id := range want {
+		select {
+		case msg := <-sub.C():
+			var ev events.Event
+			if err := json.Unmarshal(msg.Data, &ev); err != nil {
+				t.Fatal(err)
+			}
+			if ev.ID != id {
+				t.Errorf("got event %d (%s), want %d", ev.ID, ev.Type, id)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event %d not received", id)
+		}
+	}
+	select {
+	case msg := <-sub.C():
+		t.Errorf("got unexpected message %s", msg.Data)
+	default:
+	}
+
+	// Resuming replays the same events.
+	var replayed []int64
+	for _, ev := range evs {
+		if streamMatch(ev, topics) {
+			replayed = append(replayed, ev.ID)
+		}
+	}
+	if len(replayed) != len(want) {
+		t.Fatalf("replayed %v, want %v", replayed, want)
+	}
+	for i := range want {
+		if replayed[i] != want[i] {
+			t.Errorf("replayed %v, want %v", replayed, want)
+			break
+		}
+	}
+}
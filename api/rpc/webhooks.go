@@ -0,0 +1,290 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nfteseum/nfteseum-learning-project/api/chain"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/nfteseum/nfteseum-learning-project/api/proto"
+	"github.com/nfteseum/nfteseum-learning-project/api/webhooks"
+)
+
+const (
+	// maxWebhooksPerAccount caps the webhooks registered by an account.
+	maxWebhooksPerAccount = 10
+
+	defaultWebhookDeliveriesLimit = 20
+	maxWebhookDeliveriesLimit     = 100
+)
+
+// CreateWebhook registers a webhook receiving the given types of events on
+// the posts of contract. The returned secret signs the payloads, and is only
+// ever returned here.
+func (s *RPC) CreateWebhook(ctx context.Context, contract string, url string, eventTypes []string) (*proto.Webhook, string, error) {
+	account, err := s.activeAccount(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	contract = strings.ToLower(contract)
+	if !chain.IsAddress(contract) {
+		return nil, "", proto.ErrorInvalidArgument("contract", "must be a contract address")
+	}
+	if !s.Webhooks.ValidURL(url) {
+		return nil, "", proto.ErrorInvalidArgument("url", "must be an https url")
+	}
+	types, err := webhookEventTypes(eventTypes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	n, err := data.DB.CountWebhooks(ctx, account)
+	if err != nil {
+		return nil, "", s.dbError(ctx, err)
+	}
+	if n >= maxWebhooksPerAccount {
+		return nil, "", proto.Errorf(proto.ErrResourceExhausted, "too many webhooks, delete one first")
+	}
+
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		return nil, "", err
+	}
+	row, err := data.DB.CreateWebhook(ctx, sqlc.CreateWebhookParams{
+		Owner:        account,
+		ContractAddr: contract,
+		Url:          url,
+		EventTypes:   types,
+		Secret:       secret,
+	})
+	if err != nil {
+		return nil, "", s.dbError(ctx, err)
+	}
+	return webhookFromRow(&row), secret, nil
+}
+
+func (s *RPC) ListWebhooks(ctx context.Context) ([]*proto.Webhook, error) {
+	account, err := sessionAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := data.DB.ListWebhooks(ctx, account)
+	if err != nil {
+		return nil, s.dbError(ctx, err)
+	}
+	list := make([]*proto.Webhook, len(rows))
+	for i := range rows {
+		list[i] = webhookFromRow(&rows[i])
+	}
+	return list, nil
+}
+
+// UpdateWebhook changes the given settings of a webhook. Enabling a webhook
+// disabled after failing clears its failures.
+func (s *RPC) UpdateWebhook(ctx context.Context, id int64, url *string, eventTypes []string, enabled *bool) (*proto.Webhook, error) {
+	account, err := s.activeAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hook, err := s.ownWebhook(ctx, account, id)
+	if err != nil {
+		return nil, err
+	}
+
+	params := sqlc.UpdateWebhookParams{
+		Url:        hook.Url,
+		EventTypes: hook.EventTypes,
+		Enabled:    hook.Enabled,
+		ID:         hook.ID,
+		Owner:      account,
+	}
+	if url != nil {
+		if !s.Webhooks.ValidURL(*url) {
+			return nil, proto.ErrorInvalidArgument("url", "must be an https url")
+		}
+		params.Url = *url
+	}
+	if eventTypes != nil {
+		params.EventTypes, err = webhookEventTypes(eventTypes)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if enabled != nil {
+		params.Enabled = *enabled
+	}
+
+	row, err := data.DB.UpdateWebhook(ctx, params)
+	if err != nil {
+		return nil, s.dbError(ctx, err)
+	}
+	return webhookFromRow(&row), nil
+}
+
+// DeleteWebhook deletes a webhook and its delivery log. Suspended accounts
+// can too.
+func (s *RPC) DeleteWebhook(ctx context.Context, id int64) (bool, error) {
+	account, err := sessionAccount(ctx)
+	if err != nil {
+		return false, err
+	}
+	n, err := data.DB.DeleteWebhook(ctx, sqlc.DeleteWebhookParams{ID: id, Owner: account})
+	if err != nil {
+		return false, s.dbError(ctx, err)
+	}
+	return n > 0, nil
+}
+
+// ListWebhookDeliveries returns the delivery attempts of a webhook, latest
+// first. The returned cursor fetches the next page, and is empty on the last
+// one.
+func (s *RPC) ListWebhookDeliveries(ctx context.Context, id int64, cursor *string, limit *int32) ([]*proto.WebhookDelivery, string, error) {
+	account, err := sessionAccount(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := s.ownWebhook(ctx, account, id); err != nil {
+		return nil, "", err
+	}
+
+	n := int32(defaultWebhookDeliveriesLimit)
+	if limit != nil {
+		if *limit <= 0 || *limit > maxWebhookDeliveriesLimit {
+			return nil, "", proto.ErrorInvalidArgument("limit", fmt.Sprintf("must be between 1 and %d", maxWebhookDeliveriesLimit))
+		}
+		n = *limit
+	}
+
+	params := sqlc.ListWebhookDeliveriesParams{
+		WebhookID:     id,
+		BeforeID:      math.MaxInt64,
+		MaxDeliveries: n,
+	}
+	if cursor != nil && *cursor != "" {
+		params.BeforeID, err = strconv.ParseInt(*cursor, 10, 64)
+		if err != nil {
+			return nil, "", proto.ErrorInvalidArgument("cursor", "is malformed")
+		}
+	}
+
+	rows, err := data.DB.ListWebhookDeliveries(ctx, params)
+	if err != nil {
+		return nil, "", s.dbError(ctx, err)
+	}
+	list := make([]*proto.WebhookDelivery, len(rows))
+	for i := range rows {
+		list[i] = deliveryFromRow(&rows[i])
+	}
+
+	next := ""
+	if len(rows) == int(n) {
+		next = strconv.FormatInt(rows[len(rows)-1].ID, 10)
+	}
+	return list, next, nil
+}
+
+// SendTestWebhook sends a test event to a webhook right away, and returns
+// the delivery. Test deliveries aren't retried, and don't count against the
+// webhook when they fail.
+func (s *RPC) SendTestWebhook(ctx context.Context, id int64) (*proto.WebhookDelivery, error) {
+	account, err := s.activeAccount(ctx)
+	if err != nil {
+		return nil, err
+	}
+	hook, err := s.ownWebhook(ctx, account, id)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := s.Webhooks.Deliver(ctx, hook, &webhooks.Payload{
+		ID:        "test_" + uuid.NewString(),
+		Type:      webhooks.TestEvent,
+		Contract:  strings.TrimSpace(hook.ContractAddr),
+		Actor:     account,
+		Data:      []byte(`{"message":"This is a test event."}`),
+		CreatedAt: time.Now().UTC(),
+	})
+	var deliveryErr *webhooks.DeliveryError
+	if err != nil && !errors.As(err, &deliveryErr) {
+		return nil, s.dbError(ctx, err)
+	}
+	return deliveryFromRow(delivery), nil
+}
+
+// ownWebhook returns the webhook id of account, or a not found error.
+func (s *RPC) ownWebhook(ctx context.Context, account string, id int64) (*sqlc.Webhooks, error) {
+	hook, err := data.DB.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, s.dbError(ctx, err)
+	}
+	if !strings.EqualFold(hook.Owner, account) {
+		return nil, proto.ErrorNotFound("webhook not found")
+	}
+	return &hook, nil
+}
+
+// webhookEventTypes validates the event types of a webhook, and dedupes them.
+func webhookEventTypes(eventTypes []string) ([]string, error) {
+	if len(eventTypes) == 0 {
+		return nil, proto.ErrorRequiredArgument("eventTypes")
+	}
+	types := make([]string, 0, len(eventTypes))
+	seen := map[string]bool{}
+	for _, t := range eventTypes {
+		if !webhooks.ValidType(t) {
+			return nil, proto.ErrorInvalidArgument("eventTypes", fmt.Sprintf("unknown event type %q", t))
+		}
+		if !seen[t] {
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+	return types, nil
+}
+
+func webhookFromRow(row *sqlc.Webhooks) *proto.Webhook {
+	hook := &proto.Webhook{
+		ID:           row.ID,
+		Contract:     strings.TrimSpace(row.ContractAddr),
+		URL:          row.Url,
+		EventTypes:   row.EventTypes,
+		Enabled:      row.Enabled,
+		FailureCount: row.FailureCount,
+		CreatedAt:    row.CreatedAt,
+		UpdatedAt:    row.UpdatedAt,
+	}
+	if row.DisabledAt.Valid {
+		hook.DisabledAt = &row.DisabledAt.Time
+	}
+	return hook
+}
+
+func deliveryFromRow(row *sqlc.WebhookDeliveries) *proto.WebhookDelivery {
+	delivery := &proto.WebhookDelivery{
+		ID:         row.ID,
+		DeliveryID: row.DeliveryID,
+		EventType:  row.EventType,
+		Payload:    string(row.Payload.Bytes),
+		DurationMs: row.DurationMs,
+		Success:    row.Success,
+		CreatedAt:  row.CreatedAt,
+	}
+	if row.StatusCode.Valid {
+		delivery.StatusCode = &row.StatusCode.Int32
+	}
+	if row.Error.Valid {
+		delivery.Error = &row.Error.String
+	}
+	if row.Response.Valid {
+		delivery.Response = &row.Response.String
+	}
+	return delivery
+}
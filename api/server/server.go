@@ -3,23 +3,50 @@ package server
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/go-chi/httplog"
 	"github.com/nfteseum/nfteseum-learning-project/api"
+	"github.com/nfteseum/nfteseum-learning-project/api/bus"
+	"github.com/nfteseum/nfteseum-learning-project/api/chain"
+	"github.com/nfteseum/nfteseum-learning-project/api/chat"
 	"github.com/nfteseum/nfteseum-learning-project/api/config"
 	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/events"
+	"github.com/nfteseum/nfteseum-learning-project/api/health"
+	"github.com/nfteseum/nfteseum-learning-project/api/jobs"
+	"github.com/nfteseum/nfteseum-learning-project/api/leader"
+	"github.com/nfteseum/nfteseum-learning-project/api/mail"
+	"github.com/nfteseum/nfteseum-learning-project/api/metrics"
+	"github.com/nfteseum/nfteseum-learning-project/api/notifications"
 	"github.com/nfteseum/nfteseum-learning-project/api/rpc"
+	"github.com/nfteseum/nfteseum-learning-project/api/tasks"
+	"github.com/nfteseum/nfteseum-learning-project/api/tracing"
+	"github.com/nfteseum/nfteseum-learning-project/api/webhooks"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog"
 	"golang.org/x/sync/errgroup"
 )
 
 type Server struct {
-	Config *config.Config
-	Logger zerolog.Logger
-	RPC    *rpc.RPC
+	Config  *config.Config
+	Logger  zerolog.Logger
+	Health  *health.Health
+	Metrics *prometheus.Registry
+	RPC     *rpc.RPC
+	Jobs    *jobs.Queue
+	Leader  *leader.Elector
+	Events  *events.Relay
+	Bus     bus.Bus
+
+	// metricsHTTP is the internal listener for /metrics, if configured.
+	metricsHTTP *http.Server
+
+	// tracingShutdownFn flushes pending trace spans.
+	tracingShutdownFn func(context.Context) error
 
 	ctx       context.Context
 	ctxStopFn context.CancelFunc
@@ -43,16 +70,99 @@ func NewServer(cfg *config.Config) (*Server, error) {
 		},
 	})
 
+	//
+	// Tracing
+	//
+	tracingShutdownFn, err := tracing.Setup(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	//
 	// Database
 	//
-	err = data.PrepareDB(cfg)
+	err = data.PrepareDB(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	//
+	// Leader election
+	//
+	elector, err := leader.New(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	//
+	// Background jobs
+	//
+	queue, err := jobs.NewQueue(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	mailer, err := mail.New(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	err = tasks.Register(cfg, queue, mailer, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	//
+	// Domain events
+	//
+	eventBus, err := bus.New(cfg, logger)
 	if err != nil {
 		return nil, err
 	}
+	relay, err := events.NewRelay(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	relay.Subscribe("bus", events.Forward(eventBus))
+	relay.Subscribe("notifications", notifications.NewNotifier(cfg, logger, queue).Handle, notifications.Types...)
+	relay.Subscribe("webhooks", webhooks.NewDispatcher(logger, queue).Handle, webhooks.Types...)
+
+	//
+	// Chat
+	//
+	chatHub, err := chat.New(cfg, logger, eventBus, chain.NewReader(cfg))
+	if err != nil {
+		return nil, err
+	}
+
+	//
+	// Health checks
+	//
+	hc := health.New()
+	hc.Add("db", data.CheckConnected)
+	hc.Add("migrations", data.CheckMigrations)
+
+	//
+	// Metrics
+	//
+	var reg *prometheus.Registry
+	var metricsHTTP *http.Server
+	if cfg.Metrics.Enabled {
+		reg, err = metrics.NewRegistry(cfg, metrics.NewPoolCollector(data.PoolStats))
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Metrics.Listen != "" {
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", metrics.Handler(reg))
+			metricsHTTP = &http.Server{
+				Addr:              cfg.Metrics.Listen,
+				Handler:           mux,
+				ReadHeaderTimeout: 5 * time.Second,
+			}
+		}
+	}
 
 	// WebRPC Server
-	rpc, err := rpc.NewRPC(cfg, logger)
+	rpc, err := rpc.NewRPC(cfg, logger, hc, reg, eventBus, chatHub, queue)
 	if err != nil {
 		return nil, err
 	}
@@ -61,9 +171,18 @@ func NewServer(cfg *config.Config) (*Server, error) {
 	// Server
 	//
 	server := &Server{
-		Config: cfg,
-		Logger: logger,
-		RPC:    rpc,
+		Config:      cfg,
+		Logger:      logger,
+		Health:      hc,
+		Metrics:     reg,
+		RPC:         rpc,
+		Jobs:        queue,
+		Leader:      elector,
+		Events:      relay,
+		Bus:         eventBus,
+		metricsHTTP: metricsHTTP,
+
+		tracingShutdownFn: tracingShutdownFn,
 	}
 
 	return server, nil
@@ -88,12 +207,59 @@ func (s *Server) Run() error {
 	// Subprocess run context
 	g, ctx := errgroup.WithContext(s.ctx)
 
+	// Database maintenance
+	g.Go(func() error {
+		oplog.Info().Msgf("-> data: run")
+		return data.Run(ctx)
+	})
+
 	// RPC
 	g.Go(func() error {
 		oplog.Info().Msgf("-> rpc: run")
 		return s.RPC.Run(ctx)
 	})
 
+	// Event bus
+	g.Go(func() error {
+		oplog.Info().Msgf("-> bus: run")
+		return s.Bus.Run(ctx)
+	})
+
+	// Leader election
+	g.Go(func() error {
+		oplog.Info().Msgf("-> leader: run")
+		return s.Leader.Run(ctx)
+	})
+
+	// Background jobs
+	g.Go(func() error {
+		oplog.Info().Msgf("-> jobs: run")
+		return s.Jobs.Run(ctx)
+	})
+
+	// Singleton workers, only run on the leader
+	g.Go(func() error {
+		return s.Leader.OnLeader(ctx, "jobs scheduler", s.Jobs.RunScheduler)
+	})
+	g.Go(func() error {
+		return s.Leader.OnLeader(ctx, "events relay", s.Events.Run)
+	})
+	g.Go(func() error {
+		return s.Leader.OnLeader(ctx, "chat presence pruner", s.RPC.Chat.RunPruner)
+	})
+
+	// Metrics
+	if s.metricsHTTP != nil {
+		g.Go(func() error {
+			oplog.Info().Msgf("-> metrics: listening on %s", s.metricsHTTP.Addr)
+			err := s.metricsHTTP.ListenAndServe()
+			if err != nil && err != http.ErrServerClosed {
+				return err
+			}
+			return nil
+		})
+	}
+
 	// Once run context is done, trigger a server-stop.
 	go func() {
 		<-ctx.Done()
@@ -115,8 +281,17 @@ func (s *Server) Stop() {
 	// Stopping
 	atomic.StoreInt32(&s.running, 2)
 
+	// Fail readiness right away so load balancers stop sending new traffic,
+	// and give them a moment to notice before the listener goes away.
+	s.Health.SetShuttingDown()
+	if delay, _ := time.ParseDuration(s.Config.Service.ShutdownDelay); delay > 0 {
+		oplog.Info().Msgf("=> draining for %s", delay)
+		time.Sleep(delay)
+	}
+
 	// Shutdown signal with grace period of 30 seconds
-	shutdownCtx, _ := context.WithTimeout(context.Background(), 30*time.Second)
+	shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancelShutdown()
 
 	var wg sync.WaitGroup
 
@@ -126,6 +301,24 @@ func (s *Server) Stop() {
 		s.RPC.Stop(shutdownCtx)
 	}()
 
+	// Running jobs are cancelled a little before the grace period ends, so
+	// they still have time to be released for a retry.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		drainCtx, cancelDrain := context.WithTimeout(shutdownCtx, 25*time.Second)
+		defer cancelDrain()
+		s.Jobs.Stop(drainCtx)
+	}()
+
+	if s.metricsHTTP != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.metricsHTTP.Shutdown(shutdownCtx)
+		}()
+	}
+
 	// Force shutdown after grace period
 	go func() {
 		<-shutdownCtx.Done()
@@ -136,6 +329,11 @@ func (s *Server) Stop() {
 
 	// Wait for subprocesses to gracefully stop
 	wg.Wait()
+
+	// Flush pending trace spans
+	if err := s.tracingShutdownFn(shutdownCtx); err != nil {
+		oplog.Error().Err(err).Msg("failed to flush traces")
+	}
 	s.ctxStopFn()
 	atomic.StoreInt32(&s.running, 0)
 }
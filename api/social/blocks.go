@@ -0,0 +1,101 @@
+package social
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/nfteseum/nfteseum-learning-project/api/events"
+)
+
+// Kinds of blocks. Blocked users can't comment on, like or follow the
+// blocker, and both blocked and muted users are left out of the feeds and
+// notifications of the blocker.
+const (
+	Block = "block"
+	Mute  = "mute"
+)
+
+// ErrBlocked is returned when interacting with a user who blocked the actor.
+var ErrBlocked = errors.New("social: blocked by the user")
+
+// BlockUser blocks or mutes, as kind, account on behalf of blocker, and
+// reports whether it's new. Blocking also removes the follows between them.
+func BlockUser(ctx context.Context, blocker, account, kind string) (bool, error) {
+	created := false
+	err := data.WithTx(ctx, pgx.TxOptions{}, func(q *sqlc.Queries) error {
+		created = false
+
+		_, err := q.CreateUserBlock(ctx, sqlc.CreateUserBlockParams{Blocker: blocker, Blocked: account, Kind: kind})
+		if errors.Is(err, data.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		created = true
+		if kind != Block {
+			return nil
+		}
+
+		for _, f := range []sqlc.DeleteFollowParams{
+			{Follower: account, Followee: blocker},
+			{Follower: blocker, Followee: account},
+		} {
+			n, err := q.DeleteFollow(ctx, f)
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				continue
+			}
+			_, err = events.Publish(ctx, q, events.UserUnfollowed, f.Follower, events.Follow{Followee: f.Followee})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return created, err
+}
+
+// UnblockUser removes the block or mute, as kind, of account by blocker, and
+// reports whether there was one. Follows removed by the block aren't
+// restored.
+func UnblockUser(ctx context.Context, blocker, account, kind string) (bool, error) {
+	n, err := data.DB.DeleteUserBlock(ctx, sqlc.DeleteUserBlockParams{Blocker: blocker, Blocked: account, Kind: kind})
+	return n > 0, err
+}
+
+// ListBlockedUsers returns up to limit users blocked or muted, as kind, by
+// blocker, by address after the address after.
+func ListBlockedUsers(ctx context.Context, blocker, kind, after string, limit int32) ([]string, error) {
+	rows, err := data.DB.ListUserBlocks(ctx, sqlc.ListUserBlocksParams{
+		Blocker:      blocker,
+		Kind:         kind,
+		AfterBlocked: after,
+		MaxBlocks:    limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	accounts := make([]string, len(rows))
+	for i, row := range rows {
+		accounts[i] = addr(row.Blocked)
+	}
+	return accounts, nil
+}
+
+// checkBlocked returns ErrBlocked if blocker blocked account.
+func checkBlocked(ctx context.Context, q *sqlc.Queries, blocker, account string) error {
+	blocked, err := q.IsBlocked(ctx, sqlc.IsBlockedParams{Blocker: blocker, Blocked: account})
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return ErrBlocked
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package social
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Types of entities.
+const (
+	EntityMention = "mention"
+	EntityHashtag = "hashtag"
+)
+
+const (
+	// maxMentions caps the users resolved, and notified, per comment.
+	maxMentions = 10
+
+	// maxHashtags caps the hashtags indexed per comment.
+	maxHashtags = 10
+
+	maxNameLength    = 32
+	maxHashtagLength = 64
+)
+
+var (
+	// entityRe matches the @mentions and #hashtags which don't follow a word,
+	// ie. in emails or anchors.
+	entityRe = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_@#/&])([@#])([\p{L}\p{N}_]+)`)
+
+	addressHandleRe = regexp.MustCompile(`^0x[0-9a-f]{40}$`)
+)
+
+// Entity is a mention or hashtag in the content of a comment.
+type Entity struct {
+	Type string
+
+	// Start and End delimit the entity, sigil included, in UTF-16 code
+	// units so clients can slice JavaScript strings with them.
+	Start int
+	End   int
+
+	// Value is the lowercase handle or tag, without its sigil.
+	Value string
+
+	// Account is the user a mention resolved to, if any.
+	Account string
+}
+
+// ParseEntities returns the mentions and hashtags of content, in order.
+// Mentions are "@name" or "@0x…" addresses, and hashtags hold at least one
+// letter so "#3" isn't one.
+func ParseEntities(content string) []Entity {
+	var entities []Entity
+
+	// Offsets are converted incrementally, matches being in order.
+	pos, units := 0, 0
+	for _, m := range entityRe.FindAllStringSubmatchIndex(content, -1) {
+		start, end := m[2], m[5]
+		sigil, value := content[m[2]:m[3]], strings.ToLower(content[m[4]:m[5]])
+
+		var typ string
+		switch {
+		case sigil == "@" && validHandle(value):
+			typ = EntityMention
+		case sigil == "#" && validHashtag(value):
+			typ = EntityHashtag
+		default:
+			continue
+		}
+
+		units += utf16Len(content[pos:start])
+		startUnits := units
+		units += utf16Len(content[start:end])
+		pos = end
+
+		entities = append(entities, Entity{
+			Type:  typ,
+			Start: startUnits,
+			End:   units,
+			Value: value,
+		})
+	}
+	return entities
+}
+
+// NormalizeHashtag returns the lowercase tag of a hashtag given with or
+// without its "#", and whether it's valid.
+func NormalizeHashtag(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+	for _, r := range tag {
+		if !isWordRune(r) {
+			return "", false
+		}
+	}
+	return tag, validHashtag(tag)
+}
+
+// validHandle reports whether a lowercase handle is an address or may be a
+// name.
+func validHandle(handle string) bool {
+	if strings.HasPrefix(handle, "0x") && len(handle) == 42 {
+		return addressHandleRe.MatchString(handle)
+	}
+	return utf8.RuneCountInString(handle) <= maxNameLength
+}
+
+func validHashtag(tag string) bool {
+	if tag == "" || utf8.RuneCountInString(tag) > maxHashtagLength {
+		return false
+	}
+	for _, r := range tag {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r) || r == '_'
+}
+
+// utf16Len returns the length of s in UTF-16 code units.
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+// mentionedHandles returns the distinct handles mentioned in entities, up
+// to maxMentions.
+func mentionedHandles(entities []Entity) []string {
+	var handles []string
+	seen := map[string]bool{}
+	for _, e := range entities {
+		if e.Type != EntityMention || seen[e.Value] {
+			continue
+		}
+		seen[e.Value] = true
+		handles = append(handles, e.Value)
+		if len(handles) == maxMentions {
+			break
+		}
+	}
+	return handles
+}
+
+// hashtags returns the distinct tags of entities, up to maxHashtags.
+func hashtags(entities []Entity) []string {
+	var tags []string
+	seen := map[string]bool{}
+	for _, e := range entities {
+		if e.Type != EntityHashtag || seen[e.Value] {
+			continue
+		}
+		seen[e.Value] = true
+		tags = append(tags, e.Value)
+		if len(tags) == maxHashtags {
+			break
+		}
+	}
+	return tags
+}
@@ -0,0 +1,413 @@
+// Package social implements the interactions between users: likes, comments
+// and follows. Every change publishes its domain event in the same
+// transaction, see the events package.
+package social
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/jackc/pgx/v4"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/nfteseum/nfteseum-learning-project/api/events"
+	"github.com/nfteseum/nfteseum-learning-project/api/moderation"
+)
+
+var (
+	// ErrInvalidParent is returned when replying to a comment of another post.
+	ErrInvalidParent = errors.New("social: parent comment belongs to another post")
+
+	// ErrSuspended is returned when a suspended account interacts.
+	ErrSuspended = errors.New("social: account is suspended")
+)
+
+// LikePost likes a post on behalf of actor, and reports whether the like is
+// new. Liking a post twice is a no-op, and liking the posts of users who
+// blocked actor fails with ErrBlocked.
+func LikePost(ctx context.Context, actor string, postID int32) (bool, error) {
+	created := false
+	err := data.WithTx(ctx, pgx.TxOptions{}, func(q *sqlc.Queries) error {
+		created = false
+
+		if err := checkActive(ctx, q, actor); err != nil {
+			return err
+		}
+		post, err := getVisiblePost(ctx, q, postID)
+		if err != nil {
+			return err
+		}
+		if err := checkBlocked(ctx, q, addr(post.Author), actor); err != nil {
+			return err
+		}
+
+		_, err = q.CreateLike(ctx, sqlc.CreateLikeParams{PostID: postID, LikedBy: actor})
+		if errors.Is(err, data.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		created = true
+
+		err = q.AddPostLikes(ctx, sqlc.AddPostLikesParams{ID: postID, Delta: 1})
+		if err != nil {
+			return err
+		}
+
+		_, err = events.Publish(ctx, q, events.PostLiked, actor, events.PostLike{
+			PostID:     postID,
+			PostAuthor: addr(post.Author),
+			Contract:   contract(post.ContractAddr),
+		})
+		return err
+	})
+	return created, err
+}
+
+// UnlikePost removes the like of actor from a post, and reports whether
+// there was one.
+func UnlikePost(ctx context.Context, actor string, postID int32) (bool, error) {
+	deleted := false
+	err := data.WithTx(ctx, pgx.TxOptions{}, func(q *sqlc.Queries) error {
+		deleted = false
+
+		post, err := q.GetPost(ctx, postID)
+		if err != nil {
+			return err
+		}
+
+		n, err := q.DeleteLike(ctx, sqlc.DeleteLikeParams{PostID: postID, LikedBy: actor})
+		if err != nil || n == 0 {
+			return err
+		}
+		deleted = true
+
+		err = q.AddPostLikes(ctx, sqlc.AddPostLikesParams{ID: postID, Delta: -1})
+		if err != nil {
+			return err
+		}
+
+		_, err = events.Publish(ctx, q, events.PostUnliked, actor, events.PostLike{
+			PostID:     postID,
+			PostAuthor: addr(post.Author),
+			Contract:   contract(post.ContractAddr),
+		})
+		return err
+	})
+	return deleted, err
+}
+
+// Comment is a comment with the mentions and hashtags of its content.
+type Comment struct {
+	sqlc.Comments
+	Entities []Entity
+}
+
+// Hold holds a comment for review, as scored by the spam filter.
+type Hold struct {
+	Score   float64
+	Reasons []string
+}
+
+// AddComment comments on a post on behalf of author. A non-zero parentID
+// makes it a reply to that comment, which must be on the same post. Users
+// blocked by the author of the post or parent comment get ErrBlocked.
+//
+// The users mentioned in content are resolved and its hashtags indexed, in
+// the same transaction. A non-nil hold saves the comment as held, out of
+// sight and without notifying anyone until a moderator restores it.
+func AddComment(ctx context.Context, author string, postID int32, parentID int32, content string, hold *Hold) (*Comment, error) {
+	var comment Comment
+	err := data.WithTx(ctx, pgx.TxOptions{}, func(q *sqlc.Queries) error {
+		if err := checkActive(ctx, q, author); err != nil {
+			return err
+		}
+		post, err := getVisiblePost(ctx, q, postID)
+		if err != nil {
+			return err
+		}
+		if err := checkBlocked(ctx, q, addr(post.Author), author); err != nil {
+			return err
+		}
+
+		payload := events.Comment{
+			PostID:     postID,
+			PostAuthor: addr(post.Author),
+			Contract:   contract(post.ContractAddr),
+			Content:    content,
+		}
+
+		if parentID != 0 {
+			parent, err := q.GetComment(ctx, parentID)
+			if err != nil {
+				return err
+			}
+			if parent.PostID != postID || parent.Visibility != moderation.Visible {
+				return ErrInvalidParent
+			}
+			if err := checkBlocked(ctx, q, addr(parent.Author), author); err != nil {
+				return err
+			}
+			payload.ParentID = parentID
+			payload.ParentAuthor = addr(parent.Author)
+		}
+
+		row, err := q.CreateComment(ctx, sqlc.CreateCommentParams{
+			PostID:   postID,
+			ParentID: sql.NullInt32{Int32: parentID, Valid: parentID != 0},
+			Author:   author,
+			Content:  content,
+		})
+		if err != nil {
+			return err
+		}
+		payload.CommentID = row.ID
+
+		comment = Comment{Comments: row, Entities: ParseEntities(content)}
+		payload.Mentions, err = saveEntities(ctx, q, &comment)
+		if err != nil {
+			return err
+		}
+		if hold != nil {
+			return holdComment(ctx, q, &comment, hold, &payload)
+		}
+
+		err = q.AddPostComments(ctx, sqlc.AddPostCommentsParams{ID: postID, Delta: 1})
+		if err != nil {
+			return err
+		}
+
+		_, err = events.Publish(ctx, q, events.CommentCreated, author, payload)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// holdComment sets comment as held, and saves the event published once it's
+// restored.
+func holdComment(ctx context.Context, q *sqlc.Queries, comment *Comment, hold *Hold, payload *events.Comment) error {
+	_, err := q.SetCommentVisibility(ctx, sqlc.SetCommentVisibilityParams{ID: comment.ID, Visibility: moderation.Held})
+	if err != nil {
+		return err
+	}
+	comment.Visibility = moderation.Held
+
+	event, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return q.CreateHeldComment(ctx, sqlc.CreateHeldCommentParams{
+		CommentID: comment.ID,
+		Score:     float32(hold.Score),
+		Reasons:   hold.Reasons,
+		Event:     pgtype.JSONB{Bytes: event, Status: pgtype.Present},
+	})
+}
+
+// saveEntities resolves the mentions of comment and saves them with its
+// hashtags. It returns the accounts mentioned, but its author.
+func saveEntities(ctx context.Context, q *sqlc.Queries, comment *Comment) ([]string, error) {
+	tags := hashtags(comment.Entities)
+	if len(tags) > 0 {
+		err := q.CreateCommentHashtags(ctx, sqlc.CreateCommentHashtagsParams{
+			CommentID: comment.ID,
+			PostID:    comment.PostID,
+			Tags:      tags,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	handles := mentionedHandles(comment.Entities)
+	if len(handles) == 0 {
+		return nil, nil
+	}
+	rows, err := q.ResolveMentions(ctx, handles)
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+
+	resolved := make(map[string]string, len(rows))
+	params := sqlc.CreateCommentMentionsParams{CommentID: comment.ID}
+	for _, row := range rows {
+		resolved[row.Handle] = addr(row.Account)
+		params.Handles = append(params.Handles, row.Handle)
+		params.Accounts = append(params.Accounts, row.Account)
+	}
+	if err := q.CreateCommentMentions(ctx, params); err != nil {
+		return nil, err
+	}
+	resolveEntities(comment.Entities, resolved)
+
+	var accounts []string
+	seen := map[string]bool{}
+	for _, account := range resolved {
+		if !seen[account] && !strings.EqualFold(account, addr(comment.Author)) {
+			seen[account] = true
+			accounts = append(accounts, account)
+		}
+	}
+	sort.Strings(accounts)
+	return accounts, nil
+}
+
+// ListComments returns up to limit comments of a post before the comment id
+// before, latest first, or none if the post is hidden or removed. Comments of
+// the users viewer blocked or muted are left out, and viewer is empty for
+// anonymous requests.
+func ListComments(ctx context.Context, viewer string, postID int32, before int32, limit int32) ([]*Comment, error) {
+	rows, err := data.DB.ListComments(ctx, sqlc.ListCommentsParams{
+		PostID:      postID,
+		BeforeID:    before,
+		Viewer:      viewer,
+		MaxComments: limit,
+	})
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+
+	return withEntities(ctx, rows)
+}
+
+// GetComments returns the comments of the given ids, in no particular order.
+// Missing comments are skipped.
+func GetComments(ctx context.Context, ids []int32) ([]*Comment, error) {
+	rows, err := data.DB.ListCommentsByID(ctx, ids)
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+	return withEntities(ctx, rows)
+}
+
+// withEntities parses the entities of comments, and resolves their mentions.
+func withEntities(ctx context.Context, rows []sqlc.Comments) ([]*Comment, error) {
+	ids := make([]int32, len(rows))
+	for i := range rows {
+		ids[i] = rows[i].ID
+	}
+	mentions, err := data.DB.ListCommentMentions(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	resolved := map[int32]map[string]string{}
+	for _, m := range mentions {
+		if resolved[m.CommentID] == nil {
+			resolved[m.CommentID] = map[string]string{}
+		}
+		resolved[m.CommentID][m.Handle] = addr(m.Account)
+	}
+
+	comments := make([]*Comment, len(rows))
+	for i := range rows {
+		comments[i] = &Comment{Comments: rows[i], Entities: ParseEntities(rows[i].Content)}
+		resolveEntities(comments[i].Entities, resolved[rows[i].ID])
+	}
+	return comments, nil
+}
+
+// resolveEntities sets the accounts of the mentions resolved, by handle.
+func resolveEntities(entities []Entity, resolved map[string]string) {
+	for i := range entities {
+		if entities[i].Type == EntityMention {
+			entities[i].Account = resolved[entities[i].Value]
+		}
+	}
+}
+
+// FollowUser makes follower follow followee, and reports whether the follow
+// is new. Following a user who blocked follower fails with ErrBlocked.
+func FollowUser(ctx context.Context, follower, followee string) (bool, error) {
+	created := false
+	err := data.WithTx(ctx, pgx.TxOptions{}, func(q *sqlc.Queries) error {
+		created = false
+
+		if err := checkActive(ctx, q, follower); err != nil {
+			return err
+		}
+		if err := checkBlocked(ctx, q, followee, follower); err != nil {
+			return err
+		}
+		_, err := q.CreateFollow(ctx, sqlc.CreateFollowParams{Follower: follower, Followee: followee})
+		if errors.Is(err, data.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		created = true
+
+		_, err = events.Publish(ctx, q, events.UserFollowed, follower, events.Follow{Followee: followee})
+		return err
+	})
+	return created, err
+}
+
+// UnfollowUser makes follower stop following followee, and reports whether
+// it was following.
+func UnfollowUser(ctx context.Context, follower, followee string) (bool, error) {
+	deleted := false
+	err := data.WithTx(ctx, pgx.TxOptions{}, func(q *sqlc.Queries) error {
+		deleted = false
+
+		n, err := q.DeleteFollow(ctx, sqlc.DeleteFollowParams{Follower: follower, Followee: followee})
+		if err != nil || n == 0 {
+			return err
+		}
+		deleted = true
+
+		_, err = events.Publish(ctx, q, events.UserUnfollowed, follower, events.Follow{Followee: followee})
+		return err
+	})
+	return deleted, err
+}
+
+// checkActive returns ErrSuspended if account is suspended.
+func checkActive(ctx context.Context, q *sqlc.Queries, account string) error {
+	user, err := q.GetUser(ctx, account)
+	if errors.Is(err, data.ErrNoRows) {
+		// Left to the foreign keys.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if moderation.Suspended(&user, time.Now().UTC()) {
+		return ErrSuspended
+	}
+	return nil
+}
+
+// getVisiblePost returns a post, or data.ErrNoRows if it's hidden or
+// removed.
+func getVisiblePost(ctx context.Context, q *sqlc.Queries, postID int32) (*sqlc.Posts, error) {
+	post, err := q.GetPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+	if post.Visibility != moderation.Visible {
+		return nil, data.ErrNoRows
+	}
+	return &post, nil
+}
+
+// addr trims the padding of addresses stored in fixed-width char columns.
+func addr(s string) string {
+	return strings.TrimRight(s, " ")
+}
+
+// contract normalizes the contract address of a post, so events can be
+// matched by contract.
+func contract(s string) string {
+	return strings.ToLower(addr(s))
+}
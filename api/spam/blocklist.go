@@ -0,0 +1,198 @@
+package spam
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/nfteseum/nfteseum-learning-project/api/audit"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+)
+
+// Kinds of blocklist entries. Words match whole words of comments, and may
+// be phrases, and urls match the links to a domain and its subdomains.
+const (
+	BlockWord = "word"
+	BlockURL  = "url"
+)
+
+// blocklistTTL is how long the blocklist is cached. Changes made through
+// this instance apply right away.
+const blocklistTTL = time.Minute
+
+var (
+	// ErrInvalidEntry is returned for blocklist entries without a word or
+	// domain.
+	ErrInvalidEntry = errors.New("spam: invalid blocklist entry")
+
+	// ErrAlreadyListed is returned when adding an entry twice.
+	ErrAlreadyListed = errors.New("spam: already in the blocklist")
+)
+
+type blocklist struct {
+	words   []string
+	domains []string
+}
+
+// matchWords reports whether content holds one of the words.
+func (b *blocklist) matchWords(content string) bool {
+	if len(b.words) == 0 {
+		return false
+	}
+	text := " " + normalizeWords(content) + " "
+	for _, w := range b.words {
+		if strings.Contains(text, " "+w+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// matchLinks reports whether one of links is to one of the domains.
+func (b *blocklist) matchLinks(links []string) bool {
+	for _, link := range links {
+		h := host(link)
+		if h == "" {
+			continue
+		}
+		for _, d := range b.domains {
+			if h == d || strings.HasSuffix(h, "."+d) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NormalizeEntry returns the value of a blocklist entry as it's matched:
+// lowercase words separated by single spaces, or the domain of a url.
+func NormalizeEntry(kind, value string) (string, error) {
+	switch kind {
+	case BlockWord:
+		value = normalizeWords(value)
+	case BlockURL:
+		value = host(strings.TrimSpace(value))
+		if !strings.Contains(value, ".") {
+			return "", ErrInvalidEntry
+		}
+	default:
+		return "", ErrInvalidEntry
+	}
+	if value == "" {
+		return "", ErrInvalidEntry
+	}
+	return value, nil
+}
+
+// AddEntry adds an entry to the blocklist on behalf of admin, and records it
+// in the audit log.
+func (f *Filter) AddEntry(ctx context.Context, admin, kind, value string) (*sqlc.Blocklist, error) {
+	value, err := NormalizeEntry(kind, value)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry sqlc.Blocklist
+	err = data.WithTx(ctx, pgx.TxOptions{}, func(q *sqlc.Queries) error {
+		entry, err = q.CreateBlocklistEntry(ctx, sqlc.CreateBlocklistEntryParams{
+			Kind:      kind,
+			Value:     value,
+			CreatedBy: admin,
+		})
+		if errors.Is(err, data.ErrNoRows) {
+			return ErrAlreadyListed
+		}
+		if err != nil {
+			return err
+		}
+		return audit.Record(ctx, q, admin, audit.Entry{
+			Action:     audit.ActionBlocklistAdd,
+			TargetType: audit.TargetBlocklist,
+			TargetID:   strconv.FormatInt(int64(entry.ID), 10),
+			After:      entrySnapshot(&entry),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	f.invalidate()
+	return &entry, nil
+}
+
+// RemoveEntry removes an entry from the blocklist on behalf of admin, and
+// records it in the audit log. It reports whether the entry existed.
+func (f *Filter) RemoveEntry(ctx context.Context, admin string, id int32) (bool, error) {
+	removed := false
+	err := data.WithTx(ctx, pgx.TxOptions{}, func(q *sqlc.Queries) error {
+		removed = false
+		entry, err := q.DeleteBlocklistEntry(ctx, id)
+		if errors.Is(err, data.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		removed = true
+		return audit.Record(ctx, q, admin, audit.Entry{
+			Action:     audit.ActionBlocklistRemove,
+			TargetType: audit.TargetBlocklist,
+			TargetID:   strconv.FormatInt(int64(id), 10),
+			Before:     entrySnapshot(&entry),
+		})
+	})
+	if removed {
+		f.invalidate()
+	}
+	return removed, err
+}
+
+// getBlocklist returns the cached blocklist, loading it when stale.
+func (f *Filter) getBlocklist(ctx context.Context) (*blocklist, error) {
+	f.mu.Lock()
+	list, loadedAt := f.blocklist, f.loadedAt
+	f.mu.Unlock()
+	if list != nil && time.Since(loadedAt) < blocklistTTL {
+		return list, nil
+	}
+
+	rows, err := data.DB.ListBlocklist(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list = &blocklist{}
+	for _, row := range rows {
+		switch row.Kind {
+		case BlockWord:
+			list.words = append(list.words, row.Value)
+		case BlockURL:
+			list.domains = append(list.domains, row.Value)
+		}
+	}
+
+	f.mu.Lock()
+	f.blocklist, f.loadedAt = list, time.Now()
+	f.mu.Unlock()
+	return list, nil
+}
+
+func (f *Filter) invalidate() {
+	f.mu.Lock()
+	f.blocklist = nil
+	f.mu.Unlock()
+}
+
+func entrySnapshot(entry *sqlc.Blocklist) map[string]string {
+	return map[string]string{"kind": entry.Kind, "value": entry.Value}
+}
+
+// normalizeWords lowercases s and separates its words by single spaces.
+func normalizeWords(s string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	}), " ")
+}
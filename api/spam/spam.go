@@ -0,0 +1,223 @@
+// Package spam scores new comments, so suspicious ones are held for review
+// by the moderators instead of being published. The score adds up signals:
+// links, the same content posted on other posts, the age of the account,
+// the on-chain history of its wallet, its reputation, and the words and
+// domains of the blocklist managed by admins.
+package spam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/chain"
+	"github.com/nfteseum/nfteseum-learning-project/api/config"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/rs/zerolog"
+)
+
+// Reasons of scores.
+const (
+	ReasonBlockedWord   = "blocked_word"
+	ReasonBlockedURL    = "blocked_url"
+	ReasonLinks         = "links"
+	ReasonRepeated      = "repeated"
+	ReasonNewAccount    = "new_account"
+	ReasonNoHistory     = "no_wallet_history"
+	ReasonLowReputation = "low_reputation"
+)
+
+const (
+	// repeatWindow is how far back comments are compared for repetition.
+	repeatWindow = 24 * time.Hour
+
+	// walletTTL is how long the transaction counts of wallets are cached.
+	walletTTL = time.Hour
+
+	// maxWallets caps the wallets cached.
+	maxWallets = 10000
+)
+
+var linkRe = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"']+`)
+
+// Verdict is the score of a comment. Comments scoring HoldThreshold or more
+// are held.
+type Verdict struct {
+	Score   float64
+	Reasons []string
+	Hold    bool
+}
+
+func (v *Verdict) add(score float64, reason string) {
+	v.Score += score
+	v.Reasons = append(v.Reasons, reason)
+}
+
+// Filter scores comments.
+type Filter struct {
+	log       zerolog.Logger
+	reader    *chain.Reader
+	disabled  bool
+	threshold float64
+	newAge    time.Duration
+
+	mu        sync.Mutex
+	blocklist *blocklist
+	loadedAt  time.Time
+	wallets   map[string]wallet
+}
+
+type wallet struct {
+	txs       uint64
+	fetchedAt time.Time
+}
+
+func NewFilter(cfg *config.Config, log zerolog.Logger, reader *chain.Reader) (*Filter, error) {
+	f := &Filter{
+		log:       log.With().Str("ps", "spam").Logger(),
+		reader:    reader,
+		disabled:  cfg.Spam.Disabled,
+		threshold: 1,
+		newAge:    72 * time.Hour,
+		wallets:   map[string]wallet{},
+	}
+	if cfg.Spam.HoldThreshold < 0 {
+		return nil, fmt.Errorf("spam: config invalid spam.hold_threshold value %v", cfg.Spam.HoldThreshold)
+	}
+	if cfg.Spam.HoldThreshold > 0 {
+		f.threshold = cfg.Spam.HoldThreshold
+	}
+	if cfg.Spam.NewAccountAge != "" {
+		var err error
+		f.newAge, err = time.ParseDuration(cfg.Spam.NewAccountAge)
+		if err != nil {
+			return nil, fmt.Errorf("spam: config invalid spam.new_account_age value: %w", err)
+		}
+	}
+	return f, nil
+}
+
+// Score scores a comment of author on a post. Signals which can't be read,
+// ie. while the node is down, are skipped rather than failing the comment.
+func (f *Filter) Score(ctx context.Context, author string, postID int32, content string) (*Verdict, error) {
+	v := &Verdict{}
+	if f.disabled {
+		return v, nil
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(content), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '.' && r != '/' && r != ':'
+	})
+	links := linkRe.FindAllString(content, -1)
+
+	list, err := f.getBlocklist(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if list.matchWords(content) {
+		v.add(1, ReasonBlockedWord)
+	}
+	if list.matchLinks(links) {
+		v.add(1, ReasonBlockedURL)
+	}
+
+	// A bare link scores 0.6, a link in a sentence about 0.25.
+	if len(links) > 0 {
+		density := float64(len(links)) / float64(len(words)+1)
+		v.add(min(0.15*float64(len(links))+density, 0.6), ReasonLinks)
+	}
+
+	repeated, err := data.DB.CountRepeatedComments(ctx, sqlc.CountRepeatedCommentsParams{
+		Author:  author,
+		Content: content,
+		PostID:  postID,
+		Since:   time.Now().UTC().Add(-repeatWindow),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if repeated > 0 {
+		v.add(min(0.3*float64(repeated), 0.6), ReasonRepeated)
+	}
+
+	user, err := data.DB.GetUser(ctx, author)
+	if err != nil && !errors.Is(err, data.ErrNoRows) {
+		return nil, err
+	}
+	if err == nil && user.CreatedAt.Valid && time.Since(user.CreatedAt.Time) < f.newAge {
+		v.add(0.3, ReasonNewAccount)
+	}
+
+	if txs, ok := f.walletTxs(ctx, author); ok && txs == 0 {
+		v.add(0.3, ReasonNoHistory)
+	}
+
+	// Reputation ranges from -100 to 100, and moves the score by up to 1.
+	rep, err := data.DB.GetReputation(ctx, author)
+	if err != nil && !errors.Is(err, data.ErrNoRows) {
+		return nil, err
+	}
+	if rep < 0 {
+		v.add(float64(-rep)/100, ReasonLowReputation)
+	} else {
+		v.Score -= float64(rep) / 100
+	}
+
+	v.Hold = v.Score >= f.threshold
+	return v, nil
+}
+
+// walletTxs returns the number of transactions sent from the wallet of
+// account, if the node can tell.
+func (f *Filter) walletTxs(ctx context.Context, account string) (uint64, bool) {
+	if !f.reader.IsConfigured() {
+		return 0, false
+	}
+
+	f.mu.Lock()
+	w, ok := f.wallets[account]
+	f.mu.Unlock()
+	if ok && time.Since(w.fetchedAt) < walletTTL {
+		return w.txs, true
+	}
+
+	txs, err := f.reader.TransactionCount(ctx, account)
+	if err != nil {
+		f.log.Warn().Err(err).Str("account", account).Msg("failed to read wallet history")
+		return 0, false
+	}
+
+	f.mu.Lock()
+	if len(f.wallets) >= maxWallets {
+		f.wallets = map[string]wallet{}
+	}
+	f.wallets[account] = wallet{txs: txs, fetchedAt: time.Now()}
+	f.mu.Unlock()
+	return txs, true
+}
+
+func min(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// host returns the lowercase host of a link found in content.
+func host(link string) string {
+	if !strings.Contains(link, "://") {
+		link = "http://" + link
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
@@ -0,0 +1,22 @@
+package tasks
+
+import (
+	"context"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+)
+
+type ReconcilePostCountersArgs struct{}
+
+// reconcilePostCounters recounts the likes and comments of every post,
+// fixing the denormalized counters on posts if they drifted.
+func (t *Tasks) reconcilePostCounters(ctx context.Context, args ReconcilePostCountersArgs) error {
+	n, err := data.DB.ReconcilePostCounters(ctx)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		t.log.Warn().Int64("posts", n).Msg("fixed drifted post counters")
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package tasks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/nfteseum/nfteseum-learning-project/api/emails"
+	"github.com/nfteseum/nfteseum-learning-project/api/jobs"
+)
+
+// digestSlack lets digests go out a little early, so the hourly schedule
+// doesn't push them back an hour each time.
+const digestSlack = time.Hour
+
+type ScheduleDigestsArgs struct{}
+
+// scheduleDigests enqueues a digest for every account which is due one.
+func (t *Tasks) scheduleDigests(ctx context.Context, args ScheduleDigestsArgs) error {
+	now := time.Now()
+	due, err := data.DB.ListDueDigests(ctx, sqlc.ListDueDigestsParams{
+		DailyBefore:  now.Add(-24*time.Hour + digestSlack),
+		WeeklyBefore: now.Add(-7*24*time.Hour + digestSlack),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, d := range due {
+		since := d.DigestSentAt.Time
+		if !d.DigestSentAt.Valid {
+			since = now.Add(-digestPeriod(d.Digest))
+		}
+		_, err := t.queue.Enqueue(ctx, SendDigest, SendDigestArgs{Account: d.Addr, Since: since}, &jobs.EnqueueOptions{
+			UniqueKey: "digest:" + d.Addr,
+		})
+		if err != nil && !errors.Is(err, jobs.ErrDuplicate) {
+			return err
+		}
+	}
+	return nil
+}
+
+type SendDigestArgs struct {
+	Account string    `json:"account"`
+	Since   time.Time `json:"since"`
+}
+
+// sendDigest mails the digest of an account, unless it unsubscribed in the
+// meantime. Accounts without activity get no mail.
+func (t *Tasks) sendDigest(ctx context.Context, args SendDigestArgs) error {
+	user, err := data.DB.GetUser(ctx, args.Account)
+	if errors.Is(err, data.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.Digest == emails.DigestOff || !user.Email.Valid || !user.EmailVerifiedAt.Valid {
+		return nil
+	}
+
+	now := time.Now()
+	msg, err := t.emails.Digest(ctx, user.Addr, user.Email.String, user.Digest, args.Since)
+	if err != nil {
+		return err
+	}
+	if msg != nil {
+		if err := t.mail.Send(ctx, msg); err != nil {
+			return err
+		}
+	}
+
+	return data.DB.MarkDigestSent(ctx, sqlc.MarkDigestSentParams{
+		Addr:         user.Addr,
+		DigestSentAt: sql.NullTime{Time: now, Valid: true},
+	})
+}
+
+type SendVerificationEmailArgs struct {
+	Account string `json:"account"`
+	Email   string `json:"email"`
+}
+
+// sendVerificationEmail mails the verification link of an address, unless
+// the account changed it or verified it in the meantime.
+func (t *Tasks) sendVerificationEmail(ctx context.Context, args SendVerificationEmailArgs) error {
+	user, err := data.DB.GetUser(ctx, args.Account)
+	if errors.Is(err, data.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.Email.String != args.Email || user.EmailVerifiedAt.Valid {
+		return nil
+	}
+
+	msg, err := t.emails.Verification(user.Addr, args.Email)
+	if err != nil {
+		return jobs.Permanent(err)
+	}
+	return t.mail.Send(ctx, msg)
+}
+
+func digestPeriod(frequency string) time.Duration {
+	if frequency == emails.DigestWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
@@ -0,0 +1,23 @@
+package tasks
+
+import (
+	"context"
+	"time"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+)
+
+type PruneNotificationsArgs struct{}
+
+// pruneNotifications deletes the notifications without activity for longer
+// than the retention period.
+func (t *Tasks) pruneNotifications(ctx context.Context, args PruneNotificationsArgs) error {
+	n, err := data.DB.PruneNotifications(ctx, time.Now().Add(-t.notificationRetention))
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		t.log.Info().Int64("notifications", n).Msg("pruned notifications")
+	}
+	return nil
+}
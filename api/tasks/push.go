@@ -0,0 +1,45 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/jobs"
+	"github.com/nfteseum/nfteseum-learning-project/api/push"
+)
+
+// sendPush delivers a message to a push subscription. Expired subscriptions
+// are deleted, and rejected messages aren't retried unless the push service
+// is throttling or failing.
+func (t *Tasks) sendPush(ctx context.Context, args push.SendArgs) error {
+	sub, err := data.DB.GetPushSubscription(ctx, args.SubscriptionID)
+	if errors.Is(err, data.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(args.Message)
+	if err != nil {
+		return jobs.Permanent(err)
+	}
+
+	err = t.push.Send(ctx, &push.Subscription{
+		Endpoint: sub.Endpoint,
+		P256dh:   sub.P256dh,
+		Auth:     sub.Auth,
+	}, payload, args.Message.Tag)
+
+	var statusErr *push.StatusError
+	switch {
+	case errors.Is(err, push.ErrGone):
+		t.log.Info().Int64("subscription", sub.ID).Msg("deleting expired push subscription")
+		return data.DB.DeletePushSubscriptionByID(ctx, sub.ID)
+	case errors.As(err, &statusErr) && !statusErr.Temporary():
+		return jobs.Permanent(err)
+	}
+	return err
+}
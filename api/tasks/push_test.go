@@ -0,0 +1,127 @@
+package tasks
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/nfteseum/nfteseum-learning-project/api/config"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/nfteseum/nfteseum-learning-project/api/push"
+	"github.com/rs/zerolog"
+)
+
+// fakeDB serves a single push subscription, and records the statements
+// executed.
+type fakeDB struct {
+	sub  sqlc.PushSubscriptions
+	exec []string
+}
+
+func (db *fakeDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	db.exec = append(db.exec, sql)
+	return pgconn.CommandTag("DELETE 1"), nil
+}
+
+func (db *fakeDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return nil, errors.New("fakeDB: unexpected query")
+}
+
+func (db *fakeDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return fakeRow(func(dest ...interface{}) error {
+		if !strings.Contains(sql, "GetPushSubscription") || args[0] != db.sub.ID {
+			return pgx.ErrNoRows
+		}
+		*dest[0].(*int64) = db.sub.ID
+		*dest[1].(*string) = db.sub.Account
+		*dest[2].(*string) = db.sub.Endpoint
+		*dest[3].(*string) = db.sub.P256dh
+		*dest[4].(*string) = db.sub.Auth
+		*dest[5].(*time.Time) = db.sub.CreatedAt
+		return nil
+	})
+}
+
+type fakeRow func(dest ...interface{}) error
+
+func (r fakeRow) Scan(dest ...interface{}) error {
+	return r(dest...)
+}
+
+func TestSendPush(t *testing.T) {
+	public, private, err := push.GenerateVAPIDKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config.Config{Mode: config.DevelopmentMode}
+	cfg.Push.VAPIDPublicKey = public
+	cfg.Push.VAPIDPrivateKey = private
+	cfg.Push.Subject = "mailto:ops@example.com"
+	sender, err := push.New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := make([]byte, 16)
+	if _, err := rand.Read(auth); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(db *sqlc.Queries) { data.DB = db }(data.DB)
+
+	for _, tc := range []struct {
+		status  int
+		deleted bool
+		err     bool
+	}{
+		{status: http.StatusCreated},
+		{status: http.StatusNotFound, deleted: true},
+		{status: http.StatusGone, deleted: true},
+		{status: http.StatusBadRequest, err: true},
+		{status: http.StatusServiceUnavailable, err: true},
+	} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tc.status)
+		}))
+
+		db := &fakeDB{sub: sqlc.PushSubscriptions{
+			ID:        7,
+			Account:   "0x1111111111111111111111111111111111111111",
+			Endpoint:  srv.URL + "/push/7",
+			P256dh:    base64.RawURLEncoding.EncodeToString(elliptic.Marshal(elliptic.P256(), key.X, key.Y)),
+			Auth:      base64.RawURLEncoding.EncodeToString(auth),
+			CreatedAt: time.Now(),
+		}}
+		data.DB = sqlc.New(db)
+
+		tasks := &Tasks{log: zerolog.Nop(), push: sender}
+		err := tasks.sendPush(context.Background(), push.SendArgs{
+			SubscriptionID: 7,
+			Message:        &push.Message{Title: "New follower", Tag: "follows"},
+		})
+		srv.Close()
+
+		if (err != nil) != tc.err {
+			t.Errorf("%d: err = %v, want error %v", tc.status, err, tc.err)
+		}
+		deleted := len(db.exec) == 1 && strings.Contains(db.exec[0], "DeletePushSubscriptionByID")
+		if deleted != tc.deleted {
+			t.Errorf("%d: subscription deleted = %v, want %v", tc.status, deleted, tc.deleted)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+// Package tasks holds the background jobs of the api, run by the jobs queue.
+package tasks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/chain"
+	"github.com/nfteseum/nfteseum-learning-project/api/config"
+	"github.com/nfteseum/nfteseum-learning-project/api/emails"
+	"github.com/nfteseum/nfteseum-learning-project/api/jobs"
+	"github.com/nfteseum/nfteseum-learning-project/api/links"
+	"github.com/nfteseum/nfteseum-learning-project/api/mail"
+	"github.com/nfteseum/nfteseum-learning-project/api/push"
+	"github.com/nfteseum/nfteseum-learning-project/api/tokens"
+	"github.com/nfteseum/nfteseum-learning-project/api/webhooks"
+	"github.com/rs/zerolog"
+)
+
+// Job kinds.
+const (
+	ReconcilePostCounters  = "reconcile_post_counters"
+	PruneNotifications     = "prune_notifications"
+	ScheduleDigests        = "schedule_digests"
+	SendDigest             = "send_digest"
+	SendVerificationEmail  = "send_verification_email"
+	PruneWebhookDeliveries = "prune_webhook_deliveries"
+)
+
+type Tasks struct {
+	log      zerolog.Logger
+	queue    *jobs.Queue
+	mail     mail.Sender
+	emails   *emails.Builder
+	push     *push.Sender
+	webhooks *webhooks.Client
+	tokens   *tokens.Fetcher
+
+	notificationRetention time.Duration
+	webhookRetention      time.Duration
+	metadataRefresh       time.Duration
+}
+
+// Register adds the job handlers and schedules of the api to q.
+func Register(cfg *config.Config, q *jobs.Queue, sender mail.Sender, log zerolog.Logger) error {
+	builder, err := emails.NewBuilder(cfg, links.New(cfg))
+	if err != nil {
+		return err
+	}
+
+	t := &Tasks{
+		log:    log.With().Str("ps", "tasks").Logger(),
+		queue:  q,
+		mail:   sender,
+		emails: builder,
+
+		notificationRetention: 90 * 24 * time.Hour,
+		webhookRetention:      30 * 24 * time.Hour,
+		metadataRefresh:       7 * 24 * time.Hour,
+	}
+	if cfg.Notifications.Retention != "" {
+		t.notificationRetention, err = time.ParseDuration(cfg.Notifications.Retention)
+		if err != nil {
+			return fmt.Errorf("tasks: config invalid notifications.retention value: %w", err)
+		}
+	}
+	if cfg.Webhooks.Retention != "" {
+		t.webhookRetention, err = time.ParseDuration(cfg.Webhooks.Retention)
+		if err != nil {
+			return fmt.Errorf("tasks: config invalid webhooks.retention value: %w", err)
+		}
+	}
+	if cfg.Chain.MetadataRefresh != "" {
+		t.metadataRefresh, err = time.ParseDuration(cfg.Chain.MetadataRefresh)
+		if err != nil {
+			return fmt.Errorf("tasks: config invalid chain.metadata_refresh value: %w", err)
+		}
+	}
+	t.webhooks, err = webhooks.NewClient(cfg)
+	if err != nil {
+		return err
+	}
+
+	jobs.Register(q, ReconcilePostCounters, jobs.HandlerOptions{MaxAttempts: 3, Timeout: 10 * time.Minute}, t.reconcilePostCounters)
+	if err := q.Schedule("@hourly", ReconcilePostCounters, nil); err != nil {
+		return err
+	}
+
+	jobs.Register(q, PruneNotifications, jobs.HandlerOptions{MaxAttempts: 3, Timeout: 10 * time.Minute}, t.pruneNotifications)
+	if err := q.Schedule("@daily", PruneNotifications, nil); err != nil {
+		return err
+	}
+
+	jobs.Register(q, ScheduleDigests, jobs.HandlerOptions{MaxAttempts: 3}, t.scheduleDigests)
+	if err := q.Schedule("@hourly", ScheduleDigests, nil); err != nil {
+		return err
+	}
+	jobs.Register(q, SendDigest, jobs.HandlerOptions{MaxAttempts: 5, Timeout: 2 * time.Minute}, t.sendDigest)
+	jobs.Register(q, SendVerificationEmail, jobs.HandlerOptions{MaxAttempts: 5, Timeout: 2 * time.Minute}, t.sendVerificationEmail)
+
+	if push.Enabled(cfg) {
+		t.push, err = push.New(cfg)
+		if err != nil {
+			return err
+		}
+		jobs.Register(q, push.SendJob, jobs.HandlerOptions{MaxAttempts: 5, Timeout: time.Minute}, t.sendPush)
+	}
+
+	jobs.Register(q, webhooks.DeliverJob, jobs.HandlerOptions{MaxAttempts: 10, Timeout: time.Minute}, t.deliverWebhook)
+	jobs.Register(q, PruneWebhookDeliveries, jobs.HandlerOptions{MaxAttempts: 3, Timeout: 10 * time.Minute}, t.pruneWebhookDeliveries)
+	if err := q.Schedule("@daily", PruneWebhookDeliveries, nil); err != nil {
+		return err
+	}
+
+	// Token metadata is read from the chain.
+	if reader := chain.NewReader(cfg); reader.IsConfigured() {
+		t.tokens, err = tokens.NewFetcher(cfg, reader)
+		if err != nil {
+			return err
+		}
+		jobs.Register(q, tokens.SyncJob, jobs.HandlerOptions{MaxAttempts: 3}, t.syncTokenMetadata)
+		if err := q.Schedule("@hourly", tokens.SyncJob, nil); err != nil {
+			return err
+		}
+		jobs.Register(q, tokens.FetchJob, jobs.HandlerOptions{MaxAttempts: 3, Timeout: time.Minute}, t.fetchTokenMetadata)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package tasks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/nfteseum/nfteseum-learning-project/api/jobs"
+	"github.com/nfteseum/nfteseum-learning-project/api/tokens"
+)
+
+const (
+	// maxTokensPerSync caps the fetches enqueued per sync, the rest are
+	// picked up by the next ones.
+	maxTokensPerSync = 500
+
+	// tokenRetryDelay is how long a token whose metadata couldn't be
+	// fetched waits before being tried again.
+	tokenRetryDelay = 24 * time.Hour
+)
+
+type SyncTokenMetadataArgs struct{}
+
+// syncTokenMetadata enqueues the fetch of the metadata of the tokens which
+// were never fetched, or were fetched before the refresh period.
+func (t *Tasks) syncTokenMetadata(ctx context.Context, args SyncTokenMetadataArgs) error {
+	now := time.Now().UTC()
+	stale, err := data.DB.ListStaleTokens(ctx, sqlc.ListStaleTokensParams{
+		StaleBefore: now.Add(-t.metadataRefresh),
+		RetryBefore: now.Add(-tokenRetryDelay),
+		MaxTokens:   maxTokensPerSync,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, token := range stale {
+		_, err := t.queue.Enqueue(ctx, tokens.FetchJob, tokens.FetchArgs{Contract: token.ContractAddr, TokenID: token.TokenID}, &jobs.EnqueueOptions{
+			UniqueKey: fmt.Sprintf("token:%s:%d", token.ContractAddr, token.TokenID),
+		})
+		if err != nil && !errors.Is(err, jobs.ErrDuplicate) {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchTokenMetadata fetches and saves the metadata of a token, which
+// reindexes it. Failures are recorded so the token waits before being tried
+// again by a sync, and only transient ones are retried by the queue.
+func (t *Tasks) fetchTokenMetadata(ctx context.Context, args tokens.FetchArgs) error {
+	m, err := t.tokens.Fetch(ctx, args.Contract, args.TokenID)
+	if err != nil {
+		dbErr := data.DB.SaveTokenMetadataError(ctx, sqlc.SaveTokenMetadataErrorParams{
+			ContractAddr: args.Contract,
+			TokenID:      args.TokenID,
+			Error:        sql.NullString{String: err.Error(), Valid: true},
+		})
+		if dbErr != nil {
+			t.log.Error().Err(dbErr).Str("contract", args.Contract).Int32("token", args.TokenID).Msg("failed to record token metadata error")
+		}
+		var metadataErr *tokens.MetadataError
+		if errors.As(err, &metadataErr) {
+			return jobs.Permanent(err)
+		}
+		return err
+	}
+
+	return data.DB.SaveTokenMetadata(ctx, sqlc.SaveTokenMetadataParams{
+		ContractAddr: args.Contract,
+		TokenID:      args.TokenID,
+		Name:         sql.NullString{String: m.Name, Valid: m.Name != ""},
+		Description:  sql.NullString{String: m.Description, Valid: m.Description != ""},
+		Image:        sql.NullString{String: m.Image, Valid: m.Image != ""},
+	})
+}
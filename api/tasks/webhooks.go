@@ -0,0 +1,67 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/nfteseum/nfteseum-learning-project/api/jobs"
+	"github.com/nfteseum/nfteseum-learning-project/api/webhooks"
+)
+
+type PruneWebhookDeliveriesArgs struct{}
+
+// deliverWebhook posts a payload to a webhook. Failed attempts are retried
+// with the backoff of the queue, and counted against the webhook, which is
+// disabled on too many consecutive failures.
+func (t *Tasks) deliverWebhook(ctx context.Context, args webhooks.DeliverArgs) error {
+	hook, err := data.DB.GetWebhook(ctx, args.WebhookID)
+	if errors.Is(err, data.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !hook.Enabled {
+		// Disabled since the delivery was enqueued.
+		return nil
+	}
+
+	_, err = t.webhooks.Deliver(ctx, &hook, args.Payload)
+	if err == nil {
+		return data.DB.RecordWebhookSuccess(ctx, hook.ID)
+	}
+	var deliveryErr *webhooks.DeliveryError
+	if !errors.As(err, &deliveryErr) {
+		return err
+	}
+
+	enabled, dbErr := data.DB.RecordWebhookFailure(ctx, sqlc.RecordWebhookFailureParams{
+		MaxFailures: t.webhooks.MaxFailures(),
+		ID:          hook.ID,
+	})
+	if dbErr != nil {
+		t.log.Error().Err(dbErr).Int64("webhook", hook.ID).Msg("failed to record webhook failure")
+		return err
+	}
+	if !enabled {
+		t.log.Warn().Int64("webhook", hook.ID).Str("owner", hook.Owner).Msg("disabled failing webhook")
+		return jobs.Permanent(err)
+	}
+	return err
+}
+
+// pruneWebhookDeliveries deletes the delivery log older than the retention
+// period.
+func (t *Tasks) pruneWebhookDeliveries(ctx context.Context, args PruneWebhookDeliveriesArgs) error {
+	n, err := data.DB.PruneWebhookDeliveries(ctx, time.Now().Add(-t.webhookRetention))
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		t.log.Info().Int64("deliveries", n).Msg("pruned webhook deliveries")
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+// Package tokens fetches the metadata of the tokens of the posts from their
+// tokenURI, to index their names and descriptions for search.
+package tokens
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/chain"
+	"github.com/nfteseum/nfteseum-learning-project/api/config"
+	"github.com/nfteseum/nfteseum-learning-project/api/egress"
+)
+
+// Job kinds, handled in the tasks package.
+const (
+	SyncJob  = "sync_token_metadata"
+	FetchJob = "fetch_token_metadata"
+)
+
+const (
+	// maxMetadataSize caps the metadata documents read.
+	maxMetadataSize = 256 << 10
+
+	maxNameLength        = 256
+	maxDescriptionLength = 4000
+	maxImageLength       = 2048
+)
+
+// FetchArgs are the arguments of a FetchJob.
+type FetchArgs struct {
+	Contract string `json:"contract"`
+	TokenID  int32  `json:"tokenID"`
+}
+
+// Metadata holds the fields of ERC-721 metadata JSON documents which are
+// indexed.
+type Metadata struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Image       string `json:"image"`
+}
+
+// MetadataError is returned when the metadata of a token can't be read from
+// its uri. Retrying soon won't help.
+type MetadataError struct {
+	URI string
+	Err error
+}
+
+func (e *MetadataError) Error() string {
+	return fmt.Sprintf("tokens: invalid metadata at %q: %v", e.URI, e.Err)
+}
+
+func (e *MetadataError) Unwrap() error {
+	return e.Err
+}
+
+type Fetcher struct {
+	reader  *chain.Reader
+	client  *http.Client
+	gateway string
+}
+
+func NewFetcher(cfg *config.Config, reader *chain.Reader) (*Fetcher, error) {
+	f := &Fetcher{
+		reader:  reader,
+		gateway: "https://ipfs.io",
+	}
+	if cfg.Chain.IPFSGateway != "" {
+		u, err := url.Parse(cfg.Chain.IPFSGateway)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return nil, fmt.Errorf("tokens: config invalid chain.ipfs_gateway value %q", cfg.Chain.IPFSGateway)
+		}
+		f.gateway = strings.TrimSuffix(cfg.Chain.IPFSGateway, "/")
+	}
+
+	timeout := 15 * time.Second
+	f.client = &http.Client{
+		Timeout:   timeout,
+		Transport: egress.Transport(timeout, cfg.Mode == config.DevelopmentMode),
+	}
+	return f, nil
+}
+
+// Fetch reads the tokenURI of a token and returns its metadata. Metadata
+// which can't be read or decoded is reported with a *MetadataError.
+func (f *Fetcher) Fetch(ctx context.Context, contract string, tokenID int32) (*Metadata, error) {
+	id := big.NewInt(int64(tokenID))
+	uri, err := f.reader.TokenURI(ctx, contract, id)
+	if err != nil {
+		return nil, err
+	}
+	// ERC-1155 style uris hold the token id in hex.
+	uri = strings.ReplaceAll(strings.TrimSpace(uri), "{id}", fmt.Sprintf("%064x", id))
+
+	body, err := f.read(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	var m Metadata
+	if err := json.Unmarshal(body, &m); err != nil {
+		return nil, &MetadataError{URI: uri, Err: err}
+	}
+	m.Name = clean(m.Name, maxNameLength)
+	m.Description = clean(m.Description, maxDescriptionLength)
+	m.Image = clean(m.Image, maxImageLength)
+	return &m, nil
+}
+
+// read returns the document at uri: an http(s) url, an "ipfs://" uri read
+// through the gateway, or a "data:" uri.
+func (f *Fetcher) read(ctx context.Context, uri string) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(uri, "data:"):
+		return readData(uri)
+	case strings.HasPrefix(uri, "ipfs://"):
+		path := strings.TrimPrefix(strings.TrimPrefix(uri, "ipfs://"), "ipfs/")
+		return f.get(ctx, uri, f.gateway+"/ipfs/"+path)
+	case strings.HasPrefix(uri, "https://"), strings.HasPrefix(uri, "http://"):
+		return f.get(ctx, uri, uri)
+	}
+	return nil, &MetadataError{URI: uri, Err: errors.New("unsupported uri scheme")}
+}
+
+func (f *Fetcher) get(ctx context.Context, uri, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, &MetadataError{URI: uri, Err: err}
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "nfteseum-metadata")
+
+	resp, err := f.client.Do(req)
+	if errors.Is(err, egress.ErrPrivateAddress) {
+		return nil, &MetadataError{URI: uri, Err: err}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tokens: fetching %q failed: %w", uri, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+		return nil, fmt.Errorf("tokens: fetching %q failed with status %d", uri, resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &MetadataError{URI: uri, Err: fmt.Errorf("status %d", resp.StatusCode)}
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxMetadataSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("tokens: fetching %q failed: %w", uri, err)
+	}
+	if len(body) > maxMetadataSize {
+		return nil, &MetadataError{URI: uri, Err: errors.New("document too large")}
+	}
+	return body, nil
+}
+
+// readData decodes a "data:[<mediatype>][;base64],<data>" uri.
+func readData(uri string) ([]byte, error) {
+	header, payload, ok := strings.Cut(strings.TrimPrefix(uri, "data:"), ",")
+	if !ok {
+		return nil, &MetadataError{URI: "data:", Err: errors.New("malformed data uri")}
+	}
+	var (
+		body []byte
+		err  error
+	)
+	if strings.HasSuffix(header, ";base64") {
+		body, err = base64.StdEncoding.DecodeString(payload)
+	} else {
+		var s string
+		s, err = url.PathUnescape(payload)
+		body = []byte(s)
+	}
+	if err != nil {
+		return nil, &MetadataError{URI: "data:", Err: err}
+	}
+	if len(body) > maxMetadataSize {
+		return nil, &MetadataError{URI: "data:", Err: errors.New("document too large")}
+	}
+	return body, nil
+}
+
+// clean trims s to n runes, as Postgres text holds neither invalid UTF-8 nor
+// NUL bytes.
+func clean(s string, n int) string {
+	s = strings.TrimSpace(strings.ToValidUTF8(strings.ReplaceAll(s, "\x00", ""), "\uFFFD"))
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
@@ -0,0 +1,179 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/go-chi/httplog"
+	"github.com/nfteseum/nfteseum-learning-project/api"
+	"github.com/nfteseum/nfteseum-learning-project/api/config"
+	"github.com/nfteseum/nfteseum-learning-project/api/proto"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/nfteseum/nfteseum-learning-project/api"
+
+// Tracer returns the tracer used for the spans created by this service.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Setup installs the global tracer provider and W3C trace-context propagator
+// according to the [tracing] config. The returned function flushes pending
+// spans and must be called on shutdown.
+//
+// When no exporter is configured spans are still created, so trace ids are
+// propagated and logged, but they're not exported anywhere.
+func Setup(cfg *config.Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Tracing.Exporter {
+	case "":
+	case "stdout":
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case "otlp":
+		opts := []otlptracehttp.Option{}
+		if cfg.Tracing.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.Tracing.Endpoint))
+		}
+		if cfg.Tracing.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(context.Background(), opts...)
+	default:
+		return nil, fmt.Errorf("tracing: unknown exporter %q", cfg.Tracing.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tracing: failed to create exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceNameKey.String(cfg.Service.Name),
+		semconv.ServiceVersionKey.String(api.GITCOMMIT),
+		semconv.DeploymentEnvironmentKey.String(cfg.Service.Mode),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("tracing: failed to create resource: %w", err)
+	}
+
+	ratio := cfg.Tracing.SampleRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+	tp := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+
+	if exporter == nil {
+		// Without span processors the provider fails to shut down, and
+		// there's nothing to flush anyway.
+		return func(context.Context) error { return nil }, nil
+	}
+	return tp.Shutdown, nil
+}
+
+// HTTP starts a server span per request, continuing the trace of the caller
+// if the request carries a traceparent header.
+func HTTP(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, "http",
+		otelhttp.WithSpanNameFormatter(func(operation string, r *http.Request) string {
+			return r.Method + " " + r.URL.Path
+		}),
+	)
+}
+
+// LogTraceID adds the trace and span ids of the request to its httplog entry,
+// so log lines can be correlated with traces. It must come after both the
+// HTTP and httplog.RequestLogger middlewares.
+func LogTraceID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sc := trace.SpanContextFromContext(r.Context())
+		if sc.IsValid() {
+			httplog.LogEntrySetFields(r.Context(), map[string]interface{}{
+				"traceID": sc.TraceID().String(),
+				"spanID":  sc.SpanID().String(),
+			})
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RPC starts a span per webrpc method call. It must wrap the handler mounted
+// at /rpc/*.
+func RPC(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method := strings.TrimPrefix(r.URL.Path, proto.APIPathPrefix)
+		ctx, span := Tracer().Start(r.Context(), "API."+method,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.RPCSystemKey.String("webrpc"),
+				semconv.RPCServiceKey.String("API"),
+				semconv.RPCMethodKey.String(method),
+			),
+		)
+		defer span.End()
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		span.SetAttributes(semconv.HTTPStatusCodeKey.Int(rec.status))
+		if rec.status >= 500 {
+			span.SetStatus(codes.Error, http.StatusText(rec.status))
+		}
+	})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Transport wraps base so outbound requests, ie. to chain nodes or metadata
+// servers, create client spans and propagate the trace context. A nil base
+// uses http.DefaultTransport.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base)
+}
+
+// RecordError marks span as failed with err, unless err is nil.
+func RecordError(span trace.Span, err error, attrs ...attribute.KeyValue) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err, trace.WithAttributes(attrs...))
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,161 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgtype"
+	"github.com/nfteseum/nfteseum-learning-project/api/config"
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/nfteseum/nfteseum-learning-project/api/egress"
+)
+
+// maxResponseLength caps the response bodies kept in the delivery log.
+const maxResponseLength = 1024
+
+// DeliveryError is returned when a webhook didn't accept a payload, either
+// because it couldn't be reached or didn't respond with a 2xx status.
+type DeliveryError struct {
+	Status int
+	Err    error
+}
+
+func (e *DeliveryError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("webhooks: delivery failed: %v", e.Err)
+	}
+	return fmt.Sprintf("webhooks: endpoint responded %d", e.Status)
+}
+
+func (e *DeliveryError) Unwrap() error {
+	return e.Err
+}
+
+type Client struct {
+	client      *http.Client
+	dev         bool
+	maxFailures int32
+}
+
+func NewClient(cfg *config.Config) (*Client, error) {
+	c := &Client{
+		dev:         cfg.Mode == config.DevelopmentMode,
+		maxFailures: 20,
+	}
+
+	if cfg.Webhooks.MaxFailures < 0 {
+		return nil, fmt.Errorf("webhooks: config invalid webhooks.max_failures value %d", cfg.Webhooks.MaxFailures)
+	}
+	if cfg.Webhooks.MaxFailures > 0 {
+		c.maxFailures = int32(cfg.Webhooks.MaxFailures)
+	}
+
+	timeout := 10 * time.Second
+	if cfg.Webhooks.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(cfg.Webhooks.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("webhooks: config invalid webhooks.timeout value: %w", err)
+		}
+	}
+
+	c.client = &http.Client{
+		Timeout:   timeout,
+		Transport: egress.Transport(timeout, c.dev),
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			// Redirects count as failures, the url should be updated.
+			return http.ErrUseLastResponse
+		},
+	}
+	return c, nil
+}
+
+// MaxFailures is the number of consecutive failed attempts after which a
+// webhook is disabled.
+func (c *Client) MaxFailures() int32 {
+	return c.maxFailures
+}
+
+// ValidURL reports whether u can be registered as a webhook: an https url,
+// or any http url in development mode.
+func (c *Client) ValidURL(u string) bool {
+	parsed, err := url.Parse(u)
+	if err != nil || parsed.Host == "" || parsed.User != nil || len(u) > 2048 {
+		return false
+	}
+	return parsed.Scheme == "https" || (parsed.Scheme == "http" && c.dev)
+}
+
+// Deliver posts p to hook and logs the attempt in the delivery log. It
+// returns the logged delivery, and a *DeliveryError if the webhook didn't
+// accept the payload.
+func (c *Client) Deliver(ctx context.Context, hook *sqlc.Webhooks, p *Payload) (*sqlc.WebhookDeliveries, error) {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	status, response, deliveryErr := c.post(ctx, hook, p, body)
+	elapsed := time.Since(start)
+
+	params := sqlc.CreateWebhookDeliveryParams{
+		WebhookID:  hook.ID,
+		DeliveryID: p.ID,
+		EventType:  p.Type,
+		Payload:    pgtype.JSONB{Bytes: body, Status: pgtype.Present},
+		StatusCode: sql.NullInt32{Int32: int32(status), Valid: status != 0},
+		Response:   sql.NullString{String: response, Valid: status != 0},
+		DurationMs: int32(elapsed.Milliseconds()),
+		Success:    deliveryErr == nil,
+	}
+	if deliveryErr != nil {
+		params.Error = sql.NullString{String: deliveryErr.Error(), Valid: true}
+	}
+
+	delivery, err := data.DB.CreateWebhookDelivery(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	if deliveryErr != nil {
+		return &delivery, deliveryErr
+	}
+	return &delivery, nil
+}
+
+// post sends body to hook, and returns the response status and the start of
+// the response body.
+func (c *Client) post(ctx context.Context, hook *sqlc.Webhooks, p *Payload, body []byte) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", &DeliveryError{Err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "nfteseum-webhooks")
+	req.Header.Set(EventHeader, p.Type)
+	req.Header.Set(DeliveryHeader, p.ID)
+	req.Header.Set(SignatureHeader, Sign(hook.Secret, time.Now(), body))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, "", &DeliveryError{Err: err}
+	}
+	defer resp.Body.Close()
+
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseLength))
+	// Postgres text holds neither invalid UTF-8 nor NUL bytes.
+	response := strings.ToValidUTF8(strings.ReplaceAll(string(msg), "\x00", ""), "\uFFFD")
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, response, &DeliveryError{Status: resp.StatusCode}
+	}
+	return resp.StatusCode, response, nil
+}
@@ -0,0 +1,177 @@
+// Package webhooks delivers the activity on the posts of a contract to the
+// webhooks partners registered for it, ie. the Discord bot of a collection.
+//
+// Payloads are signed with the secret of their webhook. Failed deliveries are
+// retried with backoff by the jobs queue, and a webhook is disabled once its
+// consecutive failures reach webhooks.max_failures.
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/nfteseum/nfteseum-learning-project/api/data"
+	"github.com/nfteseum/nfteseum-learning-project/api/data/sqlc"
+	"github.com/nfteseum/nfteseum-learning-project/api/events"
+	"github.com/nfteseum/nfteseum-learning-project/api/jobs"
+	"github.com/rs/zerolog"
+)
+
+// Job kind of the deliveries, handled in the tasks package.
+const DeliverJob = "deliver_webhook"
+
+// TestEvent is the type of the payloads sent by SendTestWebhook.
+const TestEvent = "webhook.test"
+
+// Headers of the deliveries.
+const (
+	// SignatureHeader holds "t=<unix time>,v1=<hex signature>", see Sign.
+	SignatureHeader = "X-Nfteseum-Signature"
+
+	// EventHeader holds the type of the payload.
+	EventHeader = "X-Nfteseum-Event"
+
+	// DeliveryHeader holds the id of the payload, the same for all its
+	// attempts so receivers can dedupe them.
+	DeliveryHeader = "X-Nfteseum-Delivery"
+)
+
+// Types are the event types webhooks can subscribe to, to subscribe the
+// dispatcher with.
+var Types = []events.Type{
+	events.PostLiked,
+	events.PostUnliked,
+	events.CommentCreated,
+}
+
+// ValidType reports whether webhooks can subscribe to events of typ.
+func ValidType(typ string) bool {
+	for _, t := range Types {
+		if string(t) == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// DeliverArgs are the arguments of a DeliverJob.
+type DeliverArgs struct {
+	WebhookID int64    `json:"webhookID"`
+	Payload   *Payload `json:"payload"`
+}
+
+// Payload is the body posted to a webhook.
+type Payload struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	Contract  string          `json:"contract"`
+	Actor     string          `json:"actor,omitempty"`
+	Data      json.RawMessage `json:"data"`
+	CreatedAt time.Time       `json:"createdAt"`
+}
+
+// Dispatcher enqueues the delivery of the events it's handed by the relay to
+// the webhooks subscribed to them.
+type Dispatcher struct {
+	log   zerolog.Logger
+	queue *jobs.Queue
+}
+
+func NewDispatcher(log zerolog.Logger, queue *jobs.Queue) *Dispatcher {
+	return &Dispatcher{
+		log:   log.With().Str("ps", "webhooks").Logger(),
+		queue: queue,
+	}
+}
+
+// Handle is the events.Handler of the dispatcher. Deliveries are deduped per
+// event and webhook, so redelivered events aren't enqueued twice while
+// pending.
+func (d *Dispatcher) Handle(ctx context.Context, ev *events.Event) error {
+	contract, err := contractOf(ev)
+	if err != nil {
+		// Retrying won't fix a malformed payload.
+		d.log.Error().Err(err).Int64("eventID", ev.ID).Msg("skipping event")
+		return nil
+	}
+	if contract == "" {
+		// Events written before their payload held the contract.
+		return nil
+	}
+
+	hooks, err := data.DB.ListEventWebhooks(ctx, sqlc.ListEventWebhooksParams{
+		ContractAddr: contract,
+		EventType:    string(ev.Type),
+	})
+	if err != nil {
+		return err
+	}
+
+	payload := &Payload{
+		ID:        "evt_" + strconv.FormatInt(ev.ID, 10),
+		Type:      string(ev.Type),
+		Contract:  contract,
+		Actor:     ev.Actor,
+		Data:      ev.Data,
+		CreatedAt: ev.CreatedAt,
+	}
+	for _, hook := range hooks {
+		_, err := d.queue.Enqueue(ctx, DeliverJob, DeliverArgs{WebhookID: hook.ID, Payload: payload}, &jobs.EnqueueOptions{
+			UniqueKey: fmt.Sprintf("webhook:%d:%d", ev.ID, hook.ID),
+		})
+		if err != nil && !errors.Is(err, jobs.ErrDuplicate) {
+			return err
+		}
+	}
+	return nil
+}
+
+// contractOf returns the contract of the post ev is about.
+func contractOf(ev *events.Event) (string, error) {
+	switch ev.Type {
+	case events.PostLiked, events.PostUnliked:
+		var p events.PostLike
+		if err := ev.Decode(&p); err != nil {
+			return "", err
+		}
+		return p.Contract, nil
+
+	case events.CommentCreated:
+		var p events.Comment
+		if err := ev.Decode(&p); err != nil {
+			return "", err
+		}
+		return p.Contract, nil
+	}
+	return "", nil
+}
+
+// Sign returns the signature of body sent at t, as set in SignatureHeader:
+// the hex encoded HMAC-SHA256 of "<unix time>.<body>" keyed with secret.
+// Receivers should recompute it, and reject stale timestamps to prevent
+// replays.
+func Sign(secret string, t time.Time, body []byte) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "t=" + ts + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewSecret returns a random signing secret for a new webhook.
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}